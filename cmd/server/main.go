@@ -1,24 +1,43 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strings"
 	"syscall"
 
+	portfolio "github.com/adamdeleeuw/ssh-portfolio"
+	"github.com/adamdeleeuw/ssh-portfolio/internal/content"
 	"github.com/adamdeleeuw/ssh-portfolio/internal/ssh"
 	"github.com/charmbracelet/log"
 )
 
 /**
  * Main entry point for the SSH portfolio server.
- * Loads configuration and starts the SSH server.
+ * Loads configuration and starts the SSH server, or runs a subcommand.
  */
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "export-resume" {
+		if err := exportResume(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, "export-resume:", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "validate" {
+		os.Exit(validate(os.Args[2:]))
+	}
+
 	// Set up structured logging
 	log.SetLevel(log.InfoLevel)
 	log.SetReportTimestamp(true)
 
+	// Fall back to the content compiled into the binary
+	content.SetDefaults(portfolio.DefaultContent())
+
 	// Load configuration
 	cfg := ssh.LoadConfig()
 
@@ -27,6 +46,7 @@ func main() {
 		"port", cfg.Port,
 		"hostKeyPath", cfg.HostKeyPath,
 		"rateLimit", fmt.Sprintf("%d/min", cfg.MaxPerMinute),
+		"contentDir", cfg.ContentDir,
 	)
 
 	// Handle graceful shutdown
@@ -36,6 +56,7 @@ func main() {
 	go func() {
 		<-sigChan
 		log.Info("Shutdown signal received, stopping server...")
+		ssh.Shutdown()
 		os.Exit(0)
 	}()
 
@@ -44,3 +65,73 @@ func main() {
 		log.Fatal("Failed to start server", "error", err)
 	}
 }
+
+/**
+ * Prints the resume in JSON Resume or markdown format.
+ * Usage: export-resume [-format json|markdown] [path/to/resume.json]
+ * @param args - Command-line arguments after the subcommand name
+ * @return error if the resume cannot be loaded or the format is unknown
+ */
+func exportResume(args []string) error {
+	fs := flag.NewFlagSet("export-resume", flag.ContinueOnError)
+	format := fs.String("format", "json", "output format: json or markdown")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	path := "./content/resume.json"
+	if fs.NArg() > 0 {
+		path = fs.Arg(0)
+	}
+
+	resume, err := content.LoadResume(path)
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "json":
+		data, err := resume.JSON()
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(data)
+		return err
+	case "markdown", "md":
+		_, err := fmt.Print(resume.Markdown())
+		return err
+	}
+
+	return fmt.Errorf("unknown format %q (want json or markdown)", *format)
+}
+
+/**
+ * Checks the content directory and prints one line per problem.
+ * Usage: validate [content-dir] (default: $CONTENT_DIR or ./content)
+ * @param args - Command-line arguments after the subcommand name
+ * @return Exit code: 0 if clean, 1 if problems were found, 2 on error
+ */
+func validate(args []string) int {
+	cfg := ssh.LoadConfig()
+	dir := cfg.ContentDir
+	content.AllowRepos(cfg.ActivityRepos)
+	if len(args) > 0 {
+		dir = args[0]
+	}
+
+	problems, err := content.Validate(dir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "validate:", err)
+		return 2
+	}
+
+	for _, p := range problems {
+		fmt.Println(filepath.Join(dir, p.File) + strings.TrimPrefix(p.String(), p.File))
+	}
+	if len(problems) > 0 {
+		fmt.Fprintf(os.Stderr, "%d problem(s) found in %s\n", len(problems), dir)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "%s: no problems found\n", dir)
+	return 0
+}
@@ -0,0 +1,22 @@
+// Package portfolio holds files compiled into the server binary.
+package portfolio
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed content
+var content embed.FS
+
+/**
+ * Returns the content shipped with the binary, rooted so that
+ * welcome.md, about.md, ... are at the top level.
+ */
+func DefaultContent() fs.FS {
+	fsys, err := fs.Sub(content, "content")
+	if err != nil {
+		panic(err) // "content" is always a valid path
+	}
+	return fsys
+}
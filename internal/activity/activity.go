@@ -0,0 +1,200 @@
+// Package activity summarises commit activity in local git
+// repositories: commits per day, languages touched and recent commit
+// messages. It only reads local history and never touches the network.
+package activity
+
+import (
+	"context"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adamdeleeuw/ssh-portfolio/internal/extproc"
+)
+
+// Limits for one git log run; a year of history in a large repository
+// can be several megabytes of numstat output
+const (
+	gitTimeout   = 30 * time.Second
+	gitMaxOutput = 32 << 20
+)
+
+// Recent commits kept in a report
+const maxRecent = 10
+
+/**
+ * Commit activity across a set of repositories.
+ */
+type Report struct {
+	Days      map[string]int // Commits per day, keyed "2006-01-02" (local time)
+	Commits   int            // Total commits
+	Repos     int            // Repositories scanned successfully
+	Languages []Language     // Lines changed per language, most first
+	Recent    []Commit       // Latest commits, newest first
+	Failed    []string       // Names of repositories that could not be read
+}
+
+/**
+ * Lines changed in one language.
+ */
+type Language struct {
+	Name  string
+	Lines int
+}
+
+/**
+ * A commit in the report.
+ */
+type Commit struct {
+	Repo    string // Repository name (directory name without .git)
+	Hash    string
+	Date    time.Time
+	Subject string
+}
+
+/**
+ * Reads commit history from local repositories. Repositories that
+ * cannot be read (missing, not a repository, timed out) are listed in
+ * Failed; the rest are still counted.
+ * @param ctx - Cancels the scan
+ * @param repos - Paths of working trees or bare repositories
+ * @param authors - Only count commits whose author matches one of these
+ *                  (name or email, as git log --author); empty for all
+ * @param since - Ignore commits older than this
+ * @return Activity summary
+ */
+func Scan(ctx context.Context, repos, authors []string, since time.Time) Report {
+	r := Report{Days: make(map[string]int)}
+	langs := make(map[string]int)
+
+	for _, repo := range repos {
+		commits, err := scanRepo(ctx, repo, authors, since, langs)
+		if err != nil {
+			r.Failed = append(r.Failed, repoName(repo))
+			continue
+		}
+		r.Repos++
+		for _, c := range commits {
+			r.Days[c.Date.Local().Format("2006-01-02")]++
+			r.Commits++
+		}
+		r.Recent = append(r.Recent, commits...)
+	}
+
+	sort.SliceStable(r.Recent, func(i, j int) bool { return r.Recent[i].Date.After(r.Recent[j].Date) })
+	if len(r.Recent) > maxRecent {
+		r.Recent = r.Recent[:maxRecent]
+	}
+
+	for name, lines := range langs {
+		r.Languages = append(r.Languages, Language{Name: name, Lines: lines})
+	}
+	sort.Slice(r.Languages, func(i, j int) bool {
+		if r.Languages[i].Lines != r.Languages[j].Lines {
+			return r.Languages[i].Lines > r.Languages[j].Lines
+		}
+		return r.Languages[i].Name < r.Languages[j].Name
+	})
+	return r
+}
+
+/**
+ * Runs git log on one repository.
+ * @param langs - Lines changed per language, added to in place
+ * @return Commits, newest first
+ * @return error if the repository cannot be read
+ */
+func scanRepo(ctx context.Context, repo string, authors []string, since time.Time, langs map[string]int) ([]Commit, error) {
+	args := []string{"git", "-C", repo, "log", "--no-merges", "--no-color", "--numstat",
+		"--since=" + since.Format(time.RFC3339),
+		"--pretty=format:%x1e%H%x1f%aI%x1f%s"}
+	for _, a := range authors {
+		args = append(args, "--author="+a)
+	}
+
+	out, err := extproc.Run(ctx, extproc.Command{Args: args, Timeout: gitTimeout, MaxOutput: gitMaxOutput})
+	if err != nil {
+		return nil, err
+	}
+	return parseLog(string(out), repoName(repo), langs), nil
+}
+
+/**
+ * Parses git log output in the format produced by scanRepo.
+ */
+func parseLog(out, repo string, langs map[string]int) []Commit {
+	var commits []Commit
+	for _, record := range strings.Split(out, "\x1e") {
+		header, stats, _ := strings.Cut(record, "\n")
+		fields := strings.SplitN(header, "\x1f", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		date, err := time.Parse(time.RFC3339, fields[1])
+		if err != nil {
+			continue
+		}
+		commits = append(commits, Commit{Repo: repo, Hash: fields[0], Date: date, Subject: fields[2]})
+
+		for _, line := range strings.Split(stats, "\n") {
+			parts := strings.SplitN(line, "\t", 3)
+			if len(parts) != 3 {
+				continue
+			}
+			added, err1 := strconv.Atoi(parts[0])
+			deleted, err2 := strconv.Atoi(parts[1])
+			if err1 != nil || err2 != nil {
+				continue // Binary file
+			}
+			if lang := languageOf(parts[2]); lang != "" {
+				langs[lang] += added + deleted
+			}
+		}
+	}
+	return commits
+}
+
+/**
+ * Names a repository after its directory, without a .git suffix.
+ */
+func repoName(repo string) string {
+	name := filepath.Base(filepath.Clean(repo))
+	if name == ".git" {
+		name = filepath.Base(filepath.Dir(filepath.Clean(repo)))
+	}
+	return strings.TrimSuffix(name, ".git")
+}
+
+// Languages by file extension
+var extensions = map[string]string{
+	".go": "Go", ".c": "C", ".h": "C", ".cpp": "C++", ".cc": "C++", ".hpp": "C++",
+	".java": "Java", ".kt": "Kotlin", ".py": "Python", ".rb": "Ruby", ".rs": "Rust",
+	".js": "JavaScript", ".jsx": "JavaScript", ".mjs": "JavaScript",
+	".ts": "TypeScript", ".tsx": "TypeScript", ".swift": "Swift", ".cs": "C#",
+	".sh": "Shell", ".bash": "Shell", ".zsh": "Shell", ".lua": "Lua", ".zig": "Zig",
+	".hs": "Haskell", ".ml": "OCaml", ".ex": "Elixir", ".exs": "Elixir",
+	".php": "PHP", ".sql": "SQL", ".html": "HTML", ".css": "CSS", ".scss": "CSS",
+	".md": "Markdown", ".v": "Verilog", ".sv": "SystemVerilog", ".vhd": "VHDL",
+	".s": "Assembly", ".asm": "Assembly",
+}
+
+/**
+ * Guesses the language of a path from git's numstat output, which
+ * writes renames as "old => new" or "dir/{old => new}.ext".
+ * @return Language name, or "" for files that are not code
+ */
+func languageOf(p string) string {
+	if i := strings.LastIndex(p, "=> "); i >= 0 {
+		p = strings.Replace(p[i+3:], "}", "", 1)
+	}
+	switch path.Base(p) {
+	case "Dockerfile":
+		return "Dockerfile"
+	case "Makefile":
+		return "Makefile"
+	}
+	return extensions[strings.ToLower(path.Ext(p))]
+}
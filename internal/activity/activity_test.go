@@ -0,0 +1,109 @@
+package activity
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+/**
+ * Tests parsing git log output with numstat lines.
+ */
+func TestParseLog(t *testing.T) {
+	out := "\x1eaaa\x1f2026-03-01T10:00:00+01:00\x1fAdd parser\n" +
+		"10\t2\tparser/parse.go\n" +
+		"-\t-\tlogo.png\n" +
+		"3\t0\tREADME.md\n" +
+		"\n\x1ebbb\x1f2026-02-28T09:00:00Z\x1fRename\n" +
+		"1\t1\tsrc/{old => new}.py\n"
+
+	langs := make(map[string]int)
+	commits := parseLog(out, "demo", langs)
+
+	if len(commits) != 2 || commits[0].Hash != "aaa" || commits[0].Subject != "Add parser" || commits[1].Repo != "demo" {
+		t.Fatalf("Unexpected commits %+v", commits)
+	}
+	if langs["Go"] != 12 || langs["Markdown"] != 3 || langs["Python"] != 2 || len(langs) != 3 {
+		t.Errorf("Unexpected languages %v", langs)
+	}
+}
+
+/**
+ * Tests guessing languages from paths.
+ */
+func TestLanguageOf(t *testing.T) {
+	tests := map[string]string{
+		"main.go":            "Go",
+		"web/App.TSX":        "TypeScript",
+		"build/Dockerfile":   "Dockerfile",
+		"a.go => b.rs":       "Rust",
+		"lib/{x => y}/mod.c": "C",
+		"assets/logo.png":    "",
+		"LICENSE":            "",
+	}
+	for path, want := range tests {
+		if got := languageOf(path); got != want {
+			t.Errorf("languageOf(%q) = %q, expected %q", path, got, want)
+		}
+	}
+}
+
+/**
+ * Tests scanning real repositories, filtering by author and reporting
+ * unreadable ones.
+ */
+func TestScan(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	repo := filepath.Join(t.TempDir(), "portfolio")
+	runGit(t, "", "init", "-q", repo)
+	commit(t, repo, "me@example.com", "main.go", "package main\n\nfunc main() {}\n", "Initial commit")
+	commit(t, repo, "other@example.com", "util.py", "x = 1\n", "Add helper")
+	commit(t, repo, "me@example.com", "README.md", "# Demo\n", "Write readme")
+
+	missing := filepath.Join(t.TempDir(), "missing")
+	r := Scan(context.Background(), []string{repo, missing}, []string{"me@example.com"}, time.Now().AddDate(-1, 0, 0))
+
+	if r.Repos != 1 || r.Commits != 2 || len(r.Failed) != 1 || r.Failed[0] != "missing" {
+		t.Fatalf("Unexpected report %+v", r)
+	}
+	if r.Days[time.Now().Format("2006-01-02")] != 2 {
+		t.Errorf("Expected 2 commits today, got %v", r.Days)
+	}
+	if len(r.Recent) != 2 || r.Recent[0].Repo != "portfolio" {
+		t.Errorf("Unexpected recent commits %+v", r.Recent)
+	}
+	if len(r.Languages) != 2 || r.Languages[0].Name != "Go" || r.Languages[0].Lines != 3 {
+		t.Errorf("Unexpected languages %+v", r.Languages)
+	}
+}
+
+/**
+ * Writes a file and commits it as the given author.
+ */
+func commit(t *testing.T, repo, email, name, body, subject string) {
+	if err := os.WriteFile(filepath.Join(repo, name), []byte(body), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, repo, "add", "-A")
+	runGit(t, repo, "-c", "user.name=test", "-c", "user.email="+email, "commit", "-q", "-m", subject)
+}
+
+/**
+ * Runs a git command, failing the test on error.
+ */
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	if dir != "" {
+		args = append([]string{"-C", dir}, args...)
+	}
+	if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+		t.Fatalf("git %s failed: %v\n%s", strings.Join(args, " "), err, out)
+	}
+}
@@ -0,0 +1,316 @@
+package content
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/adamdeleeuw/ssh-portfolio/internal/activity"
+	"github.com/adamdeleeuw/ssh-portfolio/internal/tui"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Weeks shown in the contribution calendar
+const calendarWeeks = 53
+
+// Languages listed before the rest are grouped as "Other"
+const maxLanguages = 6
+
+// Paths or globs outside the content directory that activity tabs may scan
+var allowedRepos atomic.Pointer[[]string]
+
+/**
+ * Lets activity tabs scan repositories outside the content directory.
+ * Without this, a content repository can only point activity tabs at
+ * repositories inside itself, not at other paths on the server.
+ * @param patterns - Paths or globs (as for filepath.Match) repositories must match
+ */
+func AllowRepos(patterns []string) {
+	allowedRepos.Store(&patterns)
+}
+
+/**
+ * Builds the tab for an activity entry in the manifest. The repositories
+ * are rescanned on the tab's interval; the result is shared by all
+ * sessions. Scans run git, so they follow the same opt-in as commands.
+ * @param spec - Checked manifest entry
+ * @param contentDir - Directory relative repository paths start from
+ * @return Tab driven by an interval panel
+ */
+func activityTab(spec tabSpec, contentDir string) tui.Tab {
+	if !commandsEnabled.Load() {
+		return tui.Tab{
+			Name:    spec.Name,
+			Content: fmt.Sprintf("Content for %s is unavailable.\n\nActivity tabs are disabled on this server.", spec.Name),
+		}
+	}
+
+	key := activityKey(spec)
+	return tui.Tab{Name: spec.Name, Panel: &intervalPanel{
+		load: func() (string, error) {
+			return cachedResult(key, spec.interval, func() (string, error) {
+				repos := resolveRepos(spec.Repos, contentDir)
+				now := time.Now()
+				report := activity.Scan(context.Background(), repos, spec.Authors, now.AddDate(-1, 0, 0))
+				if report.Repos == 0 {
+					return "", errors.New("no readable git repositories")
+				}
+				return renderActivity(report, now, layoutWidth), nil
+			})
+		},
+		interval: spec.interval,
+	}}
+}
+
+/**
+ * Identifies the shared scan of an activity entry.
+ */
+func activityKey(spec tabSpec) string {
+	return "activity\x00" + strings.Join(spec.Repos, "\x00") + "\x00" + strings.Join(spec.Authors, "\x00")
+}
+
+/**
+ * Expands the repository patterns of an activity tab. Matches outside
+ * the content directory are left out unless AllowRepos permits them.
+ * @param patterns - Paths or globs, relative to the content directory unless absolute
+ * @param contentDir - Content directory
+ * @return Matching directories, each once, in pattern order
+ */
+func resolveRepos(patterns []string, contentDir string) []string {
+	var repos []string
+	seen := make(map[string]bool)
+	for _, p := range patterns {
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(contentDir, p)
+		}
+		matches, _ := filepath.Glob(p)
+		for _, m := range matches {
+			if !repoAllowed(m, contentDir) {
+				continue
+			}
+			if info, err := os.Stat(m); err == nil && info.IsDir() && !seen[m] {
+				seen[m] = true
+				repos = append(repos, m)
+			}
+		}
+	}
+	return repos
+}
+
+/**
+ * Whether an activity tab may scan a directory: one inside the content
+ * directory, or one the operator allowed.
+ */
+func repoAllowed(dir, contentDir string) bool {
+	dir, _ = filepath.Abs(dir)
+	base, _ := filepath.Abs(contentDir)
+	if rel, err := filepath.Rel(base, dir); err == nil && filepath.IsLocal(rel) {
+		return true
+	}
+	if allowed := allowedRepos.Load(); allowed != nil {
+		for _, pattern := range *allowed {
+			pattern, _ = filepath.Abs(pattern)
+			if ok, _ := filepath.Match(pattern, dir); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+/**
+ * Renders an activity report: contribution calendar, languages and
+ * recent commits.
+ * @param r - Scanned activity
+ * @param now - Last day of the calendar
+ * @param width - Available width
+ * @return Rendered page
+ */
+func renderActivity(r activity.Report, now time.Time, width int) string {
+	summary := fmt.Sprintf("%d %s in the last year across %d %s",
+		r.Commits, plural(r.Commits, "commit", "commits"), r.Repos, plural(r.Repos, "repository", "repositories"))
+
+	blocks := []string{
+		titleStyle.Render("ACTIVITY"),
+		subtitleStyle.Render(summary),
+		"",
+		renderCalendar(r.Days, now, width),
+	}
+
+	if len(r.Languages) > 0 {
+		blocks = append(blocks, sectionStyle.Render("Languages"), renderLanguages(r.Languages, width))
+	}
+
+	if len(r.Recent) > 0 {
+		blocks = append(blocks, sectionStyle.Render("Recent Commits"), renderRecent(r.Recent, width))
+	}
+
+	if len(r.Failed) > 0 {
+		blocks = append(blocks, "", mutedStyle.Render("Could not read: "+strings.Join(r.Failed, ", ")))
+	}
+
+	return pageStyle.Render(strings.Join(blocks, "\n"))
+}
+
+/**
+ * Renders a GitHub-style contribution calendar: one column per week,
+ * Sunday at the top, ending with the week of now. Cells are spaced out
+ * when the width allows and packed together otherwise.
+ * @param days - Commits per day, keyed "2006-01-02"
+ * @param now - Last day shown
+ * @param width - Available width
+ */
+func renderCalendar(days map[string]int, now time.Time, width int) string {
+	const labelWidth = 4
+
+	cell := "■ "
+	if labelWidth+2*calendarWeeks > width {
+		cell = "■"
+	}
+	cellWidth := len([]rune(cell))
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	start := today.AddDate(0, 0, -int(today.Weekday())-7*(calendarWeeks-1))
+
+	busiest := 0
+	for _, n := range days {
+		busiest = max(busiest, n)
+	}
+
+	// Month names above the week each month starts in
+	months := []rune(strings.Repeat(" ", labelWidth+calendarWeeks*cellWidth+3))
+	next := 0
+	for w := range calendarWeeks {
+		week := start.AddDate(0, 0, 7*w)
+		if w > 0 && week.AddDate(0, 0, 6).Day() > 7 {
+			continue
+		}
+		col := labelWidth + w*cellWidth
+		if col < next {
+			continue
+		}
+		copy(months[col:], []rune(week.AddDate(0, 0, 6).Format("Jan")))
+		next = col + 4
+	}
+	rows := []string{mutedStyle.Render(strings.TrimRight(string(months), " "))}
+
+	dayLabels := [7]string{1: "Mon", 3: "Wed", 5: "Fri"}
+	for d := range 7 {
+		var b strings.Builder
+		b.WriteString(mutedStyle.Render(fmt.Sprintf("%-*s", labelWidth, dayLabels[d])))
+		for w := range calendarWeeks {
+			day := start.AddDate(0, 0, 7*w+d)
+			if day.After(today) {
+				break
+			}
+			n := days[day.Format("2006-01-02")]
+			b.WriteString(heatStyle(heatLevel(n, busiest)).Render(cell))
+		}
+		rows = append(rows, strings.TrimRight(b.String(), " "))
+	}
+
+	legend := mutedStyle.Render("Less ")
+	for level := range heatColors {
+		legend += heatStyle(level).Render("■")
+	}
+	legend += mutedStyle.Render(" More")
+	rows = append(rows, "", strings.Repeat(" ", labelWidth)+legend)
+
+	return strings.Join(rows, "\n")
+}
+
+/**
+ * Buckets a day's commits into one of the calendar shades.
+ * @param n - Commits on the day
+ * @param busiest - Commits on the busiest day
+ * @return 0 for no commits, up to len(heatColors)-1 for the busiest days
+ */
+func heatLevel(n, busiest int) int {
+	if n <= 0 || busiest <= 0 {
+		return 0
+	}
+	steps := len(heatColors) - 1
+	return min((n*steps+busiest-1)/busiest, steps)
+}
+
+/**
+ * Style for one calendar shade.
+ */
+func heatStyle(level int) lipgloss.Style {
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(heatColors[level]))
+}
+
+/**
+ * Renders the share of changed lines per language as bars.
+ */
+func renderLanguages(langs []activity.Language, width int) string {
+	if len(langs) > maxLanguages {
+		other := activity.Language{Name: "Other"}
+		for _, l := range langs[maxLanguages-1:] {
+			other.Lines += l.Lines
+		}
+		langs = append(langs[:maxLanguages-1:maxLanguages-1], other)
+	}
+
+	total := 0
+	for _, l := range langs {
+		total += l.Lines
+	}
+	if total == 0 {
+		return mutedStyle.Render("No changes")
+	}
+
+	const labelWidth = 14
+	barWidth := max(min(width-labelWidth-8, 40), 4)
+
+	var rows []string
+	for _, l := range langs {
+		share := float64(l.Lines) / float64(total)
+		filled := int(share*float64(barWidth) + 0.5)
+		rows = append(rows, fmt.Sprintf("%s  %s%s  %s",
+			bodyStyle.Width(labelWidth).Render(l.Name),
+			meterStyle.Render(strings.Repeat("█", filled)),
+			mutedStyle.Render(strings.Repeat("░", barWidth-filled)),
+			mutedStyle.Render(fmt.Sprintf("%3.0f%%", share*100)),
+		))
+	}
+	return strings.Join(rows, "\n")
+}
+
+/**
+ * Renders the latest commits, one per line.
+ */
+func renderRecent(commits []activity.Commit, width int) string {
+	repoWidth := 0
+	for _, c := range commits {
+		repoWidth = max(repoWidth, len(c.Repo))
+	}
+	repoWidth = min(repoWidth, 20)
+
+	var rows []string
+	for _, c := range commits {
+		date := mutedStyle.Render(c.Date.Local().Format("Jan 02"))
+		repo := subtitleStyle.Width(repoWidth).Render(ansi.Truncate(stripControls(c.Repo), repoWidth, "…"))
+		subject := ansi.Truncate(stripControls(c.Subject), max(width-repoWidth-10, 10), "…")
+		rows = append(rows, date+"  "+repo+"  "+bodyStyle.Render(subject))
+	}
+	return strings.Join(rows, "\n")
+}
+
+/**
+ * Reports whether a directory is a git working tree or bare repository.
+ */
+func isGitRepo(dir string) bool {
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+		return true
+	}
+	_, errHead := os.Stat(filepath.Join(dir, "HEAD"))
+	info, errObjects := os.Stat(filepath.Join(dir, "objects"))
+	return errHead == nil && errObjects == nil && info.IsDir()
+}
@@ -0,0 +1,108 @@
+package content
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adamdeleeuw/ssh-portfolio/internal/activity"
+	"github.com/charmbracelet/x/ansi"
+)
+
+/**
+ * Tests the calendar covers a year of weeks and fits the layout width.
+ */
+func TestRenderActivity(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.Local)
+	r := activity.Report{
+		Days:    map[string]int{"2026-10-19": 4, "2026-10-18": 1, "2025-10-20": 2},
+		Commits: 7,
+		Repos:   2,
+		Languages: []activity.Language{
+			{Name: "Go", Lines: 700}, {Name: "Python", Lines: 100}, {Name: "Shell", Lines: 50},
+			{Name: "C", Lines: 40}, {Name: "Lua", Lines: 30}, {Name: "Zig", Lines: 20}, {Name: "SQL", Lines: 10},
+		},
+		Recent: []activity.Commit{{Repo: "portfolio", Date: now, Subject: "Fix \x1b[2Jthe tests"}},
+		Failed: []string{"gone"},
+	}
+
+	out := ansi.Strip(renderActivity(r, now, layoutWidth))
+	for _, want := range []string{"7 commits in the last year across 2 repositories", "Mon", "Less", "Other", "Fix the tests", "Could not read: gone"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in output:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "SQL") {
+		t.Error("Expected small languages grouped as Other")
+	}
+
+	for _, line := range strings.Split(out, "\n") {
+		if w := ansi.StringWidth(line); w > layoutWidth+4 {
+			t.Errorf("Line is %d columns wide: %q", w, line)
+		}
+	}
+
+	// Sunday row reaches the current week; Tuesday is still to come
+	cal := strings.Split(ansi.Strip(renderCalendar(r.Days, now, layoutWidth)), "\n")
+	if got := strings.Count(cal[1], "■"); got != calendarWeeks {
+		t.Errorf("Expected %d weeks, got %d", calendarWeeks, got)
+	}
+	if got := strings.Count(cal[3], "■"); got != calendarWeeks-1 {
+		t.Errorf("Expected Tuesday row to stop at last week, got %d cells", got)
+	}
+}
+
+/**
+ * Tests days are bucketed relative to the busiest day.
+ */
+func TestHeatLevel(t *testing.T) {
+	tests := []struct{ n, busiest, want int }{
+		{0, 10, 0}, {1, 10, 1}, {5, 10, 2}, {8, 10, 4}, {10, 10, 4}, {1, 1, 4},
+	}
+	for _, tt := range tests {
+		if got := heatLevel(tt.n, tt.busiest); got != tt.want {
+			t.Errorf("heatLevel(%d, %d) = %d, expected %d", tt.n, tt.busiest, got, tt.want)
+		}
+	}
+}
+
+/**
+ * Tests an activity tab scans the repositories it may: ones inside the
+ * content directory or allowed by the operator.
+ */
+func TestLoadTabs_Activity(t *testing.T) {
+	repo := initContentRepo(t)
+	dir := writeContent(t, map[string]string{
+		"tabs.json": `{"tabs": [{"name": "Activity", "repos": ["` + repo + `", "missing/*"]}]}`,
+	})
+
+	tabs, err := LoadTabs(dir)
+	if err != nil || !strings.Contains(tabs[0].Content, "disabled") {
+		t.Fatalf("Expected activity tabs to need commands enabled, got %+v (err %v)", tabs, err)
+	}
+
+	withCommands(t)
+	if repos := resolveRepos([]string{repo, filepath.Join("..", filepath.Base(repo))}, dir); len(repos) != 0 {
+		t.Errorf("Expected repositories outside the content directory to be refused, got %v", repos)
+	}
+	AllowRepos([]string{filepath.Join(filepath.Dir(repo), "*")})
+	t.Cleanup(func() { AllowRepos(nil) })
+
+	tabs, err = LoadTabs(dir)
+	if err != nil {
+		t.Fatalf("LoadTabs failed: %v", err)
+	}
+	p, ok := tabs[0].Panel.(*intervalPanel)
+	if len(tabs) != 1 || !ok || p.interval != defaultActivityInterval {
+		t.Fatalf("Expected activity panel, got %+v", tabs)
+	}
+
+	content, err := p.load()
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if !strings.Contains(ansi.Strip(content), "1 commit in the last year across 1 repository") {
+		t.Errorf("Unexpected content:\n%s", ansi.Strip(content))
+	}
+}
@@ -0,0 +1,267 @@
+package content
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Callout kinds: icon and border color
+var calloutKinds = map[string]struct {
+	icon  string
+	color string
+}{
+	"note":      {"ℹ", colorAccent},
+	"tip":       {"✦", colorGreen},
+	"important": {"★", colorHighlight},
+	"warning":   {"⚠", colorYellow},
+	"caution":   {"✖", colorRed},
+}
+
+/**
+ * Renders a boxed admonition.
+ * Fence: ```callout [note|tip|important|warning|caution] [title...]
+ */
+func renderCallout(args []string, body string, width int) (string, error) {
+	kind := "note"
+	if len(args) > 0 {
+		kind = strings.ToLower(args[0])
+	}
+
+	style, ok := calloutKinds[kind]
+	if !ok {
+		return "", fmt.Errorf("unknown callout kind %q", kind)
+	}
+
+	title := strings.ToUpper(kind[:1]) + kind[1:]
+	if len(args) > 1 {
+		title = strings.Join(args[1:], " ")
+	}
+
+	box := lipgloss.NewStyle().
+		BorderStyle(lipgloss.ThickBorder()).
+		BorderLeft(true).
+		BorderForeground(lipgloss.Color(style.color)).
+		PaddingLeft(1).
+		Width(width - 2)
+
+	heading := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(style.color)).
+		Bold(true).
+		Render(style.icon + " " + title)
+
+	return box.Render(heading + "\n" + bodyStyle.Render(strings.TrimSpace(body))), nil
+}
+
+/**
+ * Renders a horizontal bar chart.
+ * Fence: ```bars [max=N]; one "Label: value" per line.
+ * Without max, bars are scaled to the largest value.
+ */
+func renderBars(args []string, body string, width int) (string, error) {
+	type bar struct {
+		label string
+		value float64
+		raw   string
+	}
+
+	var bars []bar
+	maxValue := 0.0
+	labelWidth := 0
+
+	for n, line := range nonEmptyLines(body) {
+		label, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return "", fmt.Errorf("bar %d: expected \"label: value\"", n+1)
+		}
+		raw := strings.TrimSpace(value)
+		v, err := strconv.ParseFloat(strings.TrimSuffix(raw, "%"), 64)
+		if err != nil || v < 0 {
+			return "", fmt.Errorf("bar %d: invalid value %q", n+1, raw)
+		}
+
+		label = strings.TrimSpace(label)
+		bars = append(bars, bar{label, v, raw})
+		maxValue = max(maxValue, v)
+		labelWidth = max(labelWidth, lipgloss.Width(label))
+	}
+
+	for _, arg := range args {
+		if s, ok := strings.CutPrefix(arg, "max="); ok {
+			v, err := strconv.ParseFloat(s, 64)
+			if err != nil || v <= 0 {
+				return "", fmt.Errorf("invalid max %q", s)
+			}
+			maxValue = v
+		}
+	}
+
+	valueWidth := 0
+	for _, b := range bars {
+		valueWidth = max(valueWidth, len(b.raw))
+	}
+	barWidth := max(width-labelWidth-valueWidth-4, 4)
+
+	var rows []string
+	for _, b := range bars {
+		filled := 0
+		if maxValue > 0 {
+			filled = int(min(b.value/maxValue, 1)*float64(barWidth) + 0.5)
+		}
+		rows = append(rows, fmt.Sprintf("%s  %s%s  %s",
+			bodyStyle.Width(labelWidth).Render(b.label),
+			meterStyle.Render(strings.Repeat("█", filled)),
+			mutedStyle.Render(strings.Repeat("░", barWidth-filled)),
+			mutedStyle.Render(b.raw),
+		))
+	}
+
+	return strings.Join(rows, "\n"), nil
+}
+
+/**
+ * Renders a vertical timeline.
+ * Fence: ```timeline; one "date | title | description" per line
+ * (description optional).
+ */
+func renderTimeline(_ []string, body string, width int) (string, error) {
+	lines := nonEmptyLines(body)
+
+	dateWidth := 0
+	type event struct{ date, title, desc string }
+	var events []event
+	for n, line := range lines {
+		parts := strings.SplitN(line, "|", 3)
+		if len(parts) < 2 {
+			return "", fmt.Errorf("event %d: expected \"date | title | description\"", n+1)
+		}
+		e := event{date: strings.TrimSpace(parts[0]), title: strings.TrimSpace(parts[1])}
+		if len(parts) == 3 {
+			e.desc = strings.TrimSpace(parts[2])
+		}
+		events = append(events, e)
+		dateWidth = max(dateWidth, lipgloss.Width(e.date))
+	}
+
+	textWidth := max(width-dateWidth-5, 10)
+
+	var rows []string
+	for i, e := range events {
+		text := titleStyle.Render(e.title)
+		if e.desc != "" {
+			text += "\n" + bodyStyle.Width(textWidth).Render(e.desc)
+		}
+
+		rail := []string{meterStyle.Render("●")}
+		for n := 1; n < lipgloss.Height(text); n++ {
+			rail = append(rail, mutedStyle.Render("│"))
+		}
+		if i < len(events)-1 {
+			rail = append(rail, mutedStyle.Render("│"))
+			text += "\n"
+		}
+
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top,
+			mutedStyle.Width(dateWidth).Align(lipgloss.Right).Render(e.date),
+			"  ",
+			strings.Join(rail, "\n"),
+			"  ",
+			text,
+		))
+	}
+
+	return strings.Join(rows, "\n"), nil
+}
+
+/**
+ * Renders an indented outline as a directory tree.
+ * Fence: ```tree; nesting by two spaces or a tab per level,
+ * optional "- " list markers are ignored.
+ */
+func renderTree(_ []string, body string, _ int) (string, error) {
+	type node struct {
+		depth int
+		name  string
+	}
+
+	var nodes []node
+	for n, line := range nonEmptyLines(body) {
+		expanded := strings.ReplaceAll(line, "\t", "  ")
+		name := strings.TrimLeft(expanded, " ")
+		indent := len(expanded) - len(name)
+		if indent%2 != 0 {
+			return "", fmt.Errorf("entry %d: indent must be a multiple of two spaces", n+1)
+		}
+
+		depth := indent / 2
+		if len(nodes) == 0 && depth != 0 || len(nodes) > 0 && depth > nodes[len(nodes)-1].depth+1 {
+			return "", fmt.Errorf("entry %d: indented more than one level past its parent", n+1)
+		}
+
+		name = strings.TrimPrefix(strings.TrimPrefix(name, "- "), "* ")
+		nodes = append(nodes, node{depth, name})
+	}
+
+	// isLast reports whether no later sibling exists at the same depth
+	isLast := func(i int) bool {
+		for j := i + 1; j < len(nodes); j++ {
+			if nodes[j].depth < nodes[i].depth {
+				return true
+			}
+			if nodes[j].depth == nodes[i].depth {
+				return false
+			}
+		}
+		return true
+	}
+
+	var rows []string
+	var open []bool // open[d] is true while depth d still has siblings below
+	for i, n := range nodes {
+		last := isLast(i)
+		if n.depth == 0 {
+			rows = append(rows, titleStyle.Render(n.name))
+			open = []bool{!last}
+			continue
+		}
+
+		var prefix strings.Builder
+		for d := 1; d < n.depth; d++ {
+			if d < len(open) && open[d] {
+				prefix.WriteString("│   ")
+			} else {
+				prefix.WriteString("    ")
+			}
+		}
+		if last {
+			prefix.WriteString("└── ")
+		} else {
+			prefix.WriteString("├── ")
+		}
+
+		open = append(open[:min(n.depth, len(open))], !last)
+		style := bodyStyle
+		if strings.HasSuffix(n.name, "/") {
+			style = subtitleStyle
+		}
+		rows = append(rows, mutedStyle.Render(prefix.String())+style.Render(n.name))
+	}
+
+	return strings.Join(rows, "\n"), nil
+}
+
+/**
+ * Splits text into lines, dropping blank ones and trailing whitespace.
+ */
+func nonEmptyLines(s string) []string {
+	var out []string
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if strings.TrimSpace(line) != "" {
+			out = append(out, line)
+		}
+	}
+	return out
+}
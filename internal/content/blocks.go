@@ -0,0 +1,164 @@
+package content
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+/**
+ * Renders the body of a custom fenced block as styled terminal output.
+ * @param args - Words after the language in the fence info string
+ * @param body - Raw block contents
+ * @param width - Available width in cells
+ * @return Rendered block
+ * @return error if the block contents are malformed
+ */
+type BlockRenderer func(args []string, body string, width int) (string, error)
+
+// Fence languages with a custom renderer; everything else is left to glamour
+var blockRenderers = map[string]BlockRenderer{
+	"callout":  renderCallout,
+	"bars":     renderBars,
+	"timeline": renderTimeline,
+	"tree":     renderTree,
+}
+
+/**
+ * Registers a renderer for a fence language, replacing any existing one.
+ * Must be called before content is loaded (e.g. from an init function).
+ * @param lang - Fence language, e.g. "chart" for ```chart blocks
+ * @param r - Renderer for the block body
+ */
+func RegisterBlock(lang string, r BlockRenderer) {
+	blockRenderers[strings.ToLower(lang)] = r
+}
+
+// Prefix of the placeholder paragraphs that stand in for custom blocks
+// while glamour renders the page. Letters and digits only, so glamour
+// passes it through untouched.
+const blockPlaceholder = "sshportfolioblock"
+
+/**
+ * Replaces custom fenced blocks with placeholder paragraphs and renders them.
+ * Regular code blocks (including ones containing custom fences) are untouched.
+ * @param md - Markdown source
+ * @param width - Available width for rendered blocks
+ * @return Markdown with placeholders
+ * @return Rendered blocks keyed by placeholder
+ * @return error naming the line of the first malformed block
+ */
+func extractBlocks(md string, width int) (string, map[string]string, error) {
+	lines := strings.Split(md, "\n")
+	blocks := make(map[string]string)
+
+	var out []string
+	for i := 0; i < len(lines); i++ {
+		fence, info, ok := parseFence(lines[i])
+		if !ok {
+			out = append(out, lines[i])
+			continue
+		}
+
+		// Find the matching closing fence
+		end := len(lines)
+		for j := i + 1; j < len(lines); j++ {
+			if isClosingFence(lines[j], fence) {
+				end = j
+				break
+			}
+		}
+
+		fields := strings.Fields(info)
+		var renderer BlockRenderer
+		if len(fields) > 0 {
+			renderer = blockRenderers[strings.ToLower(fields[0])]
+		}
+
+		if renderer == nil {
+			// Ordinary code block: copy verbatim
+			stop := end
+			if stop == len(lines) {
+				stop = len(lines) - 1
+			}
+			out = append(out, lines[i:stop+1]...)
+			i = stop
+			continue
+		}
+
+		body := strings.Join(lines[i+1:min(end, len(lines))], "\n")
+		rendered, err := renderer(fields[1:], body, width)
+		if err != nil {
+			return "", nil, fmt.Errorf("line %d: %s block: %w", i+1, fields[0], err)
+		}
+
+		key := fmt.Sprintf("%s%d", blockPlaceholder, len(blocks))
+		blocks[key] = rendered
+		out = append(out, "", key, "")
+		i = end
+	}
+
+	return strings.Join(out, "\n"), blocks, nil
+}
+
+/**
+ * Swaps placeholder lines in glamour output for the rendered blocks.
+ * @param rendered - Glamour output
+ * @param blocks - Rendered blocks keyed by placeholder
+ * @param indent - Left margin to apply to each block line
+ * @return Page with blocks spliced in
+ */
+func spliceBlocks(rendered string, blocks map[string]string, indent int) string {
+	if len(blocks) == 0 {
+		return rendered
+	}
+
+	margin := strings.Repeat(" ", indent)
+	lines := strings.Split(rendered, "\n")
+	for i, line := range lines {
+		plain := strings.TrimSpace(ansi.Strip(line))
+		if !strings.HasPrefix(plain, blockPlaceholder) {
+			continue
+		}
+		if block, ok := blocks[plain]; ok {
+			blockLines := strings.Split(block, "\n")
+			for j := range blockLines {
+				blockLines[j] = margin + blockLines[j]
+			}
+			lines[i] = strings.Join(blockLines, "\n")
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+/**
+ * Recognizes an opening code fence (``` or ~~~, up to 3 spaces indent).
+ * @return The fence marker, the info string and whether the line is a fence
+ */
+func parseFence(line string) (string, string, bool) {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 {
+		return "", "", false
+	}
+
+	for _, ch := range []string{"`", "~"} {
+		n := 0
+		for n < len(trimmed) && trimmed[n] == ch[0] {
+			n++
+		}
+		if n >= 3 {
+			return trimmed[:n], strings.TrimSpace(trimmed[n:]), true
+		}
+	}
+	return "", "", false
+}
+
+/**
+ * Reports whether a line closes a block opened with the given fence.
+ */
+func isClosingFence(line, fence string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == ""
+}
@@ -0,0 +1,91 @@
+package content
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+/**
+ * Tests custom blocks are replaced and ordinary code blocks are kept.
+ */
+func TestExtractBlocks(t *testing.T) {
+	md := "Intro\n\n```bars\nGo: 2\n```\n\n````markdown\n```bars\nnot: rendered\n```\n````\n"
+
+	out, blocks, err := extractBlocks(md, 40)
+	if err != nil {
+		t.Fatalf("extractBlocks failed: %v", err)
+	}
+
+	if len(blocks) != 1 {
+		t.Fatalf("Expected 1 custom block, got %d", len(blocks))
+	}
+	if !strings.Contains(out, blockPlaceholder+"0") {
+		t.Error("Expected placeholder in output markdown")
+	}
+	if !strings.Contains(out, "not: rendered") {
+		t.Error("Nested fence inside a code block should be left alone")
+	}
+}
+
+/**
+ * Tests malformed block bodies report the fence line.
+ */
+func TestExtractBlocks_Error(t *testing.T) {
+	_, _, err := extractBlocks("# Title\n\n```bars\nGo: lots\n```\n", 40)
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("Expected error at line 3, got %v", err)
+	}
+}
+
+/**
+ * Tests placeholders in rendered output are swapped for the blocks.
+ */
+func TestSpliceBlocks(t *testing.T) {
+	rendered := "  text\n\x1b[38;5;252m  " + blockPlaceholder + "0  \x1b[0m\n"
+	out := spliceBlocks(rendered, map[string]string{blockPlaceholder + "0": "A\nB"}, 2)
+
+	if out != "  text\n  A\n  B\n" {
+		t.Errorf("Unexpected splice result %q", out)
+	}
+}
+
+/**
+ * Tests the tree renderer draws connectors for nested entries.
+ */
+func TestRenderTree(t *testing.T) {
+	out, err := renderTree(nil, "root/\n  a/\n    b\n  c", 40)
+	if err != nil {
+		t.Fatalf("renderTree failed: %v", err)
+	}
+
+	want := "root/\n├── a/\n│   └── b\n└── c"
+	if got := ansi.Strip(out); got != want {
+		t.Errorf("Expected tree\n%s\ngot\n%s", want, got)
+	}
+}
+
+/**
+ * Tests callout kinds are validated.
+ */
+func TestRenderCallout_UnknownKind(t *testing.T) {
+	if _, err := renderCallout([]string{"shout"}, "x", 40); err == nil {
+		t.Error("Expected error for unknown callout kind")
+	}
+}
+
+/**
+ * Tests custom renderers can be registered.
+ */
+func TestRegisterBlock(t *testing.T) {
+	RegisterBlock("Shout", func(_ []string, body string, _ int) (string, error) {
+		return strings.ToUpper(body), nil
+	})
+	defer delete(blockRenderers, "shout")
+
+	_, blocks, err := extractBlocks("```shout\nhello\n```", 40)
+	if err != nil || blocks[blockPlaceholder+"0"] != "HELLO" {
+		t.Errorf("Expected registered renderer to run, got %v (err %v)", blocks, err)
+	}
+}
@@ -0,0 +1,197 @@
+package content
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/adamdeleeuw/ssh-portfolio/internal/tui"
+	"github.com/charmbracelet/glamour"
+)
+
+// Average reading speed used for reading-time estimates
+const wordsPerMinute = 200
+
+// Optional date prefix on post file names: 2026-02-14-my-post.md
+var postFilenameDate = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})-(.+)$`)
+
+/**
+ * Loads and renders all blog posts for a session.
+ * This is the entry point for any output that lists posts,
+ * not only the Blog tab.
+ * @param contentDir - Content directory containing a blog/ subdirectory
+ * @param sess - Session values for templates
+ * @return Posts sorted newest first (empty if there is no blog directory)
+ * @return error naming the first post that fails to load
+ */
+func LoadPosts(contentDir string, sess Session) ([]tui.Post, error) {
+	renderer, err := newSessionRenderer(sess)
+	if err != nil {
+		return nil, err
+	}
+	return loadPosts(renderer, contentFS(contentDir), sess)
+}
+
+/**
+ * Loads every *.md file in the blog directory as a post.
+ * @param renderer - Glamour renderer
+ * @param fsys - Content filesystem containing a blog/ directory
+ * @param sess - Session values for templates
+ * @return Posts sorted newest first
+ * @return error naming the first post that fails to load
+ */
+func loadPosts(renderer *glamour.TermRenderer, fsys fs.FS, sess Session) ([]tui.Post, error) {
+	entries, err := fs.ReadDir(fsys, "blog")
+	if isNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read blog directory: %w", err)
+	}
+
+	var posts []tui.Post
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".md" {
+			continue
+		}
+
+		pg, err := renderFile(renderer, fsys, "blog/"+entry.Name(), sess)
+		if err == errHidden {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		post, err := newPost(entry.Name(), pg)
+		if err != nil {
+			return nil, fmt.Errorf("blog/%s: %w", entry.Name(), err)
+		}
+		posts = append(posts, post)
+	}
+
+	sort.SliceStable(posts, func(i, j int) bool {
+		return posts[i].Date.After(posts[j].Date)
+	})
+
+	return posts, nil
+}
+
+/**
+ * Builds a post from a rendered page and its file name.
+ * Title falls back to the first heading, the date to the file name prefix.
+ * @param filename - Post file name
+ * @param pg - Rendered page
+ * @return Post metadata and content
+ * @return error if the post has no usable date
+ */
+func newPost(filename string, pg page) (tui.Post, error) {
+	slug := strings.TrimSuffix(filename, path.Ext(filename))
+	dateStr := pg.meta["date"]
+
+	if m := postFilenameDate.FindStringSubmatch(slug); m != nil {
+		slug = m[2]
+		if dateStr == "" {
+			dateStr = m[1]
+		}
+	}
+
+	if dateStr == "" {
+		return tui.Post{}, fmt.Errorf("missing date (set \"date:\" in front matter or prefix the file name with YYYY-MM-DD-)")
+	}
+	date, err := parseProjectDate(dateStr)
+	if err != nil {
+		return tui.Post{}, err
+	}
+
+	title := pg.meta["title"]
+	if title == "" {
+		title = firstHeading(pg.source)
+	}
+	if title == "" {
+		title = slug
+	}
+	if label := pg.state.label(); label != "" {
+		title = "[" + label + "] " + title
+	}
+
+	return tui.Post{
+		Slug:        slug,
+		Title:       title,
+		Date:        date,
+		Tags:        metaList(pg.meta["tags"]),
+		Summary:     pg.meta["summary"],
+		ReadingTime: readingTime(pg.source),
+		Markdown:    pg.source,
+		Source:      pg.raw,
+		Content:     pg.rendered,
+		File:        "blog/" + filename,
+		Headings:    pg.headings,
+		Links:       pg.links,
+		Images:      pg.images,
+		QRCodes:     pg.qrCodes,
+	}, nil
+}
+
+/**
+ * Joins posts into one markdown document, one section per post,
+ * so the blog tab's changes can be tracked like any other page.
+ * Posts are taken before templating, so per-session values do not
+ * count as changes.
+ * @param posts - Loaded posts
+ * @return Markdown with a "## Title" section per post
+ */
+func postsSource(posts []tui.Post) string {
+	var b strings.Builder
+	for _, p := range posts {
+		// Escape the post's own headings so each post stays one section
+		lines := strings.Split(p.Source, "\n")
+		for i, line := range lines {
+			if strings.HasPrefix(line, "#") {
+				lines[i] = `\` + line
+			}
+		}
+		fmt.Fprintf(&b, "## %s\n\n%s\n", p.Title, strings.Join(lines, "\n"))
+	}
+	return b.String()
+}
+
+/**
+ * Parses a front matter list: "[a, b]" or "a, b".
+ */
+func metaList(value string) []string {
+	value = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(value), "["), "]")
+
+	var out []string
+	for _, item := range strings.Split(value, ",") {
+		if item = unquote(strings.TrimSpace(item)); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+/**
+ * Returns the text of the first ATX heading in a markdown document.
+ */
+func firstHeading(md string) string {
+	for _, line := range strings.Split(md, "\n") {
+		if strings.HasPrefix(line, "#") {
+			return strings.TrimSpace(strings.TrimLeft(line, "#"))
+		}
+	}
+	return ""
+}
+
+/**
+ * Estimates reading time in whole minutes (at least one).
+ */
+func readingTime(md string) time.Duration {
+	words := len(strings.Fields(md))
+	minutes := (words + wordsPerMinute - 1) / wordsPerMinute
+	return time.Duration(max(minutes, 1)) * time.Minute
+}
@@ -0,0 +1,120 @@
+package content
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+/**
+ * Writes blog posts into a temp content directory.
+ */
+func writeTestPosts(t *testing.T, posts map[string]string) string {
+	contentDir := t.TempDir()
+	blogDir := filepath.Join(contentDir, "blog")
+	if err := os.MkdirAll(blogDir, 0755); err != nil {
+		t.Fatalf("Failed to create blog dir: %v", err)
+	}
+	for name, body := range posts {
+		if err := os.WriteFile(filepath.Join(blogDir, name), []byte(body), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	return contentDir
+}
+
+/**
+ * Tests posts are parsed, sorted newest first and given a Blog tab.
+ */
+func TestLoadPosts(t *testing.T) {
+	contentDir := writeTestPosts(t, map[string]string{
+		"2026-01-10-first.md": "# First Post\n\nHello",
+		"second.md":           "---\ntitle: Second\ndate: 2026-02-01\ntags: [go, ssh]\nsummary: Newer\n---\nBody",
+		"notes.txt":           "ignored",
+	})
+
+	posts, err := LoadPosts(contentDir, Session{})
+	if err != nil {
+		t.Fatalf("LoadPosts failed: %v", err)
+	}
+
+	if len(posts) != 2 {
+		t.Fatalf("Expected 2 posts, got %d", len(posts))
+	}
+
+	if posts[0].Title != "Second" || len(posts[0].Tags) != 2 || posts[0].Summary != "Newer" {
+		t.Errorf("Unexpected newest post: %+v", posts[0])
+	}
+	if posts[1].Title != "First Post" || posts[1].Slug != "first" {
+		t.Errorf("Expected title from heading and slug without date, got %+v", posts[1])
+	}
+	if posts[1].ReadingTime != time.Minute {
+		t.Errorf("Expected minimum reading time of 1m, got %v", posts[1].ReadingTime)
+	}
+
+	tabs, err := LoadTabs(contentDir)
+	if err != nil {
+		t.Fatalf("LoadTabs failed: %v", err)
+	}
+	if tabs[3].Name != "Blog" || len(tabs[3].Posts) != 2 {
+		t.Errorf("Expected Blog tab before Future, got %s", tabs[3].Name)
+	}
+}
+
+/**
+ * Tests that the blog tab's source has one section per post, taken
+ * before templating, whatever headings the posts use.
+ */
+func TestPostsSource(t *testing.T) {
+	contentDir := writeTestPosts(t, map[string]string{
+		"2026-01-10-a.md": "# Post A\n\nHi {{ .Visitor }}\n\n### Details\n\nMore",
+		"2026-01-11-b.md": "# Post B\n\nBody",
+	})
+
+	posts, err := LoadPosts(contentDir, Session{User: "ada"})
+	if err != nil {
+		t.Fatalf("LoadPosts failed: %v", err)
+	}
+	source := postsSource(posts)
+
+	var headings []string
+	for _, line := range strings.Split(source, "\n") {
+		if strings.HasPrefix(line, "#") {
+			headings = append(headings, line)
+		}
+	}
+	if want := []string{"## Post B", "## Post A"}; !reflect.DeepEqual(headings, want) {
+		t.Errorf("Expected one section per post %q, got %q", want, headings)
+	}
+	if !strings.Contains(source, "{{ .Visitor }}") || strings.Contains(source, "ada") {
+		t.Errorf("Expected the source before templating, got %q", source)
+	}
+}
+
+/**
+ * Tests posts without a date are rejected.
+ */
+func TestLoadPosts_MissingDate(t *testing.T) {
+	contentDir := writeTestPosts(t, map[string]string{"undated.md": "# Undated"})
+
+	if _, err := LoadPosts(contentDir, Session{}); err == nil {
+		t.Error("Expected error for post without a date")
+	}
+}
+
+/**
+ * Tests reading time estimates.
+ */
+func TestReadingTime(t *testing.T) {
+	words := make([]byte, 0, 2*450)
+	for i := 0; i < 450; i++ {
+		words = append(words, "w "...)
+	}
+
+	if got := readingTime(string(words)); got != 3*time.Minute {
+		t.Errorf("Expected 3m for 450 words, got %v", got)
+	}
+}
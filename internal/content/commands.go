@@ -0,0 +1,472 @@
+package content
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/adamdeleeuw/ssh-portfolio/internal/extproc"
+	"github.com/adamdeleeuw/ssh-portfolio/internal/probe"
+	"github.com/adamdeleeuw/ssh-portfolio/internal/tui"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Whether manifest commands may run; off unless the operator opts in
+var commandsEnabled atomic.Bool
+
+/**
+ * Allows or forbids running the commands of command tabs. Off by
+ * default, so a content repository cannot run programs on the server
+ * unless the operator opts in.
+ * @param enabled - Whether commands may run
+ */
+func EnableCommands(enabled bool) {
+	commandsEnabled.Store(enabled)
+}
+
+/**
+ * Builds the tab for a command in the manifest.
+ * @param spec - Checked manifest entry
+ * @param contentDir - Directory the command runs in
+ * @return Tab driven by an interval or interactive panel
+ */
+func commandTab(spec tabSpec, contentDir string) tui.Tab {
+	if !commandsEnabled.Load() {
+		return tui.Tab{
+			Name:    spec.Name,
+			Content: fmt.Sprintf("Content for %s is unavailable.\n\nCommand tabs are disabled on this server.", spec.Name),
+		}
+	}
+
+	cmd := extproc.Command{
+		Args:      spec.Command,
+		Dir:       contentDir,
+		Timeout:   spec.timeout,
+		MaxOutput: spec.MaxOutput,
+	}
+	if spec.Mode == "interactive" {
+		return tui.Tab{Name: spec.Name, Panel: &interactivePanel{cmd: cmd}}
+	}
+	key := commandKey(spec)
+	return tui.Tab{Name: spec.Name, Panel: &intervalPanel{
+		load: func() (string, error) {
+			return cachedResult(key, spec.interval, func() (string, error) {
+				data, err := extproc.Run(context.Background(), cmd)
+				if err != nil {
+					return "", err
+				}
+				return renderCommandOutput(string(data), "markdown")
+			})
+		},
+		interval: spec.interval,
+	}}
+}
+
+/**
+ * Identifies the shared result of an interval command. The content
+ * directory is left out so a new export of the same manifest reuses it.
+ */
+func commandKey(spec tabSpec) string {
+	return fmt.Sprintf("command\x00%s\x00%s\x00%d", strings.Join(spec.Command, "\x00"), spec.timeout, spec.MaxOutput)
+}
+
+// Latest result of each interval panel's work, shared by all sessions
+// so a command or scan runs at most once per interval however many
+// visitors are connected
+var sharedResults = struct {
+	sync.Mutex
+	entries map[string]*sharedResult
+}{entries: make(map[string]*sharedResult)}
+
+/**
+ * Cached result of one command or scan.
+ */
+type sharedResult struct {
+	mu      sync.Mutex // Held while the result is produced
+	at      time.Time
+	content string
+	err     error
+}
+
+/**
+ * Returns a cached result, producing it again if it is older than the
+ * interval.
+ * @param key - Identifies the work, e.g. the command and its directory
+ * @param interval - Maximum age of a cached result
+ * @param produce - Does the work
+ * @return Rendered content
+ * @return error if the work failed
+ */
+func cachedResult(key string, interval time.Duration, produce func() (string, error)) (string, error) {
+	sharedResults.Lock()
+	out, ok := sharedResults.entries[key]
+	if !ok {
+		out = &sharedResult{}
+		sharedResults.entries[key] = out
+	}
+	sharedResults.Unlock()
+
+	out.mu.Lock()
+	defer out.mu.Unlock()
+
+	if !out.at.IsZero() && time.Since(out.at) < interval {
+		return out.content, out.err
+	}
+
+	out.content, out.err = produce()
+	out.at = time.Now()
+	return out.content, out.err
+}
+
+/**
+ * Drops the shared results and stops the monitors a manifest no longer
+ * uses, and moves the remaining monitors' command probes to its
+ * directory. Called when a new content version is picked up, so
+ * entries for tabs that were removed or changed do not pile up.
+ * @param specs - Tabs of the new manifest
+ * @param contentDir - Directory the new content is read from
+ */
+func retainShared(specs []tabSpec, contentDir string) {
+	keepResults := make(map[string]bool)
+	keepMonitors := make(map[string][]probe.Probe)
+	for _, spec := range specs {
+		switch {
+		case len(spec.Command) > 0:
+			keepResults[commandKey(spec)] = true
+		case len(spec.Repos) > 0:
+			keepResults[activityKey(spec)] = true
+		case len(spec.Probes) > 0:
+			probes := statusProbes(spec, contentDir)
+			keepMonitors[monitorKey(spec, probes)] = probes
+		}
+	}
+
+	sharedResults.Lock()
+	for key := range sharedResults.entries {
+		if !keepResults[key] {
+			delete(sharedResults.entries, key)
+		}
+	}
+	sharedResults.Unlock()
+
+	monitors.Lock()
+	defer monitors.Unlock()
+	for key, m := range monitors.entries {
+		if probes, ok := keepMonitors[key]; ok {
+			m.SetProbes(probes)
+			continue
+		}
+		m.Stop()
+		delete(monitors.entries, key)
+	}
+}
+
+/**
+ * Renders program output for the viewport.
+ * @param text - Output of the program
+ * @param format - "markdown" to render with glamour, anything else for text
+ * @return Content safe to write to the visitor's terminal
+ * @return error if the markdown cannot be rendered
+ */
+func renderCommandOutput(text, format string) (string, error) {
+	if format != "markdown" {
+		return sanitizeOutput(text), nil
+	}
+
+	renderer, err := newRenderer()
+	if err != nil {
+		return "", err
+	}
+	return renderer.Render(ansi.Strip(text))
+}
+
+// Colour and style sequences, the only escapes passed through from programs
+var sgrPattern = regexp.MustCompile(`\x1b\[[0-9;:]*m`)
+
+/**
+ * Removes everything but text and colour from program output, so a
+ * program cannot move the cursor, retitle the window or otherwise
+ * reach past its tab.
+ */
+func sanitizeOutput(s string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range sgrPattern.FindAllStringIndex(s, -1) {
+		b.WriteString(stripControls(s[last:loc[0]]))
+		b.WriteString(s[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	b.WriteString(stripControls(s[last:]))
+	return b.String() + "\x1b[0m"
+}
+
+/**
+ * Strips escape sequences and control characters other than newline and tab.
+ */
+func stripControls(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\t' {
+			return r
+		}
+		if r < 0x20 || r == 0x7f || (r >= 0x80 && r < 0xa0) {
+			return -1
+		}
+		return r
+	}, ansi.Strip(s))
+}
+
+/**
+ * Shows an error from a command tab.
+ */
+func commandError(err error, hint string) string {
+	msg := "⚠ " + err.Error()
+	if hint != "" {
+		msg += "\n\n" + hint
+	}
+	return pageStyle.Render(errorStyle.Width(layoutWidth).Render(msg))
+}
+
+/**
+ * Panel that reloads its content on an interval: a command's markdown
+ * output or an activity report.
+ */
+type intervalPanel struct {
+	load     func() (string, error) // Produces the rendered content
+	interval time.Duration
+
+	content string
+	err     error
+	closed  atomic.Bool // Read from load goroutines
+}
+
+// Messages of intervalPanel, tagged with the panel they belong to
+type (
+	intervalOutputMsg struct {
+		panel   *intervalPanel
+		content string
+		err     error
+	}
+	intervalTickMsg struct{ panel *intervalPanel }
+)
+
+func (p *intervalPanel) Init(width, height int) tea.Cmd {
+	return p.fetch()
+}
+
+/**
+ * Loads the content (or reuses a recent result) in the background.
+ */
+func (p *intervalPanel) fetch() tea.Cmd {
+	return func() tea.Msg {
+		if p.closed.Load() {
+			return nil
+		}
+		content, err := p.load()
+		return intervalOutputMsg{panel: p, content: content, err: err}
+	}
+}
+
+func (p *intervalPanel) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case intervalOutputMsg:
+		if msg.panel != p {
+			return nil
+		}
+		p.content, p.err = msg.content, msg.err
+		return tea.Tick(p.interval, func(time.Time) tea.Msg {
+			return intervalTickMsg{panel: p}
+		})
+	case intervalTickMsg:
+		if msg.panel == p && !p.closed.Load() {
+			return p.fetch()
+		}
+	}
+	return nil
+}
+
+func (p *intervalPanel) View() string {
+	if p.err != nil {
+		return commandError(p.err, fmt.Sprintf("Retrying every %s.", p.interval))
+	}
+	if p.content == "" {
+		return pageStyle.Render(mutedStyle.Render("Loading…"))
+	}
+	return p.content
+}
+
+func (p *intervalPanel) Interactive() bool {
+	return false
+}
+
+func (p *intervalPanel) Close() {
+	p.closed.Store(true)
+}
+
+/**
+ * Message sent to an interactive program.
+ */
+type commandEvent struct {
+	Type   string `json:"type"`             // "size" or "key"
+	Width  int    `json:"width,omitempty"`  // Viewport size, for "size"
+	Height int    `json:"height,omitempty"` // Viewport size, for "size"
+	Key    string `json:"key,omitempty"`    // Key name, e.g. "a", "up", "enter", for "key"
+}
+
+/**
+ * Message received from an interactive program.
+ */
+type commandFrame struct {
+	Type    string `json:"type"`             // "frame"; other types are ignored
+	Content string `json:"content"`          // What to show
+	Format  string `json:"format,omitempty"` // "markdown" or "text" (default)
+}
+
+/**
+ * Panel that runs a program for the session and exchanges
+ * newline-delimited JSON with it: the program receives the viewport
+ * size and key presses and answers with frames to show. The program
+ * starts when the tab is first opened and keeps running across reloads
+ * that leave its manifest entry unchanged.
+ */
+type interactivePanel struct {
+	cmd    extproc.Command
+	proc   *extproc.Process
+	width  int
+	height int
+
+	frame  string
+	err    error
+	closed bool
+}
+
+/**
+ * A frame or exit from the program.
+ */
+type interactiveMsg struct {
+	proc  *extproc.Process
+	frame string
+	err   error
+}
+
+func (p *interactivePanel) Init(width, height int) tea.Cmd {
+	p.width, p.height = width, height
+	return p.start()
+}
+
+/**
+ * Starts (or restarts) the program and sends it the viewport size.
+ */
+func (p *interactivePanel) start() tea.Cmd {
+	proc, err := extproc.Start(p.cmd)
+	if err != nil {
+		p.err = err
+		return nil
+	}
+	p.proc, p.err, p.frame = proc, nil, ""
+	p.send(commandEvent{Type: "size", Width: p.width, Height: p.height})
+	return p.next(proc)
+}
+
+/**
+ * Sends an event, stopping the program if it cannot take it.
+ */
+func (p *interactivePanel) send(ev commandEvent) {
+	data, _ := json.Marshal(ev)
+	if err := p.proc.Send(data); err != nil {
+		p.proc.Close()
+	}
+}
+
+/**
+ * Waits in the background for the program's next frame.
+ * Frames are decoded and rendered there to keep the UI responsive.
+ */
+func (p *interactivePanel) next(proc *extproc.Process) tea.Cmd {
+	return func() tea.Msg {
+		for {
+			line, err := proc.Next()
+			if err != nil {
+				return interactiveMsg{proc: proc, err: err}
+			}
+
+			var f commandFrame
+			if err := json.Unmarshal(line, &f); err != nil {
+				proc.Close()
+				return interactiveMsg{proc: proc, err: fmt.Errorf("invalid message: %w", err)}
+			}
+			if f.Type != "frame" {
+				continue
+			}
+			frame, err := renderCommandOutput(f.Content, f.Format)
+			return interactiveMsg{proc: proc, frame: frame, err: err}
+		}
+	}
+}
+
+func (p *interactivePanel) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case interactiveMsg:
+		if p.proc == nil || msg.proc != p.proc {
+			return nil
+		}
+		if msg.err != nil {
+			p.proc.Close()
+			p.err = msg.err
+			return nil
+		}
+		p.frame = msg.frame
+		return p.next(p.proc)
+
+	case tui.PanelSizeMsg:
+		p.width, p.height = msg.Width, msg.Height
+		if p.err == nil && p.proc != nil {
+			p.send(commandEvent{Type: "size", Width: msg.Width, Height: msg.Height})
+		}
+
+	case tea.KeyMsg:
+		if p.err != nil || p.proc == nil {
+			if msg.String() == "r" && !p.closed {
+				return p.start()
+			}
+			return nil
+		}
+		p.send(commandEvent{Type: "key", Key: msg.String()})
+	}
+	return nil
+}
+
+func (p *interactivePanel) View() string {
+	if errors.Is(p.err, extproc.ErrBusy) {
+		return commandError(errors.New("too many visitors are running programs right now"), "Press r to try again.")
+	}
+	if p.err != nil {
+		return commandError(fmt.Errorf("program stopped: %w", p.err), "Press r to restart.")
+	}
+	if p.frame == "" {
+		return pageStyle.Render(mutedStyle.Render("Starting…"))
+	}
+	return p.frame
+}
+
+func (p *interactivePanel) Interactive() bool {
+	return true
+}
+
+func (p *interactivePanel) Key() string {
+	return fmt.Sprintf("interactive\x00%s\x00%s\x00%s\x00%d",
+		p.cmd.Dir, strings.Join(p.cmd.Args, "\x00"), p.cmd.Timeout, p.cmd.MaxOutput)
+}
+
+func (p *interactivePanel) Close() {
+	p.closed = true
+	if p.proc != nil {
+		p.proc.Close()
+	}
+}
@@ -0,0 +1,55 @@
+package content
+
+import (
+	"fmt"
+	"strings"
+)
+
+/**
+ * Splits optional front matter from a markdown document.
+ * Front matter is a block of "key: value" lines fenced by "---" lines
+ * at the very top of the file. Keys are lower-cased; values may be quoted.
+ * @param src - Raw file contents
+ * @return Metadata map (empty if there is no front matter)
+ * @return Markdown body with the front matter removed
+ * @return error if the block is unterminated or a line is not "key: value"
+ */
+func parseFrontMatter(src string) (map[string]string, string, error) {
+	meta := make(map[string]string)
+
+	src = strings.TrimPrefix(src, "\ufeff")
+	if !strings.HasPrefix(src, "---\n") && !strings.HasPrefix(src, "---\r\n") {
+		return meta, src, nil
+	}
+
+	lines := strings.SplitAfter(src, "\n")
+	for i := 1; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], "\r\n")
+
+		if line == "---" {
+			return meta, strings.Join(lines[i+1:], ""), nil
+		}
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		key = strings.ToLower(strings.TrimSpace(key))
+		if !ok || key == "" {
+			return nil, "", fmt.Errorf("front matter line %d: expected \"key: value\", got %q", i+1, line)
+		}
+		meta[key] = unquote(strings.TrimSpace(value))
+	}
+
+	return nil, "", fmt.Errorf("front matter is not terminated by \"---\"")
+}
+
+/**
+ * Strips one pair of matching single or double quotes.
+ */
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,47 @@
+package content
+
+import "testing"
+
+/**
+ * Tests splitting front matter from the markdown body.
+ */
+func TestParseFrontMatter(t *testing.T) {
+	src := "---\ntitle: \"Hello: World\"\nRole: student\n---\n# Body\n"
+
+	meta, body, err := parseFrontMatter(src)
+	if err != nil {
+		t.Fatalf("parseFrontMatter failed: %v", err)
+	}
+
+	if meta["title"] != "Hello: World" {
+		t.Errorf("Expected quoted value with colon, got %q", meta["title"])
+	}
+	if meta["role"] != "student" {
+		t.Errorf("Expected lower-cased key 'role', got %v", meta)
+	}
+	if body != "# Body\n" {
+		t.Errorf("Unexpected body %q", body)
+	}
+}
+
+/**
+ * Tests files without front matter pass through unchanged.
+ */
+func TestParseFrontMatter_None(t *testing.T) {
+	meta, body, err := parseFrontMatter("# Title\n---\n")
+	if err != nil || len(meta) != 0 || body != "# Title\n---\n" {
+		t.Errorf("Expected passthrough, got meta=%v body=%q err=%v", meta, body, err)
+	}
+}
+
+/**
+ * Tests malformed front matter is reported.
+ */
+func TestParseFrontMatter_Invalid(t *testing.T) {
+	if _, _, err := parseFrontMatter("---\ntitle: x\n"); err == nil {
+		t.Error("Expected error for unterminated front matter")
+	}
+	if _, _, err := parseFrontMatter("---\nnot a pair\n---\n"); err == nil {
+		t.Error("Expected error for line without colon")
+	}
+}
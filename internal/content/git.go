@@ -0,0 +1,293 @@
+package content
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+/**
+ * Identifies the commit content is currently served from.
+ */
+type Version struct {
+	Commit string    // Full commit hash
+	Date   time.Time // Committer date
+}
+
+/**
+ * Short form for display, e.g. "a1b2c3d (2026-02-14)".
+ */
+func (v Version) String() string {
+	if v.Commit == "" {
+		return ""
+	}
+	return fmt.Sprintf("%.7s (%s)", v.Commit, v.Date.Format("2006-01-02"))
+}
+
+/**
+ * Content loaded from a git repository at a pinned branch or tag.
+ * Each commit is exported into its own directory under the cache
+ * directory, which is what LoadSessionTabs then reads from. Sessions
+ * Acquire an export for their lifetime; old exports are removed once
+ * the last session using them releases them.
+ */
+type GitSource struct {
+	repo     string // Path to a bare repository or working tree
+	ref      string // Branch, tag or commit to follow
+	subdir   string // Directory inside the repository holding the content ("" = root)
+	cacheDir string // Where exported commits are written
+
+	mu      sync.RWMutex
+	dir     string         // Directory of the current export
+	version Version        // Commit of the current export
+	users   map[string]int // Sessions holding each export
+}
+
+/**
+ * Creates a git content source and exports the current commit.
+ * @param repo - Repository path (bare or working tree)
+ * @param ref - Branch or tag to follow
+ * @param subdir - Content directory inside the repository ("" for the root)
+ * @param cacheDir - Writable directory for exported commits
+ * @return Ready-to-use source
+ * @return error if git is unavailable, the ref is unknown or export fails
+ */
+func NewGitSource(repo, ref, subdir, cacheDir string) (*GitSource, error) {
+	g := &GitSource{
+		repo:     repo,
+		ref:      ref,
+		subdir:   strings.Trim(filepath.ToSlash(subdir), "/"),
+		cacheDir: cacheDir,
+		users:    map[string]int{},
+	}
+	addExportRoot(cacheDir)
+
+	if _, err := g.Sync(); err != nil {
+		return nil, err
+	}
+	g.sweep()
+	return g, nil
+}
+
+/**
+ * Removes exports left in the cache directory by earlier runs, which
+ * no session can be using any more.
+ */
+func (g *GitSource) sweep() {
+	entries, err := os.ReadDir(g.cacheDir)
+	if err != nil {
+		return
+	}
+	current := g.Dir()
+	for _, e := range entries {
+		dir := filepath.Join(g.cacheDir, e.Name())
+		if e.IsDir() && isCommitHash(e.Name()) && dir != current {
+			os.RemoveAll(dir)
+		}
+	}
+}
+
+/**
+ * Whether a name is a full commit hash (SHA-1 or SHA-256), as exports
+ * are named.
+ */
+func isCommitHash(name string) bool {
+	if len(name) != 40 && len(name) != 64 {
+		return false
+	}
+	for _, r := range name {
+		if !strings.ContainsRune("0123456789abcdef", r) {
+			return false
+		}
+	}
+	return true
+}
+
+/**
+ * Returns the directory holding the current commit's content.
+ */
+func (g *GitSource) Dir() string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.dir
+}
+
+/**
+ * Returns the current export and keeps it on disk until released,
+ * even if a newer commit is picked up meanwhile.
+ * @return Directory of the export
+ * @return Function releasing it; safe to call more than once
+ */
+func (g *GitSource) Acquire() (string, func()) {
+	g.mu.Lock()
+	dir := g.dir
+	g.users[dir]++
+	g.mu.Unlock()
+
+	var once sync.Once
+	return dir, func() {
+		once.Do(func() {
+			g.mu.Lock()
+			g.users[dir]--
+			unused := g.users[dir] == 0
+			if unused {
+				delete(g.users, dir)
+			}
+			stale := unused && dir != g.dir
+			g.mu.Unlock()
+
+			if stale {
+				os.RemoveAll(dir)
+			}
+		})
+	}
+}
+
+/**
+ * Returns the commit currently being served.
+ */
+func (g *GitSource) Version() Version {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.version
+}
+
+/**
+ * Checks the ref for a new commit and exports it if there is one.
+ * Should be called periodically in a goroutine.
+ * @return true if a new commit was picked up
+ * @return error if git fails; the previous export stays in use
+ */
+func (g *GitSource) Sync() (bool, error) {
+	out, err := g.git("log", "-1", "--format=%H%n%cI", g.ref+"^{commit}", "--")
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve %s: %w", g.ref, err)
+	}
+
+	fields := strings.Fields(string(out))
+	if len(fields) != 2 {
+		return false, fmt.Errorf("unexpected git log output %q", out)
+	}
+	date, err := time.Parse(time.RFC3339, fields[1])
+	if err != nil {
+		return false, fmt.Errorf("invalid commit date %q: %w", fields[1], err)
+	}
+	version := Version{Commit: fields[0], Date: date}
+
+	if version.Commit == g.Version().Commit {
+		return false, nil
+	}
+
+	dir := filepath.Join(g.cacheDir, version.Commit)
+	if err := g.export(version.Commit, dir); err != nil {
+		os.RemoveAll(dir)
+		return false, err
+	}
+
+	g.mu.Lock()
+	old := g.dir
+	g.dir = dir
+	g.version = version
+	stale := old != "" && old != dir && g.users[old] == 0
+	g.mu.Unlock()
+
+	// Exports still held by sessions go when the last one releases them
+	if stale {
+		os.RemoveAll(old)
+	}
+
+	// Forget shared results and monitors of tabs the commit removed
+	if specs, err := loadManifest(contentFS(dir)); err == nil {
+		retainShared(specs, dir)
+	}
+	return true, nil
+}
+
+/**
+ * Writes the content tree of a commit into a directory.
+ * @param commit - Commit hash
+ * @param dir - Destination directory (replaced if it exists)
+ * @return error if git archive or extraction fails
+ */
+func (g *GitSource) export(commit, dir string) error {
+	treeish := commit
+	if g.subdir != "" {
+		treeish += ":" + g.subdir
+	}
+
+	archive, err := g.git("archive", "--format=tar", treeish)
+	if err != nil {
+		return fmt.Errorf("failed to export %s: %w", treeish, err)
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	tr := tar.NewReader(bytes.NewReader(archive))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read archive: %w", err)
+		}
+
+		// Never write outside the export directory
+		target := filepath.Join(dir, filepath.FromSlash(hdr.Name))
+		if !strings.HasPrefix(target, filepath.Clean(dir)+string(os.PathSeparator)) {
+			continue
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(target, data, 0644); err != nil {
+				return err
+			}
+			// Keep commit times so {{ .Modified }} reflects the commit
+			os.Chtimes(target, hdr.ModTime, hdr.ModTime)
+		}
+	}
+}
+
+/**
+ * Runs a git command against the repository.
+ * @return Standard output
+ * @return error including git's standard error on failure
+ */
+func (g *GitSource) git(args ...string) ([]byte, error) {
+	cmd := exec.Command("git", append([]string{"-C", g.repo}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,150 @@
+package content
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+/**
+ * Creates a git repository with a content/ directory and one commit.
+ * Skips the test if git is not installed.
+ */
+func initContentRepo(t *testing.T) string {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	repo := t.TempDir()
+	runGit(t, repo, "init", "-q", "-b", "main")
+	commitFile(t, repo, "content/welcome.md", "# Version one")
+	return repo
+}
+
+/**
+ * Writes a file into the repository and commits it.
+ */
+func commitFile(t *testing.T, repo, name, body string) {
+	path := filepath.Join(repo, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+	runGit(t, repo, "add", "-A")
+	runGit(t, repo, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "update "+name)
+}
+
+/**
+ * Runs a git command in a directory, failing the test on error.
+ */
+func runGit(t *testing.T, dir string, args ...string) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %s failed: %v\n%s", strings.Join(args, " "), err, out)
+	}
+}
+
+/**
+ * Tests exporting content from a ref and picking up new commits.
+ */
+func TestGitSource(t *testing.T) {
+	repo := initContentRepo(t)
+
+	src, err := NewGitSource(repo, "main", "content", t.TempDir())
+	if err != nil {
+		t.Fatalf("NewGitSource failed: %v", err)
+	}
+
+	first := src.Version()
+	if len(first.Commit) != 40 || first.Date.IsZero() {
+		t.Errorf("Expected commit hash and date, got %+v", first)
+	}
+
+	data, err := os.ReadFile(filepath.Join(src.Dir(), "welcome.md"))
+	if err != nil || string(data) != "# Version one" {
+		t.Fatalf("Expected exported welcome.md, got %q (err %v)", data, err)
+	}
+
+	// No new commit: nothing changes
+	if changed, err := src.Sync(); err != nil || changed {
+		t.Errorf("Expected no change, got changed=%v err=%v", changed, err)
+	}
+
+	commitFile(t, repo, "content/welcome.md", "# Version two")
+	if changed, err := src.Sync(); err != nil || !changed {
+		t.Fatalf("Expected new commit to be picked up, got changed=%v err=%v", changed, err)
+	}
+
+	if src.Version().Commit == first.Commit {
+		t.Error("Version should change after sync")
+	}
+	data, _ = os.ReadFile(filepath.Join(src.Dir(), "welcome.md"))
+	if string(data) != "# Version two" {
+		t.Errorf("Expected updated content, got %q", data)
+	}
+}
+
+/**
+ * Tests an unknown ref is reported.
+ */
+func TestGitSource_UnknownRef(t *testing.T) {
+	repo := initContentRepo(t)
+
+	if _, err := NewGitSource(repo, "does-not-exist", "", t.TempDir()); err == nil {
+		t.Error("Expected error for unknown ref")
+	}
+}
+
+/**
+ * Tests old exports stay until the last session releases them, and
+ * exports left by earlier runs are swept.
+ */
+func TestGitSource_Exports(t *testing.T) {
+	repo := initContentRepo(t)
+	cacheDir := t.TempDir()
+	orphan := filepath.Join(cacheDir, strings.Repeat("a", 40))
+	os.MkdirAll(orphan, 0755)
+
+	src, err := NewGitSource(repo, "main", "content", cacheDir)
+	if err != nil {
+		t.Fatalf("NewGitSource failed: %v", err)
+	}
+	if _, err := os.Stat(orphan); !os.IsNotExist(err) {
+		t.Error("Expected the orphaned export to be swept")
+	}
+
+	first, release1 := src.Acquire()
+	_, release2 := src.Acquire()
+	commitFile(t, repo, "content/welcome.md", "# Version two")
+	if _, err := src.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	release1()
+	release1()
+	if _, err := os.Stat(filepath.Join(first, "welcome.md")); err != nil {
+		t.Error("Expected the export to stay while a session holds it")
+	}
+	release2()
+	if _, err := os.Stat(first); !os.IsNotExist(err) {
+		t.Error("Expected the export to go with its last session")
+	}
+
+	// The current export stays without sessions
+	current, release := src.Acquire()
+	release()
+	if _, err := os.Stat(current); err != nil {
+		t.Error("Expected the current export to stay")
+	}
+
+	// Unused exports go as soon as they are replaced
+	commitFile(t, repo, "content/welcome.md", "# Version three")
+	src.Sync()
+	if _, err := os.Stat(current); !os.IsNotExist(err) {
+		t.Error("Expected the unused export to be removed")
+	}
+}
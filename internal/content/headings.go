@@ -0,0 +1,50 @@
+package content
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/adamdeleeuw/ssh-portfolio/internal/tui"
+)
+
+// Inline markdown dropped from heading text: links, emphasis and code marks
+var (
+	headingLinkPattern = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+	headingMarkPattern = regexp.MustCompile("[*_`~]+")
+)
+
+/**
+ * Lists the ATX headings of a document outside code blocks, with their
+ * inline markdown removed so they match the rendered text.
+ * @param md - Markdown after template expansion
+ * @return Headings in document order
+ */
+func pageHeadings(md string) []tui.Heading {
+	var headings []tui.Heading
+	fence := ""
+
+	for _, line := range strings.Split(md, "\n") {
+		if fence != "" {
+			if isClosingFence(line, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if marker, _, ok := parseFence(line); ok {
+			fence = marker
+			continue
+		}
+
+		level := len(line) - len(strings.TrimLeft(line, "#"))
+		if level == 0 || level > 6 || (len(line) > level && line[level] != ' ') {
+			continue
+		}
+		title := headingLinkPattern.ReplaceAllString(line[level:], "$1")
+		title = strings.TrimSpace(headingMarkPattern.ReplaceAllString(title, ""))
+		title = strings.TrimSpace(strings.TrimRight(title, "#"))
+		if title != "" {
+			headings = append(headings, tui.Heading{Level: level, Title: title})
+		}
+	}
+	return headings
+}
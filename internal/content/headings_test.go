@@ -0,0 +1,54 @@
+package content
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+/**
+ * Tests headings are listed with levels and plain text, skipping code.
+ */
+func TestPageHeadings(t *testing.T) {
+	md := "# Hello *World*\n\nText\n\n```sh\n# not a heading\n```\n\n## [Docs](https://example.com) `api` ##\n#hashtag\n###### Deep"
+	got := fmt.Sprint(pageHeadings(md))
+	want := "[{1 Hello World 0} {2 Docs api 0} {6 Deep 0}]"
+	if got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
+
+/**
+ * Tests loaded tabs record the rendered line of each heading.
+ */
+func TestLoadTabs_HeadingLines(t *testing.T) {
+	dir := t.TempDir()
+	md := "# About\n\nIntro paragraph.\n\n## Experience\n\n- one\n- two\n\n```sh\n# not a heading\n```\n\n### Skills **and** tools\n"
+	if err := os.WriteFile(filepath.Join(dir, "about.md"), []byte(md), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tabs, err := LoadTabs(dir)
+	if err != nil {
+		t.Fatalf("LoadTabs failed: %v", err)
+	}
+	about := tabs[1]
+	if len(about.Headings) != 3 {
+		t.Fatalf("Expected 3 headings, got %v", about.Headings)
+	}
+
+	lines := strings.Split(about.Content, "\n")
+	for _, h := range about.Headings {
+		if h.Line < 1 || h.Line > len(lines) {
+			t.Errorf("Expected %q to be located, got line %d", h.Title, h.Line)
+			continue
+		}
+		if text := ansi.Strip(lines[h.Line-1]); !strings.Contains(text, h.Title) {
+			t.Errorf("Expected line %d to show %q, got %q", h.Line, h.Title, text)
+		}
+	}
+}
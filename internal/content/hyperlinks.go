@@ -0,0 +1,211 @@
+package content
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/adamdeleeuw/ssh-portfolio/internal/tui"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Private-use runes around the text of links that become OSC 8
+// hyperlinks; glamour passes them through and they are swapped for
+// the escape sequences after rendering
+const (
+	hyperlinkOpen  = '\uE000'
+	hyperlinkClose = '\uE001'
+)
+
+/**
+ * Reports whether a client's terminal is known to support OSC 8
+ * hyperlinks. Over SSH only TERM and the environment the client
+ * chooses to send are known, so this errs towards footnotes.
+ * @param term - TERM from the PTY request
+ * @param environ - Client environment ("KEY=value" entries)
+ * @return true for terminals known to open OSC 8 links
+ */
+func SupportsHyperlinks(term string, environ []string) bool {
+	term = strings.ToLower(term)
+	for _, name := range []string{"kitty", "ghostty", "wezterm", "foot", "alacritty", "contour"} {
+		if strings.Contains(term, name) {
+			return true
+		}
+	}
+
+	for _, kv := range environ {
+		key, value, _ := strings.Cut(kv, "=")
+		switch key {
+		case "LC_TERMINAL", "TERM_PROGRAM":
+			switch value {
+			case "iTerm2", "iTerm.app", "WezTerm", "vscode", "ghostty", "Tabby", "WarpTerminal":
+				return true
+			}
+		case "WT_SESSION", "KITTY_WINDOW_ID":
+			return true
+		case "VTE_VERSION":
+			// GNOME Terminal and other VTE terminals since 0.50
+			if v, err := strconv.Atoi(value); err == nil && v >= 5000 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+/**
+ * Rewrites the inline links of a document before glamour renders it,
+ * which would otherwise print each URL after the link text. Links to
+ * other sites are marked for OSC 8 hyperlinks, or numbered with their
+ * URLs listed at the bottom as footnotes; links to content files are
+ * followed in the TUI, so only their text is kept.
+ * @param md - Markdown after template expansion
+ * @param hyperlinks - Mark links for OSC 8 instead of footnotes
+ * @return Markdown to render, and the URLs of the marked (or numbered)
+ *         links in order
+ */
+func markLinks(md string, hyperlinks bool) (string, []string) {
+	lines := strings.Split(md, "\n")
+	var urls []string
+	fence := ""
+
+	for i, line := range lines {
+		if fence != "" {
+			if isClosingFence(line, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if marker, _, ok := parseFence(line); ok {
+			fence = marker
+			continue
+		}
+		lines[i] = markLineLinks(line, hyperlinks, &urls)
+	}
+
+	if !hyperlinks && len(urls) > 0 {
+		lines = append(lines, "", "---", "", "**Links**", "")
+		for n, u := range urls {
+			lines = append(lines, fmt.Sprintf(`\[%d\] %s`, n+1, u))
+		}
+	}
+	return strings.Join(lines, "\n"), urls
+}
+
+/**
+ * Rewrites the links of one line of markdown for markLinks.
+ * @param urls - URLs marked so far; those of this line are appended
+ */
+func markLineLinks(line string, hyperlinks bool, urls *[]string) string {
+	code := codeSpanPattern.FindAllStringIndex(line, -1)
+	inCode := func(at int) bool {
+		for _, c := range code {
+			if at >= c[0] && at < c[1] {
+				return true
+			}
+		}
+		return false
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range inlineLinkPattern.FindAllStringSubmatchIndex(line, -1) {
+		text, target := line[m[4]:m[5]], line[m[6]:m[7]]
+		if m[3] > m[2] || inCode(m[0]) || strings.Contains(text, "](") || strings.HasPrefix(target, "#") {
+			continue // Images, code, nested markup and anchors glamour already leaves alone
+		}
+		u, err := url.Parse(target)
+		if err != nil {
+			continue
+		}
+
+		b.WriteString(line[last:m[0]])
+		switch {
+		case u.Scheme == "" && !strings.HasPrefix(target, "//"):
+			b.WriteString("[" + text + "](#)")
+		case hyperlinks:
+			b.WriteString("[" + string(hyperlinkOpen) + text + string(hyperlinkClose) + "](#)")
+			*urls = append(*urls, target)
+		default:
+			*urls = append(*urls, target)
+			b.WriteString(fmt.Sprintf(`[%s](#)\[%d\]`, text, len(*urls)))
+		}
+		last = m[1]
+	}
+	b.WriteString(line[last:])
+	return b.String()
+}
+
+/**
+ * Turns the links marked by markLinks into OSC 8 hyperlinks. Links
+ * are closed at the end of each line and reopened on the next, so the
+ * padding around wrapped text is not part of them.
+ * @param rendered - Glamour output
+ * @param urls - URLs of the marked links, in order
+ * @return Rendered content with hyperlinks
+ */
+func applyHyperlinks(rendered string, urls []string) string {
+	if len(urls) == 0 {
+		return rendered
+	}
+	lines := strings.Split(rendered, "\n")
+	next, open := 0, ""
+
+	for i, line := range lines {
+		if open == "" && !strings.ContainsAny(line, string([]rune{hyperlinkOpen, hyperlinkClose})) {
+			continue
+		}
+		var b strings.Builder
+		reopen := open != ""
+		end := -1 // Where the link's text on this line ends
+		var state byte
+		for line != "" {
+			seq, width, n, newState := ansi.DecodeSequence(line, state, nil)
+			state, line = newState, line[n:]
+			r := []rune(seq)[0]
+			switch {
+			case r == hyperlinkOpen:
+				if next < len(urls) {
+					open = urls[next]
+					next++
+					b.WriteString(ansi.SetHyperlink(open))
+				}
+				continue
+			case r == hyperlinkClose:
+				if open != "" {
+					open = ""
+					b.WriteString(ansi.ResetHyperlink())
+				}
+				continue
+			case reopen && width > 0 && seq != " ":
+				// Continue the link from the previous line at its text
+				b.WriteString(ansi.SetHyperlink(open))
+				reopen = false
+			}
+			b.WriteString(seq)
+			if open != "" && width > 0 && seq != " " {
+				end = b.Len()
+			}
+		}
+
+		out := b.String()
+		if open != "" && !reopen && end >= 0 {
+			out = out[:end] + ansi.ResetHyperlink() + out[end:]
+		}
+		lines[i] = out
+	}
+	return strings.Join(lines, "\n")
+}
+
+/**
+ * Lists the footnote URLs markLinks added below a page, for keyboard
+ * focus in the TUI.
+ */
+func footnoteLinks(urls []string) []tui.Link {
+	links := make([]tui.Link, len(urls))
+	for i, u := range urls {
+		links[i] = tui.Link{Text: u, URL: u}
+	}
+	return links
+}
@@ -0,0 +1,75 @@
+package content
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+/**
+ * Tests links to other sites get footnotes or hyperlink markers, and
+ * links to content files lose their URL either way.
+ */
+func TestMarkLinks(t *testing.T) {
+	md := "See [my project](projects.md#ssh-portfolio), [the code](https://github.com/x/y) and <https://e.com>.\n" +
+		"`[not](https://a.com)` [top](#intro)\n\n```md\n[in code](https://b.com)\n```"
+
+	got, urls := markLinks(md, false)
+	if !strings.HasPrefix(got, "See [my project](#), [the code](#)\\[1\\] and <https://e.com>.\n`[not](https://a.com)` [top](#intro)") {
+		t.Errorf("Expected numbered links, got %q", got)
+	}
+	if !strings.Contains(got, "[in code](https://b.com)") || !strings.HasSuffix(got, "**Links**\n\n\\[1\\] https://github.com/x/y") {
+		t.Errorf("Expected a footnote list and code left alone, got %q", got)
+	}
+	if len(urls) != 1 || urls[0] != "https://github.com/x/y" {
+		t.Errorf("Expected the footnote URL, got %v", urls)
+	}
+
+	got, urls = markLinks(md, true)
+	if !strings.HasPrefix(got, "See [my project](#), [\uE000the code\uE001](#) and") || strings.Contains(got, "**Links**") {
+		t.Errorf("Expected a marked link without footnotes, got %q", got)
+	}
+	if len(urls) != 1 {
+		t.Errorf("Expected one hyperlink, got %v", urls)
+	}
+}
+
+/**
+ * Tests marked links become OSC 8 hyperlinks, reopened after the
+ * indent of each line they wrap onto.
+ */
+func TestApplyHyperlinks(t *testing.T) {
+	rendered := "  See \uE000a long\n  link\uE001 and \uE000b\uE001\n  end"
+	got := applyHyperlinks(rendered, []string{"https://a.com", "https://b.com"})
+	want := "  See " + ansi.SetHyperlink("https://a.com") + "a long" + ansi.ResetHyperlink() + "\n" +
+		"  " + ansi.SetHyperlink("https://a.com") + "link" + ansi.ResetHyperlink() +
+		" and " + ansi.SetHyperlink("https://b.com") + "b" + ansi.ResetHyperlink() + "\n  end"
+	if got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+/**
+ * Tests hyperlink support is detected from TERM and the client's
+ * environment.
+ */
+func TestSupportsHyperlinks(t *testing.T) {
+	tests := []struct {
+		term    string
+		environ []string
+		want    bool
+	}{
+		{"xterm-kitty", nil, true},
+		{"xterm-256color", []string{"TERM_PROGRAM=iTerm.app"}, true},
+		{"xterm-256color", []string{"VTE_VERSION=7200"}, true},
+		{"xterm-256color", []string{"VTE_VERSION=4600"}, false},
+		{"xterm-256color", []string{"LANG=en_US.UTF-8"}, false},
+		{"screen", nil, false},
+	}
+	for _, tt := range tests {
+		if got := SupportsHyperlinks(tt.term, tt.environ); got != tt.want {
+			t.Errorf("SupportsHyperlinks(%q, %v) = %v, want %v", tt.term, tt.environ, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package content
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"io/fs"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/adamdeleeuw/ssh-portfolio/internal/termimg"
+	"github.com/adamdeleeuw/ssh-portfolio/internal/tui"
+)
+
+// Prefix of the paragraphs that stand in for images during rendering
+const imagePlaceholder = "sshportfolioimage"
+
+// An image on a line of its own: ![alt](src) or ![alt](src "title")
+var imageLinePattern = regexp.MustCompile(`^\s*!\[([^\]]*)\]\(\s*<?([^)\s>]+)>?(?:\s+"([^"]*)")?\s*\)\s*$`)
+
+// Decoded images keyed by content hash, shared by all sessions.
+// Cleared wholesale when it grows past maxDecodedImages.
+const maxDecodedImages = 64
+
+var decodedImages = struct {
+	sync.Mutex
+	entries map[string]image.Image
+}{entries: make(map[string]image.Image)}
+
+/**
+ * Replaces images that sit on a line of their own with placeholder
+ * paragraphs, so the TUI can draw them at the viewport width.
+ * Remote images, images inside code blocks and inline images are left
+ * for glamour, which shows them as links. A title of "braille" asks
+ * for braille rendering, which suits line art and logos. An image that
+ * cannot be loaded is replaced by a caption saying so, and the rest of
+ * the page still renders.
+ * @param body - Markdown after template expansion
+ * @param fsys - Content filesystem
+ * @param file - Path of the page, for resolving relative image paths
+ * @return Markdown with placeholders
+ * @return Images keyed by placeholder
+ * @return An error naming each image that could not be loaded
+ */
+func extractImages(body string, fsys fs.FS, file string) (string, map[string]tui.Image, []error) {
+	lines := strings.Split(body, "\n")
+	images := make(map[string]tui.Image)
+	fence := ""
+	var errs []error
+
+	for i, line := range lines {
+		if fence != "" {
+			if isClosingFence(line, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if marker, _, ok := parseFence(line); ok {
+			fence = marker
+			continue
+		}
+
+		m := imageLinePattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		if u, err := url.Parse(m[2]); err == nil && u.Scheme != "" {
+			continue
+		}
+
+		img, err := loadImage(fsys, resolvePath(file, m[2]))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("image %s: %w", m[2], err))
+			alt := m[1]
+			if alt == "" {
+				alt = m[2]
+			}
+			lines[i] = "\n*\\[image unavailable: " + alt + "\\]*\n"
+			continue
+		}
+		img.Alt = m[1]
+		img.Braille = strings.EqualFold(m[3], "braille")
+
+		key := fmt.Sprintf("%s%d", imagePlaceholder, len(images))
+		images[key] = img
+		lines[i] = "\n" + key + "\n"
+	}
+
+	if len(images) == 0 && len(errs) == 0 {
+		return body, nil, nil
+	}
+	return strings.Join(lines, "\n"), images, errs
+}
+
+/**
+ * Reads and decodes an image from the content filesystem.
+ * Decoded images are cached by content hash.
+ * @param fsys - Content filesystem
+ * @param name - Slash-separated path
+ * @return Image with Key and Img set
+ * @return error if the file is missing, too large or not an image
+ */
+func loadImage(fsys fs.FS, name string) (tui.Image, error) {
+	if info, err := fs.Stat(fsys, name); err == nil && info.Size() > termimg.MaxFileSize {
+		return tui.Image{}, fmt.Errorf("larger than %d MB", termimg.MaxFileSize>>20)
+	}
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return tui.Image{}, err
+	}
+
+	sum := sha256.Sum256(data)
+	key := hex.EncodeToString(sum[:16])
+
+	decodedImages.Lock()
+	img, ok := decodedImages.entries[key]
+	decodedImages.Unlock()
+	if ok {
+		return tui.Image{Key: key, Img: img}, nil
+	}
+
+	img, err = termimg.Decode(data)
+	if err != nil {
+		return tui.Image{}, err
+	}
+
+	decodedImages.Lock()
+	if len(decodedImages.entries) >= maxDecodedImages {
+		decodedImages.entries = make(map[string]image.Image)
+	}
+	decodedImages.entries[key] = img
+	decodedImages.Unlock()
+	return tui.Image{Key: key, Img: img}, nil
+}
+
+/**
+ * Loads an optional image from the content directory, e.g. the splash image.
+ * @param contentDir - Content directory
+ * @param name - File name within it
+ * @return Image ready for the TUI
+ * @return error satisfying errors.Is(err, fs.ErrNotExist) if it is absent
+ */
+func LoadImage(contentDir, name string) (tui.Image, error) {
+	return loadImage(contentFS(contentDir), name)
+}
+
+/**
+ * Resolves a link target relative to the page containing it.
+ * A leading slash means the root of the content directory.
+ */
+func resolvePath(from, target string) string {
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(path.Clean(target), "/")
+	}
+	return path.Join(path.Dir(from), target)
+}
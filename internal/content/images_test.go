@@ -0,0 +1,99 @@
+package content
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+/**
+ * Encodes a small PNG for use as test content.
+ */
+func testPNG(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 4, 4))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+/**
+ * Tests local images on their own line become placeholders and
+ * remote, inline and fenced images are left for glamour.
+ */
+func TestExtractImages(t *testing.T) {
+	fsys := fstest.MapFS{
+		"images/me.png":   {Data: testPNG(t)},
+		"blog/logo.png":   {Data: testPNG(t)},
+		"blog/post.md":    {},
+		"images/text.png": {Data: []byte("not an image")},
+	}
+	md := "![Me](/images/me.png)\n\n" +
+		"![Logo](logo.png \"braille\")\n\n" +
+		"![Remote](https://example.com/a.png)\n\n" +
+		"Inline ![x](logo.png) image\n\n" +
+		"```\n![Fenced](logo.png)\n```\n"
+
+	out, images, err := extractImages(md, fsys, "blog/post.md")
+	if err != nil {
+		t.Fatalf("extractImages failed: %v", err)
+	}
+	if len(images) != 2 {
+		t.Fatalf("Expected 2 images, got %d", len(images))
+	}
+
+	me, logo := images[imagePlaceholder+"0"], images[imagePlaceholder+"1"]
+	if me.Alt != "Me" || me.Braille || me.Img == nil {
+		t.Errorf("Unexpected first image %+v", me)
+	}
+	if logo.Alt != "Logo" || !logo.Braille {
+		t.Errorf("Unexpected second image %+v", logo)
+	}
+	if me.Key != logo.Key {
+		t.Error("Expected identical image data to share a key")
+	}
+	for _, want := range []string{"https://example.com/a.png", "Inline ![x](logo.png)", "![Fenced](logo.png)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q to be left in place", want)
+		}
+	}
+
+	out, _, errs := extractImages("![Missing](gone.png)\n\nStill here", fsys, "about.md")
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "gone.png") {
+		t.Errorf("Expected error naming missing image, got %v", errs)
+	}
+	if !strings.Contains(out, "image unavailable: Missing") || !strings.Contains(out, "Still here") {
+		t.Errorf("Expected a caption in place of the image, got %q", out)
+	}
+	if _, _, errs := extractImages("![Bad](images/text.png)", fsys, "about.md"); len(errs) != 1 {
+		t.Error("Expected error for file that is not an image")
+	}
+}
+
+/**
+ * Tests a broken image leaves the rest of the page and the other tabs
+ * loading.
+ */
+func TestLoadTabs_BrokenImage(t *testing.T) {
+	dir := writeContent(t, map[string]string{
+		"welcome.md":  "# Welcome",
+		"about.md":    "# About\n\n![Me](me.png)\n\nHello there",
+		"me.png":      "not an image",
+		"projects.md": "# Projects",
+	})
+
+	tabs, err := LoadTabs(dir)
+	if err != nil {
+		t.Fatalf("LoadTabs failed: %v", err)
+	}
+	about := ansi.Strip(tabs[1].Content)
+	if !strings.Contains(about, "Hello there") || !strings.Contains(about, "image unavailable: Me") {
+		t.Errorf("Expected the page with a caption for the image, got %q", about)
+	}
+}
@@ -0,0 +1,111 @@
+package content
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/adamdeleeuw/ssh-portfolio/internal/tui"
+)
+
+// Inline links, autolinks, bare URLs and code spans (whose text is not
+// markdown) in a line of markdown
+var (
+	inlineLinkPattern = regexp.MustCompile(`(!?)\[((?:[^\[\]]|\[[^\]]*\]\([^)]*\))*)\]\(\s*<?([^\s)>]+)>?(?:\s+"[^"]*")?\s*\)`)
+	autolinkPattern   = regexp.MustCompile(`<((?:https?|mailto):[^\s>]+)>`)
+	bareURLPattern    = regexp.MustCompile(`https?://[^\s<>()\[\]]+`)
+	textImagePattern  = regexp.MustCompile(`!\[[^\]]*\]\([^)]*\)`)
+)
+
+/**
+ * Lists the links of a document outside code, with their text as
+ * glamour renders it. Images, and links whose only text is an image,
+ * are left out. Links to other content files are made relative to the
+ * content directory, which is how the TUI finds their tabs.
+ * @param md - Markdown after template expansion
+ * @param from - Slash-separated path of the document
+ * @return Links in document order
+ */
+func pageLinks(md, from string) []tui.Link {
+	var links []tui.Link
+	fence := ""
+
+	for _, line := range strings.Split(md, "\n") {
+		if fence != "" {
+			if isClosingFence(line, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if marker, _, ok := parseFence(line); ok {
+			fence = marker
+			continue
+		}
+		links = append(links, lineLinks(line)...)
+	}
+
+	for i, l := range links {
+		u, err := url.Parse(l.URL)
+		if err != nil || u.Scheme != "" || strings.HasPrefix(l.URL, "//") || strings.HasPrefix(l.URL, "#") {
+			continue
+		}
+		file, anchor, found := strings.Cut(l.URL, "#")
+		links[i].URL = resolvePath(from, file)
+		if found {
+			links[i].URL += "#" + anchor
+		}
+	}
+	return links
+}
+
+/**
+ * Finds the links in one line of markdown, in order.
+ */
+func lineLinks(line string) []tui.Link {
+	// Blank out code spans so their text is not taken for links
+	line = codeSpanPattern.ReplaceAllStringFunc(line, func(s string) string {
+		return strings.Repeat(" ", len(s))
+	})
+
+	type found struct {
+		at   int
+		link tui.Link
+	}
+	var out []found
+	var spans [][2]int // Links and autolinks, whose URLs are not bare
+	for _, m := range inlineLinkPattern.FindAllStringSubmatchIndex(line, -1) {
+		spans = append(spans, [2]int{m[0], m[1]})
+		if m[3] > m[2] {
+			continue // Image
+		}
+		text := textImagePattern.ReplaceAllString(line[m[4]:m[5]], "")
+		text = headingLinkPattern.ReplaceAllString(text, "$1")
+		text = strings.TrimSpace(headingMarkPattern.ReplaceAllString(text, ""))
+		if text != "" {
+			out = append(out, found{at: m[0], link: tui.Link{Text: text, URL: line[m[6]:m[7]]}})
+		}
+	}
+	for _, m := range autolinkPattern.FindAllStringSubmatchIndex(line, -1) {
+		spans = append(spans, [2]int{m[0], m[1]})
+		url := line[m[2]:m[3]]
+		out = append(out, found{at: m[0], link: tui.Link{Text: strings.TrimPrefix(url, "mailto:"), URL: url}})
+	}
+	for _, m := range bareURLPattern.FindAllStringIndex(line, -1) {
+		inside := false
+		for _, s := range spans {
+			inside = inside || m[0] >= s[0] && m[0] < s[1]
+		}
+		url := strings.TrimRight(line[m[0]:m[1]], ".,;:!?'\"")
+		if !inside {
+			out = append(out, found{at: m[0], link: tui.Link{Text: url, URL: url}})
+		}
+	}
+
+	sort.SliceStable(out, func(a, b int) bool { return out[a].at < out[b].at })
+	links := make([]tui.Link, len(out))
+	for i, f := range out {
+		links[i] = f.link
+	}
+	return links
+}
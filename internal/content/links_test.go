@@ -0,0 +1,23 @@
+package content
+
+import (
+	"fmt"
+	"testing"
+)
+
+/**
+ * Tests links are listed in order with their rendered text, skipping
+ * code and images, and internal targets are made relative to the
+ * content directory.
+ */
+func TestPageLinks(t *testing.T) {
+	md := "See [**my** project](../projects.md#ssh-portfolio), <https://e.com> and https://bare.com/x.\n\n" +
+		"`[not](a.md)` ![logo](logo.png) [![badge](b.svg)](https://ci.com) [top](#intro)\n\n" +
+		"```md\n[in code](x.md)\n```\n\n[Email](mailto:me@example.com \"Write to me\")"
+	got := fmt.Sprint(pageLinks(md, "blog/post.md"))
+	want := "[{my project projects.md#ssh-portfolio} {https://e.com https://e.com} {https://bare.com/x https://bare.com/x} " +
+		"{top #intro} {Email mailto:me@example.com}]"
+	if got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
@@ -1,12 +1,15 @@
 package content
 
 import (
+	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
+	"io/fs"
+	"path"
+	"time"
 
 	"github.com/adamdeleeuw/ssh-portfolio/internal/tui"
 	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/log"
 )
 
 /**
@@ -16,55 +19,319 @@ import (
  * @return error if files cannot be loaded
  */
 func LoadTabs(contentDir string) ([]tui.Tab, error) {
-	// Define tab order and filenames
-	tabFiles := []struct {
-		name     string
-		filename string
-	}{
-		{"Welcome", "welcome.md"},
-		{"About", "about.md"},
-		{"Projects", "projects.md"},
-		{"Future", "future.md"},
-	}
-
-	// Create glamour renderer with dark theme for terminal
-	// Enable hyperlinks for clickable links in compatible terminals (OSC 8)
-	renderer, err := glamour.NewTermRenderer(
-		glamour.WithStandardStyle("dark"),
-		glamour.WithWordWrap(100),
-		glamour.WithPreservedNewLines(),
-	)
+	return LoadSessionTabs(contentDir, Session{})
+}
+
+/**
+ * Loads content for a specific session. Tabs come from tabs.json when
+ * present, otherwise the default set. Markdown files are run through
+ * text/template with the session values before being rendered, and
+ * drafts or pages outside their publish_at/expire_at window are left out
+ * (admins still see drafts and scheduled pages, marked as such).
+ * @param contentDir - Directory containing markdown files
+ * @param sess - Visitor and server values exposed to templates
+ * @return Slice of tabs with rendered content
+ * @return error if files cannot be loaded, templated or rendered
+ */
+func LoadSessionTabs(contentDir string, sess Session) ([]tui.Tab, error) {
+	renderer, err := newSessionRenderer(sess)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create markdown renderer: %w", err)
+		return nil, err
 	}
 
+	fsys := contentFS(contentDir)
+	specs, err := loadManifest(fsys)
+	if err != nil {
+		return nil, err
+	}
 	var tabs []tui.Tab
 
-	for _, tf := range tabFiles {
-		path := filepath.Join(contentDir, tf.filename)
+	for _, spec := range specs {
+		if spec.Blog {
+			posts, err := loadPosts(renderer, fsys, sess)
+			if err != nil {
+				return nil, err
+			}
+			if len(posts) > 0 {
+				tabs = append(tabs, tui.Tab{Name: spec.Name, Posts: posts, Source: postsSource(posts)})
+			}
+			continue
+		}
 
-		// Read markdown file
-		content, err := os.ReadFile(path)
-		if err != nil {
-			// If file doesn't exist, use placeholder
-			tabs = append(tabs, tui.Tab{
-				Name:    tf.name,
-				Content: fmt.Sprintf("Content for %s coming soon!\n\nFile not found: %s", tf.name, tf.filename),
-			})
+		if len(spec.Command) > 0 {
+			tabs = append(tabs, commandTab(spec, contentDir))
+			continue
+		}
+
+		if len(spec.Repos) > 0 {
+			tabs = append(tabs, activityTab(spec, contentDir))
+			continue
+		}
+
+		if len(spec.Probes) > 0 {
+			tabs = append(tabs, statusTab(spec, contentDir))
 			continue
 		}
 
-		// Render markdown to ANSI
-		rendered, err := renderer.Render(string(content))
+		// Prefer structured data, falling back to markdown
+		if spec.Data != "" {
+			dataTabs, err := loadDataTabs(spec.Name, fsys, spec.Data)
+			if err == nil {
+				// Links to the markdown page lead to the data that replaces it
+				if len(dataTabs) > 0 {
+					dataTabs[0].File = spec.File
+				}
+				tabs = append(tabs, dataTabs...)
+				continue
+			}
+			if !isNotExist(err) {
+				return nil, err
+			}
+			if spec.File == "" {
+				tabs = append(tabs, placeholderTab(spec.Name, spec.Data))
+				continue
+			}
+		}
+
+		pg, err := renderFile(renderer, fsys, spec.File, sess)
+		var readErr *fs.PathError
+		if errors.As(err, &readErr) {
+			// If file can't be read, use placeholder
+			tabs = append(tabs, placeholderTab(spec.Name, spec.File))
+			continue
+		}
+		if err == errHidden {
+			continue
+		}
 		if err != nil {
-			return nil, fmt.Errorf("failed to render %s: %w", tf.filename, err)
+			return nil, err
+		}
+
+		name := spec.Name
+		if label := pg.state.label(); label != "" {
+			name += " (" + label + ")"
 		}
 
 		tabs = append(tabs, tui.Tab{
-			Name:    tf.name,
-			Content: rendered,
+			Name:     name,
+			Content:  pg.rendered,
+			Source:   pg.raw,
+			File:     spec.File,
+			Headings: pg.headings,
+			Links:    pg.links,
+			Images:   pg.images,
+			QRCodes:  pg.qrCodes,
+			Contact:  pg.contact,
 		})
 	}
 
 	return tabs, nil
 }
+
+/**
+ * Builds the tab shown in place of a missing file.
+ */
+func placeholderTab(name, file string) tui.Tab {
+	return tui.Tab{
+		Name:    name,
+		Content: fmt.Sprintf("Content for %s coming soon!\n\nFile not found: %s", name, file),
+	}
+}
+
+/**
+ * A markdown file after templating and rendering.
+ */
+type page struct {
+	meta     map[string]string     // Front matter
+	state    publishState          // Draft/scheduled pages are only rendered for admins
+	raw      string                // Markdown before template expansion
+	source   string                // Markdown after template expansion
+	rendered string                // ANSI output
+	headings []tui.Heading         // Headings with their lines in rendered
+	links    []tui.Link            // Links, with internal ones relative to the content directory
+	images   map[string]tui.Image  // Images in rendered, keyed by placeholder
+	qrCodes  map[string]tui.QRCode // QR codes in rendered, keyed by placeholder
+	contact  *tui.QRCode           // Code from the "contact" front matter
+}
+
+// Word wrap of rendered pages. Glamour keeps two columns of margin on
+// each side, so prose is layoutWidth wide like generated pages.
+const renderWidth = layoutWidth + 4
+
+/**
+ * Creates a glamour renderer for content outside a session, e.g.
+ * program output.
+ * @return Renderer with dark theme and the standard wrap width
+ * @return error if glamour cannot be initialized
+ */
+func newRenderer() (*glamour.TermRenderer, error) {
+	return newRendererStyle("dark", renderWidth)
+}
+
+/**
+ * Creates the glamour renderer shared by all pages of a session.
+ * @param sess - Session, for its theme
+ * @return Renderer with the session's theme and the standard wrap width
+ * @return error if glamour cannot be initialized
+ */
+func newSessionRenderer(sess Session) (*glamour.TermRenderer, error) {
+	return newRendererStyle(sess.glamourStyle(), renderWidth)
+}
+
+/**
+ * Creates a glamour renderer with a standard style.
+ * @param style - Glamour standard style, "dark" or "light"
+ * @param wrap - Word wrap column
+ * @return Renderer
+ * @return error if glamour cannot be initialized
+ */
+func newRendererStyle(style string, wrap int) (*glamour.TermRenderer, error) {
+	// Links are made clickable after rendering (see markLinks)
+	renderer, err := glamour.NewTermRenderer(
+		glamour.WithStandardStyle(style),
+		glamour.WithWordWrap(wrap),
+		glamour.WithPreservedNewLines(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create markdown renderer: %w", err)
+	}
+	return renderer, nil
+}
+
+/**
+ * Reads a markdown file and runs it through the content pipeline:
+ * front matter, template expansion, QR codes, custom blocks, images
+ * and glamour.
+ * @param renderer - Glamour renderer
+ * @param fsys - Content filesystem
+ * @param file - Slash-separated path of the markdown file within fsys
+ * @param sess - Session values for templates
+ * @return Rendered page
+ * @return *fs.PathError if the file cannot be read, errHidden if the
+ *         session may not see it, or an error naming the file if any
+ *         later stage fails
+ */
+func renderFile(renderer *glamour.TermRenderer, fsys fs.FS, file string, sess Session) (page, error) {
+	name := path.Base(file)
+
+	// Read markdown file
+	content, err := fs.ReadFile(fsys, file)
+	if err != nil {
+		return page{}, err
+	}
+
+	meta, body, err := parseFrontMatter(string(content))
+	if err != nil {
+		return page{}, fmt.Errorf("invalid front matter in %s: %w", name, err)
+	}
+
+	// Skip drafts and pages outside their publication window
+	state, err := pageState(meta, sess.now())
+	if err != nil {
+		return page{}, fmt.Errorf("invalid front matter in %s: %w", name, err)
+	}
+	if !state.visibleTo(sess) {
+		return page{}, errHidden
+	}
+
+	// Expand template variables unless the file opts out
+	raw := body
+	if meta["template"] != "false" {
+		var modified time.Time
+		if info, err := fs.Stat(fsys, file); err == nil {
+			modified = info.ModTime()
+		}
+
+		body, err = executeTemplate(name, body, newPageData(sess, modified, meta))
+		if err != nil {
+			return page{}, fmt.Errorf("failed to expand template in %s: %w", name, err)
+		}
+	}
+
+	// QR codes are drawn by the TUI, which knows whether they fit
+	stripped, qrCodes, err := extractQRCodes(body)
+	if err != nil {
+		return page{}, fmt.Errorf("%s: %w", name, err)
+	}
+	contact, err := pageContact(meta)
+	if err != nil {
+		return page{}, fmt.Errorf("invalid front matter in %s: %w", name, err)
+	}
+
+	// Pull out custom blocks (callouts, charts, ...) so glamour skips them
+	stripped, blocks, err := extractBlocks(stripped, layoutWidth)
+	if err != nil {
+		return page{}, fmt.Errorf("%s: %w", name, err)
+	}
+
+	// Swap local images for placeholders the TUI draws at viewport width
+	// A broken image only loses its own place; validate reports it
+	stripped, images, imageErrs := extractImages(stripped, fsys, file)
+	for _, err := range imageErrs {
+		log.Warn("Image unavailable", "page", name, "error", err)
+	}
+
+	// Keep glamour from printing URLs after link text
+	stripped, urls := markLinks(stripped, sess.Hyperlinks)
+
+	// Render markdown to ANSI, then splice the custom blocks back in
+	rendered, err := renderer.Render(stripped)
+	if err != nil {
+		return page{}, fmt.Errorf("failed to render %s: %w", name, err)
+	}
+	rendered = spliceBlocks(rendered, blocks, 2)
+
+	links := pageLinks(body, file)
+	if sess.Hyperlinks {
+		rendered = applyHyperlinks(rendered, urls)
+	} else {
+		links = append(links, footnoteLinks(urls)...)
+	}
+
+	// Record where each heading landed while the output is known
+	return page{
+		meta:     meta,
+		state:    state,
+		raw:      raw,
+		source:   body,
+		rendered: rendered,
+		headings: tui.LocateHeadings(rendered, pageHeadings(body)),
+		links:    links,
+		images:   images,
+		qrCodes:  qrCodes,
+		contact:  contact,
+	}, nil
+}
+
+/**
+ * Builds tabs from a structured data file.
+ * @param name - Name of the tab the data replaces
+ * @param fsys - Content filesystem
+ * @param dataFile - Data file name (projects.json or resume.json)
+ * @return One or more tabs generated from the data
+ * @return error (satisfying isNotExist) if the file is absent
+ */
+func loadDataTabs(name string, fsys fs.FS, dataFile string) ([]tui.Tab, error) {
+	data, err := fs.ReadFile(fsys, dataFile)
+	if err != nil {
+		return nil, err
+	}
+
+	switch dataFile {
+	case "resume.json":
+		resume, err := parseResume(data, dataFile)
+		if err != nil {
+			return nil, err
+		}
+		return ResumeTabs(resume), nil
+
+	case "projects.json":
+		projects, err := parseProjects(data, dataFile)
+		if err != nil {
+			return nil, err
+		}
+		return []tui.Tab{{Name: name, Projects: projects, Source: projectsSource(projects)}}, nil
+	}
+
+	return nil, fmt.Errorf("unknown data file %s", dataFile)
+}
@@ -0,0 +1,283 @@
+package content
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/url"
+	"path"
+	"time"
+)
+
+// Optional file in the content directory listing the tabs
+const manifestFile = "tabs.json"
+
+// Limits for command tabs
+const (
+	defaultCommandInterval = time.Minute
+	minCommandInterval     = 5 * time.Second
+	maxCommandTimeout      = time.Minute
+	maxCommandOutput       = 1 << 20
+)
+
+// Limits for status tabs
+const (
+	defaultProbeInterval = time.Minute
+	minProbeInterval     = 10 * time.Second
+	defaultProbeHistory  = 60
+	maxProbeHistory      = 1440
+)
+
+// Limits for activity tabs; scanning history is slow, so rescans are rare
+const (
+	defaultActivityInterval = time.Hour
+	minActivityInterval     = time.Minute
+)
+
+/**
+ * One tab in the manifest. Exactly one source is set: a markdown file
+ * (optionally replaced by a data file when that exists), the blog, an
+ * external command, the activity of local git repositories, or service
+ * probes.
+ */
+type tabSpec struct {
+	Name string `json:"name"`
+
+	File string `json:"file,omitempty"` // Markdown page
+	Data string `json:"data,omitempty"` // resume.json or projects.json, preferred over File when present
+	Blog bool   `json:"blog,omitempty"` // Posts from blog/; the tab is left out when there are none
+
+	Command   []string `json:"command,omitempty"`    // Program and arguments, run in the content directory
+	Mode      string   `json:"mode,omitempty"`       // "interval" (default) or "interactive"
+	Interval  string   `json:"interval,omitempty"`   // How often commands rerun or probes run (default 1m), or repositories are rescanned (default 1h)
+	Timeout   string   `json:"timeout,omitempty"`    // Run limit, or time to first frame when interactive (default 5s)
+	MaxOutput int      `json:"max_output,omitempty"` // Output limit in bytes (default 64 KiB)
+
+	Repos   []string `json:"repos,omitempty"`   // Git repositories (globs allowed), relative to the content directory
+	Authors []string `json:"authors,omitempty"` // Only count commits by these names or e-mail addresses
+
+	Probes  []probeSpec `json:"probes,omitempty"`  // Services checked by a status tab
+	History int         `json:"history,omitempty"` // Results kept per probe (default 60)
+
+	interval, timeout time.Duration // Parsed by check
+}
+
+/**
+ * One service in a status tab. Exactly one of TCP, HTTP and Command is set.
+ */
+type probeSpec struct {
+	Name    string   `json:"name"`
+	TCP     string   `json:"tcp,omitempty"`     // "host:port"
+	HTTP    string   `json:"http,omitempty"`    // http:// or https:// URL
+	Command []string `json:"command,omitempty"` // Health check program, run in the content directory
+	Timeout string   `json:"timeout,omitempty"` // Limit for one check (default 5s)
+
+	timeout time.Duration // Parsed by check
+}
+
+// Tabs shown when the content directory has no manifest
+var defaultTabs = []tabSpec{
+	{Name: "Welcome", File: "welcome.md"},
+	{Name: "About", File: "about.md", Data: "resume.json"},
+	{Name: "Projects", File: "projects.md", Data: "projects.json"},
+	{Name: "Blog", Blog: true},
+	{Name: "Future", File: "future.md"},
+}
+
+/**
+ * The manifest file: tabs in display order.
+ */
+type manifest struct {
+	Tabs []tabSpec `json:"tabs"`
+}
+
+/**
+ * Reads the tab manifest, falling back to the default tabs.
+ * @param fsys - Content filesystem
+ * @return Tabs in display order
+ * @return error if the manifest exists but is malformed
+ */
+func loadManifest(fsys fs.FS) ([]tabSpec, error) {
+	data, err := fs.ReadFile(fsys, manifestFile)
+	if isNotExist(err) {
+		return defaultTabs, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseManifest(data)
+}
+
+/**
+ * Parses and checks a manifest.
+ * @param data - Contents of tabs.json
+ * @return Tabs in display order
+ * @return error naming the first invalid tab
+ */
+func parseManifest(data []byte) ([]tabSpec, error) {
+	var m manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", manifestFile, err)
+	}
+	if len(m.Tabs) == 0 {
+		return nil, fmt.Errorf("%s lists no tabs", manifestFile)
+	}
+
+	seen := make(map[string]bool)
+	for i := range m.Tabs {
+		spec := &m.Tabs[i]
+		if err := spec.check(); err != nil {
+			return nil, fmt.Errorf("%s: tab %d (%s): %w", manifestFile, i+1, spec.Name, err)
+		}
+		if seen[spec.Name] {
+			return nil, fmt.Errorf("%s: tab %d: duplicate name %q", manifestFile, i+1, spec.Name)
+		}
+		seen[spec.Name] = true
+	}
+	return m.Tabs, nil
+}
+
+/**
+ * Validates one tab and parses its durations.
+ */
+func (t *tabSpec) check() error {
+	if t.Name == "" {
+		return errors.New("missing name")
+	}
+
+	sources := 0
+	for _, set := range []bool{t.File != "" || t.Data != "", t.Blog, len(t.Command) > 0, len(t.Repos) > 0, len(t.Probes) > 0} {
+		if set {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return errors.New("set exactly one of file/data, blog, command, repos or probes")
+	}
+
+	if t.File != "" && path.Ext(t.File) != ".md" {
+		return fmt.Errorf("file %q is not a markdown file", t.File)
+	}
+	if t.Data != "" && t.Data != "resume.json" && t.Data != "projects.json" {
+		return fmt.Errorf("data must be resume.json or projects.json, got %q", t.Data)
+	}
+	if len(t.Repos) > 0 {
+		return t.checkInterval(defaultActivityInterval, minActivityInterval)
+	}
+	if len(t.Probes) > 0 {
+		return t.checkProbes()
+	}
+	if len(t.Command) == 0 {
+		return nil
+	}
+
+	switch t.Mode {
+	case "", "interval", "interactive":
+	default:
+		return fmt.Errorf("mode must be \"interval\" or \"interactive\", got %q", t.Mode)
+	}
+
+	if err := t.checkInterval(defaultCommandInterval, minCommandInterval); err != nil {
+		return err
+	}
+
+	var err error
+	if t.Timeout != "" {
+		if t.timeout, err = time.ParseDuration(t.Timeout); err != nil {
+			return fmt.Errorf("timeout: %w", err)
+		}
+		if t.timeout <= 0 || t.timeout > maxCommandTimeout {
+			return fmt.Errorf("timeout must be between 0 and %s", maxCommandTimeout)
+		}
+	}
+	if t.MaxOutput < 0 || t.MaxOutput > maxCommandOutput {
+		return fmt.Errorf("max_output must be between 0 and %d", maxCommandOutput)
+	}
+	return nil
+}
+
+/**
+ * Parses the refresh interval.
+ * @param def - Interval when none is set
+ * @param least - Shortest interval allowed
+ */
+func (t *tabSpec) checkInterval(def, least time.Duration) error {
+	t.interval = def
+	if t.Interval == "" {
+		return nil
+	}
+
+	var err error
+	if t.interval, err = time.ParseDuration(t.Interval); err != nil {
+		return fmt.Errorf("interval: %w", err)
+	}
+	if t.interval < least {
+		return fmt.Errorf("interval must be at least %s", least)
+	}
+	return nil
+}
+
+/**
+ * Validates the probes of a status tab and parses their timeouts.
+ */
+func (t *tabSpec) checkProbes() error {
+	if err := t.checkInterval(defaultProbeInterval, minProbeInterval); err != nil {
+		return err
+	}
+	if t.History == 0 {
+		t.History = defaultProbeHistory
+	}
+	if t.History < 1 || t.History > maxProbeHistory {
+		return fmt.Errorf("history must be between 1 and %d", maxProbeHistory)
+	}
+
+	for i := range t.Probes {
+		if err := t.Probes[i].check(); err != nil {
+			return fmt.Errorf("probe %d (%s): %w", i+1, t.Probes[i].Name, err)
+		}
+	}
+	return nil
+}
+
+/**
+ * Validates one probe and parses its timeout.
+ */
+func (p *probeSpec) check() error {
+	if p.Name == "" {
+		return errors.New("missing name")
+	}
+
+	kinds := 0
+	for _, set := range []bool{p.TCP != "", p.HTTP != "", len(p.Command) > 0} {
+		if set {
+			kinds++
+		}
+	}
+	if kinds != 1 {
+		return errors.New("set exactly one of tcp, http or command")
+	}
+
+	if p.TCP != "" {
+		if _, _, err := net.SplitHostPort(p.TCP); err != nil {
+			return fmt.Errorf("tcp: %w", err)
+		}
+	}
+	if p.HTTP != "" {
+		u, err := url.Parse(p.HTTP)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("http must be an http:// or https:// URL, got %q", p.HTTP)
+		}
+	}
+	if p.Timeout != "" {
+		var err error
+		if p.timeout, err = time.ParseDuration(p.Timeout); err != nil {
+			return fmt.Errorf("timeout: %w", err)
+		}
+		if p.timeout <= 0 || p.timeout > maxCommandTimeout {
+			return fmt.Errorf("timeout must be between 0 and %s", maxCommandTimeout)
+		}
+	}
+	return nil
+}
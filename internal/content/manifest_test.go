@@ -0,0 +1,223 @@
+package content
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adamdeleeuw/ssh-portfolio/internal/extproc"
+	"github.com/adamdeleeuw/ssh-portfolio/internal/tui"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+/**
+ * Tests the manifest decides tab order and names.
+ */
+func TestLoadTabs_Manifest(t *testing.T) {
+	dir := writeContent(t, map[string]string{
+		"tabs.json":  `{"tabs": [{"name": "Hello", "file": "welcome.md"}, {"name": "Status", "command": ["./status.sh"]}, {"name": "Notes", "file": "notes.md"}]}`,
+		"welcome.md": "# Welcome",
+		"notes.md":   "# Notes\n\nSome notes",
+	})
+
+	tabs, err := LoadTabs(dir)
+	if err != nil {
+		t.Fatalf("LoadTabs failed: %v", err)
+	}
+
+	var names []string
+	for _, tab := range tabs {
+		names = append(names, tab.Name)
+	}
+	if strings.Join(names, ",") != "Hello,Status,Notes" {
+		t.Errorf("Unexpected tabs %v", names)
+	}
+
+	// Commands are off unless the operator enables them
+	if tabs[1].Panel != nil || !strings.Contains(tabs[1].Content, "disabled") {
+		t.Errorf("Expected disabled command tab, got %+v", tabs[1])
+	}
+}
+
+/**
+ * Tests malformed manifests are rejected with the offending tab.
+ */
+func TestParseManifest_Errors(t *testing.T) {
+	tests := map[string]string{
+		`{"tabs": []}`:                                                                        "no tabs",
+		`{"tabs": [{"file": "a.md"}]}`:                                                        "missing name",
+		`{"tabs": [{"name": "A"}]}`:                                                           "exactly one",
+		`{"tabs": [{"name": "A", "file": "a.md", "blog": true}]}`:                             "exactly one",
+		`{"tabs": [{"name": "A", "file": "a.txt"}]}`:                                          "markdown",
+		`{"tabs": [{"name": "A", "data": "other.json"}]}`:                                     "resume.json",
+		`{"tabs": [{"name": "A", "command": ["x"], "mode": "daemon"}]}`:                       "mode",
+		`{"tabs": [{"name": "A", "command": ["x"], "interval": "1s"}]}`:                       "at least",
+		`{"tabs": [{"name": "A", "command": ["x"], "timeout": "soon"}]}`:                      "timeout",
+		`{"tabs": [{"name": "A", "repos": ["x"], "interval": "5s"}]}`:                         "at least",
+		`{"tabs": [{"name": "A", "repos": ["x"], "command": ["x"]}]}`:                         "exactly one",
+		`{"tabs": [{"name": "A", "probes": [{"name": "P", "tcp": "nohost"}]}]}`:               "tcp",
+		`{"tabs": [{"name": "A", "probes": [{"name": "P", "http": "ftp://x"}]}]}`:             "http",
+		`{"tabs": [{"name": "A", "probes": [{"name": "P"}]}]}`:                                "exactly one of tcp",
+		`{"tabs": [{"name": "A", "probes": [{"tcp": "a:1"}]}]}`:                               "missing name",
+		`{"tabs": [{"name": "A", "probes": [{"name": "P", "tcp": "a:1"}], "history": 5000}]}`: "history",
+		`{"tabs": [{"name": "A", "blog": true}, {"name": "A", "blog": true}]}`:                "duplicate",
+	}
+
+	for data, want := range tests {
+		if _, err := parseManifest([]byte(data)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("parseManifest(%s): expected error containing %q, got %v", data, want, err)
+		}
+	}
+}
+
+/**
+ * Writes an executable shell script into the content directory.
+ */
+func writeScript(t *testing.T, dir, name, body string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("requires /bin/sh")
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+body), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+/**
+ * Enables commands for the duration of a test.
+ */
+func withCommands(t *testing.T) {
+	t.Helper()
+	EnableCommands(true)
+	t.Cleanup(func() { EnableCommands(false) })
+}
+
+/**
+ * Runs a command until the panel settles on a view, feeding the
+ * messages its commands produce back into it (like Bubble Tea would).
+ */
+func runPanel(t *testing.T, p tui.Panel, cmd tea.Cmd, until string) string {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for cmd != nil && time.Now().Before(deadline) {
+		msg := cmd()
+		if batch, ok := msg.(tea.BatchMsg); ok && len(batch) > 0 {
+			cmd = batch[0]
+			continue
+		}
+		cmd = p.Update(msg)
+		if strings.Contains(ansi.Strip(p.View()), until) {
+			break
+		}
+	}
+	return ansi.Strip(p.View())
+}
+
+/**
+ * Tests interval commands render their markdown output.
+ */
+func TestCommandTab_Interval(t *testing.T) {
+	withCommands(t)
+	dir := writeContent(t, map[string]string{
+		"tabs.json": `{"tabs": [{"name": "Status", "command": ["./status.sh"], "interval": "10s"}]}`,
+	})
+	writeScript(t, dir, "status.sh", "echo '# Build status'; echo; echo 'All **green**'\n")
+
+	tabs, err := LoadTabs(dir)
+	if err != nil {
+		t.Fatalf("LoadTabs failed: %v", err)
+	}
+	p := tabs[0].Panel
+	if p == nil || p.Interactive() {
+		t.Fatalf("Expected an interval panel, got %+v", tabs[0])
+	}
+	defer p.Close()
+
+	view := runPanel(t, p, p.Init(80, 20), "All green")
+	if !strings.Contains(view, "Build status") || !strings.Contains(view, "All green") {
+		t.Errorf("Expected rendered output, got %q", view)
+	}
+}
+
+/**
+ * Tests the interactive protocol: the program gets the size and keys
+ * and its frames are shown; a crash is shown in the tab.
+ */
+func TestCommandTab_Interactive(t *testing.T) {
+	withCommands(t)
+	dir := writeContent(t, map[string]string{
+		"tabs.json": `{"tabs": [{"name": "Game", "command": ["./game.sh"], "mode": "interactive"}]}`,
+	})
+	writeScript(t, dir, "game.sh", `while read -r line; do
+  case "$line" in
+    *'"key":"x"'*) echo "crashed" >&2; exit 1 ;;
+    *'"type":"size"'*) echo '{"type":"frame","content":"sized \u001b]0;title\u0007ok"}' ;;
+    *) echo '{"type":"frame","content":"key event"}' ;;
+  esac
+done
+`)
+
+	tabs, err := LoadTabs(dir)
+	if err != nil {
+		t.Fatalf("LoadTabs failed: %v", err)
+	}
+	p := tabs[0].Panel
+	if p == nil || !p.Interactive() {
+		t.Fatalf("Expected an interactive panel, got %+v", tabs[0])
+	}
+	defer p.Close()
+
+	next := p.Init(80, 20)
+	if view := runPanel(t, p, next, "sized"); !strings.Contains(view, "sized ok") {
+		t.Errorf("Expected first frame with escapes removed, got %q", view)
+	}
+	if strings.Contains(p.View(), "\x1b]") {
+		t.Error("Expected OSC sequences to be stripped from frames")
+	}
+
+	p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	next = p.(*interactivePanel).next(p.(*interactivePanel).proc)
+	if view := runPanel(t, p, next, "key event"); !strings.Contains(view, "key event") {
+		t.Errorf("Expected frame after key, got %q", view)
+	}
+
+	p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	next = p.(*interactivePanel).next(p.(*interactivePanel).proc)
+	if view := runPanel(t, p, next, "stopped"); !strings.Contains(view, "crashed") || !strings.Contains(view, "r to restart") {
+		t.Errorf("Expected crash shown in the tab, got %q", view)
+	}
+}
+
+/**
+ * Tests sessions beyond the server's program limit are told to try
+ * again, and reloads recognise an unchanged program.
+ */
+func TestCommandTab_Busy(t *testing.T) {
+	withCommands(t)
+	extproc.SetMaxProcesses(1)
+	t.Cleanup(func() { extproc.SetMaxProcesses(20) })
+	dir := writeContent(t, map[string]string{
+		"tabs.json": `{"tabs": [{"name": "Game", "command": ["./game.sh"], "mode": "interactive"}]}`,
+	})
+	writeScript(t, dir, "game.sh", `while read -r line; do echo '{"type":"frame","content":"playing"}'; done
+`)
+
+	first, _ := LoadTabs(dir)
+	second, _ := LoadTabs(dir)
+	p1, p2 := first[0].Panel.(*interactivePanel), second[0].Panel.(*interactivePanel)
+	defer p1.Close()
+	defer p2.Close()
+	if p1.Key() != p2.Key() {
+		t.Error("Expected the same manifest entry to give the same key")
+	}
+
+	p1.Init(80, 20)
+	p2.Init(80, 20)
+	if view := ansi.Strip(p2.View()); !strings.Contains(view, "too many visitors") || !strings.Contains(view, "r to try again") {
+		t.Errorf("Expected the second program to be refused, got %q", view)
+	}
+}
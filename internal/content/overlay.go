@@ -0,0 +1,163 @@
+package content
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// Built-in content used when files are missing from the content directory.
+// Set once at startup with SetDefaults.
+var defaultContent fs.FS
+
+// Cache directories git commits are exported under. Exports are served
+// exactly as committed, so a page deleted in git stays deleted.
+var exportRoots struct {
+	sync.Mutex
+	dirs []string
+}
+
+/**
+ * Registers the built-in content compiled into the binary.
+ * Files in the content directory override these one by one.
+ * @param fsys - Filesystem whose root holds welcome.md, about.md, ...
+ */
+func SetDefaults(fsys fs.FS) {
+	defaultContent = fsys
+}
+
+/**
+ * Reports whether sessions are running on built-in content only,
+ * because the content directory is missing, empty or unreadable.
+ * @param contentDir - Configured content directory
+ * @return true if defaults are registered and the directory has nothing
+ *         to list
+ */
+func Degraded(contentDir string) bool {
+	if defaultContent == nil || isExport(contentDir) {
+		return false
+	}
+	entries, err := os.ReadDir(contentDir)
+	return err != nil || len(entries) == 0
+}
+
+/**
+ * Returns the filesystem content is read from: the directory,
+ * overlaid on the built-in defaults if any are registered and the
+ * directory is not a git export.
+ */
+func contentFS(contentDir string) fs.FS {
+	disk := os.DirFS(contentDir)
+	if defaultContent == nil || isExport(contentDir) {
+		return disk
+	}
+	return overlayFS{upper: disk, lower: defaultContent}
+}
+
+/**
+ * Marks a directory as holding git exports, which contentFS then
+ * serves without the built-in defaults.
+ * @param cacheDir - Directory exports are written under
+ */
+func addExportRoot(cacheDir string) {
+	if abs, err := filepath.Abs(cacheDir); err == nil {
+		cacheDir = abs
+	}
+	exportRoots.Lock()
+	defer exportRoots.Unlock()
+	exportRoots.dirs = append(exportRoots.dirs, cacheDir)
+}
+
+/**
+ * Whether a content directory lies inside a git export root.
+ */
+func isExport(contentDir string) bool {
+	abs, err := filepath.Abs(contentDir)
+	if err != nil {
+		return false
+	}
+	exportRoots.Lock()
+	defer exportRoots.Unlock()
+	for _, root := range exportRoots.dirs {
+		if rel, err := filepath.Rel(root, abs); err == nil && rel != "." && filepath.IsLocal(rel) {
+			return true
+		}
+	}
+	return false
+}
+
+/**
+ * Read-only union of two filesystems. Files in upper shadow files
+ * with the same path in lower; directory listings are merged.
+ */
+type overlayFS struct {
+	upper fs.FS
+	lower fs.FS
+}
+
+/**
+ * Opens a file from upper, falling back to lower if upper
+ * does not have it or cannot read it.
+ */
+func (o overlayFS) Open(name string) (fs.File, error) {
+	f, err := o.upper.Open(name)
+	if err == nil {
+		return f, nil
+	}
+	if lf, lerr := o.lower.Open(name); lerr == nil {
+		return lf, nil
+	}
+	return nil, err
+}
+
+/**
+ * Stats a file from upper, falling back to lower.
+ */
+func (o overlayFS) Stat(name string) (fs.FileInfo, error) {
+	info, err := fs.Stat(o.upper, name)
+	if err == nil {
+		return info, nil
+	}
+	if linfo, lerr := fs.Stat(o.lower, name); lerr == nil {
+		return linfo, nil
+	}
+	return nil, err
+}
+
+/**
+ * Lists a directory from both layers; upper entries win on name clashes.
+ */
+func (o overlayFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	upper, uerr := fs.ReadDir(o.upper, name)
+	lower, lerr := fs.ReadDir(o.lower, name)
+	if uerr != nil && lerr != nil {
+		return nil, uerr
+	}
+
+	merged := make(map[string]fs.DirEntry)
+	for _, e := range lower {
+		merged[e.Name()] = e
+	}
+	for _, e := range upper {
+		merged[e.Name()] = e
+	}
+
+	entries := make([]fs.DirEntry, 0, len(merged))
+	for _, e := range merged {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+	return entries, nil
+}
+
+/**
+ * Reports whether an error means the file is simply absent.
+ */
+func isNotExist(err error) bool {
+	return errors.Is(err, fs.ErrNotExist)
+}
@@ -0,0 +1,156 @@
+package content
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+/**
+ * Registers defaults for the duration of a test.
+ */
+func withDefaults(t *testing.T, fsys fstest.MapFS) {
+	t.Helper()
+	SetDefaults(fsys)
+	t.Cleanup(func() { SetDefaults(nil) })
+}
+
+/**
+ * Tests that on-disk files override built-in ones file by file.
+ */
+func TestLoadTabs_Overlay(t *testing.T) {
+	withDefaults(t, fstest.MapFS{
+		"welcome.md":             {Data: []byte("# Welcome\n\nBuilt-in welcome")},
+		"about.md":               {Data: []byte("# About\n\nBuilt-in about")},
+		"projects.md":            {Data: []byte("# Projects\n\nBuilt-in projects")},
+		"future.md":              {Data: []byte("# Future\n\nBuilt-in future")},
+		"blog/2026-01-01-one.md": {Data: []byte("# Built-in post")},
+	})
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "welcome.md"), []byte("# Welcome\n\nDisk welcome"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tabs, err := LoadTabs(dir)
+	if err != nil {
+		t.Fatalf("LoadTabs failed: %v", err)
+	}
+	if len(tabs) != 5 {
+		t.Fatalf("Expected 5 tabs, got %d", len(tabs))
+	}
+
+	if !strings.Contains(ansi.Strip(tabs[0].Content), "Disk welcome") {
+		t.Errorf("Expected welcome.md from disk, got %q", tabs[0].Content)
+	}
+	if !strings.Contains(ansi.Strip(tabs[1].Content), "Built-in about") {
+		t.Errorf("Expected built-in about.md, got %q", tabs[1].Content)
+	}
+	if tabs[3].Name != "Blog" || len(tabs[3].Posts) != 1 {
+		t.Errorf("Expected blog tab with built-in post, got %+v", tabs[3])
+	}
+	if Degraded(dir) {
+		t.Error("Expected readable directory not to be degraded")
+	}
+}
+
+/**
+ * Tests that a missing content directory falls back to defaults.
+ */
+func TestLoadTabs_MissingDirectory(t *testing.T) {
+	withDefaults(t, fstest.MapFS{
+		"welcome.md": {Data: []byte("# Welcome\n\nBuilt-in welcome")},
+	})
+
+	dir := filepath.Join(t.TempDir(), "missing")
+	if !Degraded(dir) {
+		t.Error("Expected missing directory to be degraded")
+	}
+
+	tabs, err := LoadTabs(dir)
+	if err != nil {
+		t.Fatalf("LoadTabs failed: %v", err)
+	}
+	if !strings.Contains(ansi.Strip(tabs[0].Content), "Built-in welcome") {
+		t.Errorf("Expected built-in welcome.md, got %q", tabs[0].Content)
+	}
+	if !strings.Contains(ansi.Strip(tabs[1].Content), "coming soon") {
+		t.Errorf("Expected placeholder for file missing from both layers, got %q", tabs[1].Content)
+	}
+}
+
+/**
+ * Tests that an empty content directory counts as degraded.
+ */
+func TestDegraded_EmptyDirectory(t *testing.T) {
+	withDefaults(t, fstest.MapFS{
+		"welcome.md": {Data: []byte("# Welcome\n\nBuilt-in welcome")},
+	})
+
+	if !Degraded(t.TempDir()) {
+		t.Error("Expected empty directory to be degraded")
+	}
+}
+
+/**
+ * Tests that git exports are served without the built-in defaults,
+ * so pages deleted in the repository do not come back.
+ */
+func TestLoadTabs_GitExportNoDefaults(t *testing.T) {
+	withDefaults(t, fstest.MapFS{
+		"welcome.md": {Data: []byte("# Welcome\n\nBuilt-in welcome")},
+		"about.md":   {Data: []byte("# About\n\nBuilt-in about")},
+	})
+
+	repo := initContentRepo(t)
+	src, err := NewGitSource(repo, "main", "content", t.TempDir())
+	if err != nil {
+		t.Fatalf("NewGitSource failed: %v", err)
+	}
+
+	tabs, err := LoadTabs(src.Dir())
+	if err != nil {
+		t.Fatalf("LoadTabs failed: %v", err)
+	}
+	if strings.Contains(ansi.Strip(tabs[1].Content), "Built-in about") {
+		t.Errorf("Expected no built-in about.md in a git export, got %q", tabs[1].Content)
+	}
+	if Degraded(src.Dir()) {
+		t.Error("Expected git export not to be degraded")
+	}
+}
+
+/**
+ * Tests that built-in pages, which have no modification time, leave
+ * out their "Last updated" line instead of printing it empty.
+ */
+func TestLoadTabs_DefaultsModified(t *testing.T) {
+	future, err := os.ReadFile(filepath.Join("..", "..", "content", "future.md"))
+	if err != nil {
+		t.Fatalf("Failed to read future.md: %v", err)
+	}
+	withDefaults(t, fstest.MapFS{"future.md": {Data: future}})
+
+	tabs, err := LoadTabs(filepath.Join(t.TempDir(), "missing"))
+	if err != nil {
+		t.Fatalf("LoadTabs failed: %v", err)
+	}
+	for _, tab := range tabs {
+		if strings.Contains(ansi.Strip(tab.Content), "Last updated") {
+			t.Errorf("Expected no \"Last updated\" line without a modification time, got %q", tab.Content)
+		}
+	}
+}
+
+/**
+ * Tests that Degraded is false when no defaults are registered.
+ */
+func TestDegraded_NoDefaults(t *testing.T) {
+	if Degraded(filepath.Join(t.TempDir(), "missing")) {
+		t.Error("Expected no degraded mode without defaults")
+	}
+}
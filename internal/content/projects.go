@@ -0,0 +1,119 @@
+package content
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/adamdeleeuw/ssh-portfolio/internal/tui"
+)
+
+/**
+ * On-disk representation of a project in projects.json.
+ */
+type projectEntry struct {
+	Name    string   `json:"name"`
+	Summary string   `json:"summary"`
+	Tech    []string `json:"tech"`
+	Repo    string   `json:"repo"`
+	Status  string   `json:"status"`
+	Date    string   `json:"date"` // YYYY-MM or YYYY-MM-DD
+}
+
+// Accepted layouts for the project date field
+var projectDateLayouts = []string{"2006-01-02", "2006-01", "2006"}
+
+/**
+ * Loads the structured projects catalogue from a JSON file.
+ * @param path - Path to projects.json
+ * @return Parsed projects in file order
+ * @return error if the file cannot be read or is malformed
+ */
+func LoadProjects(path string) ([]tui.Project, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseProjects(data, path)
+}
+
+/**
+ * Parses a projects catalogue.
+ * @param data - JSON contents
+ * @param path - File name used in error messages
+ * @return Parsed projects in file order
+ * @return error if the data is malformed
+ */
+func parseProjects(data []byte, path string) ([]tui.Project, error) {
+	var entries []projectEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	projects := make([]tui.Project, 0, len(entries))
+	for i, e := range entries {
+		if e.Name == "" {
+			return nil, fmt.Errorf("project %d in %s has no name", i+1, path)
+		}
+
+		var date time.Time
+		if e.Date != "" {
+			var err error
+			date, err = parseProjectDate(e.Date)
+			if err != nil {
+				return nil, fmt.Errorf("project %q in %s: %w", e.Name, path, err)
+			}
+		}
+
+		projects = append(projects, tui.Project{
+			Name:    e.Name,
+			Summary: e.Summary,
+			Tech:    e.Tech,
+			Repo:    e.Repo,
+			Status:  e.Status,
+			Date:    date,
+		})
+	}
+
+	return projects, nil
+}
+
+/**
+ * Describes the catalogue as markdown, one section per project,
+ * so changes can be tracked like any other page.
+ * @param projects - Parsed projects
+ * @return Markdown summary
+ */
+func projectsSource(projects []tui.Project) string {
+	var b strings.Builder
+	for _, p := range projects {
+		fmt.Fprintf(&b, "## %s\n\n%s\n\n", p.Name, p.Summary)
+		for _, field := range []string{strings.Join(p.Tech, ", "), p.Repo, p.Status} {
+			if field != "" {
+				fmt.Fprintf(&b, "%s\n", field)
+			}
+		}
+		if !p.Date.IsZero() {
+			fmt.Fprintf(&b, "%s\n", p.Date.Format("2006-01-02"))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+/**
+ * Parses a project date in any of the accepted layouts.
+ * @param s - Date string from projects.json
+ * @return Parsed date
+ * @return error if no layout matches
+ */
+func parseProjectDate(s string) (time.Time, error) {
+	for _, layout := range projectDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q (want YYYY-MM-DD, YYYY-MM or YYYY)", s)
+}
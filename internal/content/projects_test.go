@@ -0,0 +1,80 @@
+package content
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+/**
+ * Tests parsing a structured projects catalogue.
+ */
+func TestLoadProjects(t *testing.T) {
+	tempDir := t.TempDir()
+	path := filepath.Join(tempDir, "projects.json")
+
+	data := `[
+		{"name": "SSH Portfolio", "summary": "This app", "tech": ["Go", "Bubble Tea"], "repo": "https://example.com/a", "status": "active", "date": "2026-02"},
+		{"name": "Allocator", "tech": ["C"], "date": "2025-11-03"}
+	]`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to write projects.json: %v", err)
+	}
+
+	projects, err := LoadProjects(path)
+	if err != nil {
+		t.Fatalf("LoadProjects failed: %v", err)
+	}
+
+	if len(projects) != 2 {
+		t.Fatalf("Expected 2 projects, got %d", len(projects))
+	}
+
+	if projects[0].Name != "SSH Portfolio" || len(projects[0].Tech) != 2 {
+		t.Errorf("Unexpected first project: %+v", projects[0])
+	}
+
+	if projects[1].Date.Day() != 3 {
+		t.Errorf("Expected full date to be parsed, got %v", projects[1].Date)
+	}
+}
+
+/**
+ * Tests that malformed dates are reported.
+ */
+func TestLoadProjects_InvalidDate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "projects.json")
+	if err := os.WriteFile(path, []byte(`[{"name": "X", "date": "last year"}]`), 0644); err != nil {
+		t.Fatalf("Failed to write projects.json: %v", err)
+	}
+
+	if _, err := LoadProjects(path); err == nil {
+		t.Error("Expected error for invalid date")
+	}
+}
+
+/**
+ * Tests that LoadTabs prefers projects.json over projects.md.
+ */
+func TestLoadTabs_ProjectsData(t *testing.T) {
+	tempDir := t.TempDir()
+
+	files := map[string]string{
+		"projects.md":   "# Projects\n\nMarkdown fallback",
+		"projects.json": `[{"name": "Structured", "tech": ["Go"]}]`,
+	}
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(body), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	tabs, err := LoadTabs(tempDir)
+	if err != nil {
+		t.Fatalf("LoadTabs failed: %v", err)
+	}
+
+	if len(tabs[2].Projects) != 1 || tabs[2].Projects[0].Name != "Structured" {
+		t.Errorf("Expected structured projects on Projects tab, got %+v", tabs[2])
+	}
+}
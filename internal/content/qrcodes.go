@@ -0,0 +1,194 @@
+package content
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+
+	"github.com/adamdeleeuw/ssh-portfolio/internal/qr"
+	"github.com/adamdeleeuw/ssh-portfolio/internal/tui"
+)
+
+// Prefix of the paragraphs that stand in for QR codes during rendering
+const qrPlaceholder = "sshportfolioqr"
+
+/**
+ * Replaces ```qr fenced blocks with placeholder paragraphs the TUI
+ * swaps for codes at view time. The body is a URL, email address or
+ * other text; ```qr vcard takes "key: value" lines (name, email, phone,
+ * url, title, org) and encodes a contact card. Line count is preserved
+ * so later errors still point at the right line.
+ * @param body - Markdown after template expansion
+ * @return Markdown with placeholders
+ * @return Codes keyed by placeholder
+ * @return error naming the line of the first invalid block
+ */
+func extractQRCodes(body string) (string, map[string]tui.QRCode, error) {
+	lines := strings.Split(body, "\n")
+	codes := make(map[string]tui.QRCode)
+
+	var out []string
+	for i := 0; i < len(lines); i++ {
+		fence, info, ok := parseFence(lines[i])
+		if !ok {
+			out = append(out, lines[i])
+			continue
+		}
+
+		end := len(lines)
+		for j := i + 1; j < len(lines); j++ {
+			if isClosingFence(lines[j], fence) {
+				end = j
+				break
+			}
+		}
+		last := min(end, len(lines)-1)
+
+		fields := strings.Fields(info)
+		if len(fields) == 0 || !strings.EqualFold(fields[0], "qr") {
+			// Other code blocks (including ones showing a qr fence) are copied verbatim
+			out = append(out, lines[i:last+1]...)
+			i = last
+			continue
+		}
+
+		code, err := qrBlock(fields[1:], strings.Join(lines[i+1:min(end, len(lines))], "\n"))
+		if err != nil {
+			return "", nil, fmt.Errorf("line %d: qr block: %w", i+1, err)
+		}
+
+		key := fmt.Sprintf("%s%d", qrPlaceholder, len(codes))
+		codes[key] = code
+		out = append(out, "", key, "")
+		for range last - i + 1 - 3 {
+			out = append(out, "")
+		}
+		i = last
+	}
+
+	if len(codes) == 0 {
+		return body, nil, nil
+	}
+	return strings.Join(out, "\n"), codes, nil
+}
+
+/**
+ * Builds the code for one ```qr block.
+ * @param args - Words after "qr" in the fence
+ * @param body - Block contents
+ * @return Code ready for the TUI
+ * @return error if the block is empty, malformed or too long
+ */
+func qrBlock(args []string, body string) (tui.QRCode, error) {
+	if len(args) > 0 && strings.EqualFold(args[0], "vcard") {
+		card := make(map[string]string)
+		for _, line := range strings.Split(body, "\n") {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			key, value, ok := strings.Cut(line, ":")
+			if !ok {
+				return tui.QRCode{}, fmt.Errorf("expected \"key: value\", got %q", strings.TrimSpace(line))
+			}
+			card[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+		}
+		if card["name"] == "" {
+			return tui.QRCode{}, errors.New("vcard needs a name")
+		}
+		return newContactCode(card)
+	}
+	if len(args) > 0 {
+		return tui.QRCode{}, fmt.Errorf("unknown option %q", args[0])
+	}
+
+	text := strings.TrimSpace(body)
+	if text == "" {
+		return tui.QRCode{}, errors.New("nothing to encode")
+	}
+	return newQRCode(text)
+}
+
+/**
+ * Encodes a URL, email address or other text. Bare email addresses
+ * get a mailto: scheme so phones offer to write a message.
+ * @param text - Text to encode
+ * @return Code labelled with the text, minus any scheme
+ * @return error if the text is too long for a QR code
+ */
+func newQRCode(text string) (tui.QRCode, error) {
+	data, label := text, text
+	if u, err := url.Parse(text); err == nil && u.Scheme != "" {
+		label = strings.TrimPrefix(strings.TrimPrefix(text, u.Scheme+":"), "//")
+	} else if addr, err := mail.ParseAddress(text); err == nil && addr.Address == text {
+		data = "mailto:" + text
+	}
+
+	code, err := qr.Encode(data, qr.Medium)
+	if err != nil {
+		return tui.QRCode{}, err
+	}
+	return tui.QRCode{Label: label, Text: text, Code: code}, nil
+}
+
+/**
+ * Encodes a contact card (vCard 3.0).
+ * @param card - Values keyed by name, email, phone, url, title and org
+ * @return Code labelled with the name
+ * @return error if the card is too long for a QR code
+ */
+func newContactCode(card map[string]string) (tui.QRCode, error) {
+	name := card["name"]
+	family, given := name, ""
+	if i := strings.LastIndex(name, " "); i >= 0 {
+		given, family = name[:i], name[i+1:]
+	}
+
+	lines := []string{"BEGIN:VCARD", "VERSION:3.0",
+		"N:" + vcardEscape(family) + ";" + vcardEscape(given) + ";;;",
+		"FN:" + vcardEscape(name),
+	}
+	text := []string{name}
+	for _, f := range []struct{ key, prop string }{
+		{"title", "TITLE"}, {"org", "ORG"}, {"email", "EMAIL"}, {"phone", "TEL"}, {"url", "URL"},
+	} {
+		if v := card[f.key]; v != "" {
+			lines = append(lines, f.prop+":"+vcardEscape(v))
+			text = append(text, v)
+		}
+	}
+	lines = append(lines, "END:VCARD")
+
+	code, err := qr.Encode(strings.Join(lines, "\r\n"), qr.Medium)
+	if err != nil {
+		return tui.QRCode{}, err
+	}
+	return tui.QRCode{Label: name + " (contact card)", Text: strings.Join(text, "\n"), Code: code}, nil
+}
+
+/**
+ * Escapes characters with special meaning in vCard values.
+ */
+func vcardEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ",", `\,`, ";", `\;`, "\n", `\n`).Replace(s)
+}
+
+/**
+ * Builds the contact code for a page from its "contact" front matter:
+ * a URL or email address shown full size with the c key.
+ * @param meta - Front matter
+ * @return Code, or nil if the page has none
+ * @return error if the value is too long for a QR code
+ */
+func pageContact(meta map[string]string) (*tui.QRCode, error) {
+	text := meta["contact"]
+	if text == "" {
+		return nil, nil
+	}
+	code, err := newQRCode(text)
+	if err != nil {
+		return nil, fmt.Errorf("contact: %w", err)
+	}
+	return &code, nil
+}
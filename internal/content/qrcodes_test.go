@@ -0,0 +1,72 @@
+package content
+
+import (
+	"strings"
+	"testing"
+)
+
+/**
+ * Tests qr blocks become placeholders without shifting later lines.
+ */
+func TestExtractQRCodes(t *testing.T) {
+	md := "Intro\n\n```qr\nhttps://example.com/me\n```\n\n```qr vcard\nname: Ada Lovelace\nemail: ada@example.com\n```\n\n````markdown\n```qr\nshown as code\n```\n````\nEnd"
+
+	out, codes, err := extractQRCodes(md)
+	if err != nil {
+		t.Fatalf("extractQRCodes failed: %v", err)
+	}
+	if len(codes) != 2 {
+		t.Fatalf("Expected 2 codes, got %d", len(codes))
+	}
+	if strings.Count(out, "\n") != strings.Count(md, "\n") {
+		t.Errorf("Expected line count to be preserved, got %q", out)
+	}
+	if !strings.Contains(out, "shown as code") {
+		t.Error("Expected qr fence inside a code block to be left alone")
+	}
+
+	url := codes[qrPlaceholder+"0"]
+	if url.Label != "example.com/me" || url.Code == nil {
+		t.Errorf("Unexpected URL code %+v", url)
+	}
+	card := codes[qrPlaceholder+"1"]
+	if card.Label != "Ada Lovelace (contact card)" || !strings.Contains(card.Text, "ada@example.com") {
+		t.Errorf("Unexpected contact card %+v", card)
+	}
+}
+
+/**
+ * Tests invalid blocks report the fence line.
+ */
+func TestExtractQRCodes_Errors(t *testing.T) {
+	tests := []string{
+		"# Title\n\n```qr\n```\n",
+		"# Title\n\n```qr vcard\nemail: a@example.com\n```\n",
+		"# Title\n\n```qr vcard\nname Ada\n```\n",
+		"# Title\n\n```qr huge\nx\n```\n",
+		"# Title\n\n```qr\n" + strings.Repeat("x", 3000) + "\n```\n",
+	}
+
+	for _, md := range tests {
+		if _, _, err := extractQRCodes(md); err == nil || !strings.Contains(err.Error(), "line 3") {
+			t.Errorf("Expected error at line 3 for %q, got %v", md, err)
+		}
+	}
+}
+
+/**
+ * Tests email addresses are encoded as mailto links and vCard values escaped.
+ */
+func TestNewQRCode(t *testing.T) {
+	code, err := newQRCode("ada@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code.Label != "ada@example.com" || code.Code == nil {
+		t.Errorf("Unexpected code %+v", code)
+	}
+
+	if got := vcardEscape(`a,b;c\d`); got != `a\,b\;c\\d` {
+		t.Errorf("vcardEscape = %q", got)
+	}
+}
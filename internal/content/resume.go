@@ -0,0 +1,289 @@
+package content
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+/**
+ * Subset of the JSON Resume schema (https://jsonresume.org/schema)
+ * used to generate the resume tabs.
+ */
+type Resume struct {
+	Basics    ResumeBasics      `json:"basics"`
+	Work      []ResumeWork      `json:"work,omitempty"`
+	Education []ResumeEducation `json:"education,omitempty"`
+	Skills    []ResumeSkill     `json:"skills,omitempty"`
+
+	raw json.RawMessage // Document the resume was parsed from, written back by JSON
+}
+
+/**
+ * Personal details from the "basics" section.
+ */
+type ResumeBasics struct {
+	Name     string          `json:"name"`
+	Label    string          `json:"label,omitempty"`
+	Email    string          `json:"email,omitempty"`
+	Phone    string          `json:"phone,omitempty"`
+	URL      string          `json:"url,omitempty"`
+	Summary  string          `json:"summary,omitempty"`
+	Location ResumeLocation  `json:"location"`
+	Profiles []ResumeProfile `json:"profiles,omitempty"`
+}
+
+/**
+ * Location from the "basics" section.
+ */
+type ResumeLocation struct {
+	City        string `json:"city,omitempty"`
+	Region      string `json:"region,omitempty"`
+	CountryCode string `json:"countryCode,omitempty"`
+}
+
+/**
+ * Social profile from the "basics" section.
+ */
+type ResumeProfile struct {
+	Network  string `json:"network"`
+	Username string `json:"username,omitempty"`
+	URL      string `json:"url,omitempty"`
+}
+
+/**
+ * Entry in the "work" section.
+ */
+type ResumeWork struct {
+	Name       string   `json:"name"`
+	Position   string   `json:"position,omitempty"`
+	URL        string   `json:"url,omitempty"`
+	StartDate  string   `json:"startDate,omitempty"`
+	EndDate    string   `json:"endDate,omitempty"`
+	Summary    string   `json:"summary,omitempty"`
+	Highlights []string `json:"highlights,omitempty"`
+}
+
+/**
+ * Entry in the "education" section.
+ */
+type ResumeEducation struct {
+	Institution string   `json:"institution"`
+	URL         string   `json:"url,omitempty"`
+	Area        string   `json:"area,omitempty"`
+	StudyType   string   `json:"studyType,omitempty"`
+	StartDate   string   `json:"startDate,omitempty"`
+	EndDate     string   `json:"endDate,omitempty"`
+	Score       string   `json:"score,omitempty"`
+	Courses     []string `json:"courses,omitempty"`
+}
+
+/**
+ * Entry in the "skills" section.
+ */
+type ResumeSkill struct {
+	Name     string   `json:"name"`
+	Level    string   `json:"level,omitempty"`
+	Keywords []string `json:"keywords,omitempty"`
+}
+
+/**
+ * Loads and validates a JSON Resume file.
+ * Unknown sections are ignored so full resumes from other tooling load
+ * as-is, and kept for JSON.
+ * @param path - Path to resume.json
+ * @return Parsed resume
+ * @return error if the file cannot be read, parsed or has no name
+ */
+func LoadResume(path string) (*Resume, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseResume(data, path)
+}
+
+/**
+ * Parses and validates a JSON Resume document.
+ * @param data - JSON contents
+ * @param path - File name used in error messages
+ * @return Parsed resume
+ * @return error if the data is malformed or has no name
+ */
+func parseResume(data []byte, path string) (*Resume, error) {
+	var r Resume
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	r.raw = append(json.RawMessage(nil), data...)
+
+	if r.Basics.Name == "" {
+		return nil, fmt.Errorf("%s: basics.name is required", path)
+	}
+
+	return &r, nil
+}
+
+/**
+ * Serializes the resume back to JSON Resume format.
+ * A resume parsed from a file is written back as it was, including
+ * sections and fields Resume does not model; only the indentation
+ * changes. Other resumes are encoded from their fields.
+ * @return Indented JSON document
+ * @return error if encoding fails
+ */
+func (r *Resume) JSON() ([]byte, error) {
+	if r.raw != nil {
+		var b bytes.Buffer
+		if err := json.Indent(&b, r.raw, "", "  "); err != nil {
+			return nil, err
+		}
+		b.WriteByte('\n')
+		return b.Bytes(), nil
+	}
+
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+/**
+ * Converts the resume to a single markdown document,
+ * suitable as a drop-in about.md or for pasting elsewhere.
+ * @return Markdown text
+ */
+func (r *Resume) Markdown() string {
+	var b strings.Builder
+	basics := r.Basics
+
+	fmt.Fprintf(&b, "# %s\n\n", basics.Name)
+	if basics.Label != "" {
+		fmt.Fprintf(&b, "*%s*\n\n", basics.Label)
+	}
+	if basics.Summary != "" {
+		fmt.Fprintf(&b, "%s\n\n", basics.Summary)
+	}
+
+	if len(r.Work) > 0 {
+		b.WriteString("## Experience\n\n")
+		for _, w := range r.Work {
+			fmt.Fprintf(&b, "### %s\n\n", joinNonEmpty(" - ", w.Position, w.Name))
+			if dates := resumeDateRange(w.StartDate, w.EndDate); dates != "" {
+				fmt.Fprintf(&b, "*%s*\n\n", dates)
+			}
+			if w.Summary != "" {
+				fmt.Fprintf(&b, "%s\n\n", w.Summary)
+			}
+			for _, h := range w.Highlights {
+				fmt.Fprintf(&b, "- %s\n", h)
+			}
+			if len(w.Highlights) > 0 {
+				b.WriteString("\n")
+			}
+		}
+	}
+
+	if len(r.Education) > 0 {
+		b.WriteString("## Education\n\n")
+		for _, e := range r.Education {
+			fmt.Fprintf(&b, "### %s\n\n", e.Institution)
+			if degree := joinNonEmpty(", ", e.StudyType, e.Area); degree != "" {
+				fmt.Fprintf(&b, "%s\n\n", degree)
+			}
+			if dates := resumeDateRange(e.StartDate, e.EndDate); dates != "" {
+				fmt.Fprintf(&b, "*%s*\n\n", dates)
+			}
+		}
+	}
+
+	if len(r.Skills) > 0 {
+		b.WriteString("## Skills\n\n")
+		for _, s := range r.Skills {
+			line := "- **" + s.Name + "**"
+			if len(s.Keywords) > 0 {
+				line += ": " + strings.Join(s.Keywords, ", ")
+			}
+			b.WriteString(line + "\n")
+		}
+		b.WriteString("\n")
+	}
+
+	var contact []string
+	if basics.Email != "" {
+		contact = append(contact, fmt.Sprintf("- **Email:** [%s](mailto:%s)", basics.Email, basics.Email))
+	}
+	if basics.URL != "" {
+		contact = append(contact, fmt.Sprintf("- **Website:** [%s](%s)", basics.URL, basics.URL))
+	}
+	for _, p := range basics.Profiles {
+		if p.URL != "" {
+			contact = append(contact, fmt.Sprintf("- **%s:** [%s](%s)", p.Network, firstNonEmpty(p.Username, p.URL), p.URL))
+		}
+	}
+	if len(contact) > 0 {
+		b.WriteString("## Contact\n\n")
+		b.WriteString(strings.Join(contact, "\n"))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+/**
+ * Formats a JSON Resume start/end pair as "Sep 2024 – Present".
+ * @param start - ISO 8601 start date (may be empty)
+ * @param end - ISO 8601 end date (empty means ongoing)
+ * @return Human-readable range
+ */
+func resumeDateRange(start, end string) string {
+	format := func(s string) string {
+		t, err := parseProjectDate(s)
+		if err != nil {
+			return s
+		}
+		if len(s) == 4 {
+			return t.Format("2006")
+		}
+		return t.Format("Jan 2006")
+	}
+
+	if start == "" && end == "" {
+		return ""
+	}
+	if end == "" {
+		return format(start) + " – Present"
+	}
+	if start == "" {
+		return format(end)
+	}
+	return format(start) + " – " + format(end)
+}
+
+/**
+ * Joins the non-empty parts with a separator.
+ */
+func joinNonEmpty(sep string, parts ...string) string {
+	var out []string
+	for _, p := range parts {
+		if p != "" {
+			out = append(out, p)
+		}
+	}
+	return strings.Join(out, sep)
+}
+
+/**
+ * Returns the first non-empty string, or "" if all are empty.
+ */
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,118 @@
+package content
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testResume = `{
+	"basics": {
+		"name": "Ada Lovelace",
+		"label": "Engineer",
+		"email": "ada@example.com",
+		"image": "https://example.com/ada.png",
+		"profiles": [{"network": "GitHub", "username": "ada", "url": "https://github.com/ada"}]
+	},
+	"work": [{"name": "Analytical Co", "position": "Programmer", "startDate": "2024-09", "highlights": ["Wrote the first program"]}],
+	"education": [{"institution": "UBC", "area": "Computer Engineering", "studyType": "BASc", "startDate": "2023"}],
+	"skills": [{"name": "Go", "level": "Advanced", "keywords": ["Bubble Tea"]}],
+	"volunteer": [{"organization": "ignored"}]
+}`
+
+/**
+ * Writes the test resume into a temp directory and returns the directory.
+ */
+func writeTestResume(t *testing.T) string {
+	tempDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tempDir, "resume.json"), []byte(testResume), 0644); err != nil {
+		t.Fatalf("Failed to write resume.json: %v", err)
+	}
+	return tempDir
+}
+
+/**
+ * Tests that resume.json replaces the About tab with the resume tabs.
+ */
+func TestLoadTabs_Resume(t *testing.T) {
+	tabs, err := LoadTabs(writeTestResume(t))
+	if err != nil {
+		t.Fatalf("LoadTabs failed: %v", err)
+	}
+
+	var names []string
+	for _, tab := range tabs {
+		names = append(names, tab.Name)
+	}
+
+	expected := "Welcome,About,Experience,Education,Skills,Projects,Future"
+	if strings.Join(names, ",") != expected {
+		t.Errorf("Expected tabs %s, got %s", expected, strings.Join(names, ","))
+	}
+
+	if !strings.Contains(tabs[2].Content, "Wrote the first program") {
+		t.Error("Experience tab should contain work highlights")
+	}
+	if tabs[1].Contact == nil || !strings.Contains(tabs[1].Contact.Text, "ada@example.com") {
+		t.Error("About tab should carry a contact card QR code")
+	}
+}
+
+/**
+ * Tests that a resume without a name is rejected.
+ */
+func TestLoadResume_MissingName(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "resume.json")
+	if err := os.WriteFile(path, []byte(`{"basics": {}}`), 0644); err != nil {
+		t.Fatalf("Failed to write resume.json: %v", err)
+	}
+
+	if _, err := LoadResume(path); err == nil {
+		t.Error("Expected error for resume without basics.name")
+	}
+}
+
+/**
+ * Tests JSON and markdown export.
+ */
+func TestResumeExport(t *testing.T) {
+	resume, err := LoadResume(filepath.Join(writeTestResume(t), "resume.json"))
+	if err != nil {
+		t.Fatalf("LoadResume failed: %v", err)
+	}
+
+	data, err := resume.JSON()
+	if err != nil {
+		t.Fatalf("JSON export failed: %v", err)
+	}
+
+	var roundTrip Resume
+	if err := json.Unmarshal(data, &roundTrip); err != nil {
+		t.Fatalf("Exported JSON is invalid: %v", err)
+	}
+	if roundTrip.Basics.Name != "Ada Lovelace" || len(roundTrip.Work) != 1 {
+		t.Errorf("Round trip lost data: %+v", roundTrip)
+	}
+
+	// Fields and sections Resume does not model come back unchanged
+	var original, exported map[string]any
+	if err := json.Unmarshal([]byte(testResume), &original); err != nil {
+		t.Fatalf("Test resume is invalid: %v", err)
+	}
+	if err := json.Unmarshal(data, &exported); err != nil {
+		t.Fatalf("Exported JSON is invalid: %v", err)
+	}
+	if !reflect.DeepEqual(original, exported) {
+		t.Errorf("Expected export to match the original document, got %s", data)
+	}
+
+	md := resume.Markdown()
+	for _, want := range []string{"# Ada Lovelace", "### Programmer - Analytical Co", "*Sep 2024 – Present*", "- **Go**: Bubble Tea"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown export missing %q", want)
+		}
+	}
+}
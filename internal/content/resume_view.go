@@ -0,0 +1,256 @@
+package content
+
+import (
+	"strings"
+
+	"github.com/adamdeleeuw/ssh-portfolio/internal/tui"
+	"github.com/charmbracelet/lipgloss"
+)
+
+/**
+ * Builds the About, Experience, Education and Skills tabs from a resume.
+ * Sections with no entries are skipped.
+ * @param r - Parsed resume
+ * @return Tabs in display order
+ */
+func ResumeTabs(r *Resume) []tui.Tab {
+	md := r.Markdown()
+	about := resumeSection(md, "") + resumeSection(md, "Contact")
+	tabs := []tui.Tab{{Name: "About", Content: renderResumeAbout(r), Source: about, Contact: resumeContact(r.Basics)}}
+
+	if len(r.Work) > 0 {
+		tabs = append(tabs, tui.Tab{Name: "Experience", Content: renderResumeWork(r), Source: resumeSection(md, "Experience")})
+	}
+	if len(r.Education) > 0 {
+		tabs = append(tabs, tui.Tab{Name: "Education", Content: renderResumeEducation(r), Source: resumeSection(md, "Education")})
+	}
+	if len(r.Skills) > 0 {
+		tabs = append(tabs, tui.Tab{Name: "Skills", Content: renderResumeSkills(r), Source: resumeSection(md, "Skills")})
+	}
+
+	return tabs
+}
+
+/**
+ * Cuts one "## " section out of the resume's markdown form.
+ * @param md - Output of Resume.Markdown
+ * @param heading - Section heading, or "" for the text before the first section
+ * @return Section text without its "## " heading line ("" if absent)
+ */
+func resumeSection(md, heading string) string {
+	sections := strings.Split("\n"+md, "\n## ")
+	if heading == "" {
+		return strings.TrimPrefix(sections[0], "\n")
+	}
+	for _, s := range sections[1:] {
+		if title, body, _ := strings.Cut(s, "\n"); title == heading {
+			return body
+		}
+	}
+	return ""
+}
+
+/**
+ * Builds a contact card QR code from the resume basics.
+ * @return Code, or nil if there are no contact details or they do not fit
+ */
+func resumeContact(b ResumeBasics) *tui.QRCode {
+	if b.Name == "" || b.Email == "" && b.Phone == "" && b.URL == "" {
+		return nil
+	}
+	code, err := newContactCode(map[string]string{
+		"name": b.Name, "title": b.Label, "email": b.Email, "phone": b.Phone, "url": b.URL,
+	})
+	if err != nil {
+		return nil
+	}
+	return &code
+}
+
+/**
+ * Renders the About page: name card, summary and contact details.
+ */
+func renderResumeAbout(r *Resume) string {
+	basics := r.Basics
+	var blocks []string
+
+	blocks = append(blocks, titleStyle.Render(strings.ToUpper(basics.Name)))
+	if basics.Label != "" {
+		blocks = append(blocks, subtitleStyle.Render(basics.Label))
+	}
+	if loc := joinNonEmpty(", ", basics.Location.City, basics.Location.Region, basics.Location.CountryCode); loc != "" {
+		blocks = append(blocks, mutedStyle.Render("📍 "+loc))
+	}
+
+	if basics.Summary != "" {
+		blocks = append(blocks, sectionStyle.Render("Summary"))
+		blocks = append(blocks, bodyStyle.Width(layoutWidth).Render(basics.Summary))
+	}
+
+	var contact []string
+	row := func(label, value string) {
+		if value != "" {
+			contact = append(contact, lipgloss.JoinHorizontal(lipgloss.Top, labelStyle.Render(label), linkStyle.Render(value)))
+		}
+	}
+	row("Email", basics.Email)
+	row("Phone", basics.Phone)
+	row("Website", basics.URL)
+	for _, p := range basics.Profiles {
+		row(p.Network, firstNonEmpty(p.URL, p.Username))
+	}
+	if len(contact) > 0 {
+		blocks = append(blocks, sectionStyle.Render("Contact"))
+		blocks = append(blocks, strings.Join(contact, "\n"))
+	}
+
+	return pageStyle.Render(strings.Join(blocks, "\n"))
+}
+
+/**
+ * Renders the Experience page as a vertical timeline of positions.
+ */
+func renderResumeWork(r *Resume) string {
+	blocks := []string{titleStyle.Render("EXPERIENCE")}
+
+	for i, w := range r.Work {
+		heading := titleStyle.Render(firstNonEmpty(w.Position, w.Name))
+		if w.Position != "" && w.Name != "" {
+			heading += subtitleStyle.Render(" @ " + w.Name)
+		}
+
+		body := []string{heading, mutedStyle.Render(resumeDateRange(w.StartDate, w.EndDate))}
+		if w.Summary != "" {
+			body = append(body, bodyStyle.Width(layoutWidth-4).Render(w.Summary))
+		}
+		for _, h := range w.Highlights {
+			body = append(body, lipgloss.JoinHorizontal(lipgloss.Top,
+				meterStyle.Render("▸ "),
+				bodyStyle.Width(layoutWidth-6).Render(h),
+			))
+		}
+
+		// Timeline rail: a node for each entry, a connector down to the next one
+		content := strings.Join(body, "\n")
+		rail := []string{meterStyle.Render("●")}
+		connector := " "
+		if i < len(r.Work)-1 {
+			connector = mutedStyle.Render("│")
+		}
+		for n := 1; n < lipgloss.Height(content); n++ {
+			rail = append(rail, connector)
+		}
+
+		blocks = append(blocks, "", lipgloss.JoinHorizontal(lipgloss.Top, strings.Join(rail, "\n"), "  ", content))
+	}
+
+	return pageStyle.Render(strings.Join(blocks, "\n"))
+}
+
+/**
+ * Renders the Education page with degree details and course chips.
+ */
+func renderResumeEducation(r *Resume) string {
+	blocks := []string{titleStyle.Render("EDUCATION")}
+
+	for _, e := range r.Education {
+		header := lipgloss.JoinHorizontal(lipgloss.Top,
+			lipgloss.NewStyle().Width(layoutWidth-24).Render(titleStyle.Render(e.Institution)),
+			mutedStyle.Width(24).Align(lipgloss.Right).Render(resumeDateRange(e.StartDate, e.EndDate)),
+		)
+		blocks = append(blocks, sectionStyle.Width(layoutWidth).Render(header))
+
+		if degree := joinNonEmpty(" in ", e.StudyType, e.Area); degree != "" {
+			blocks = append(blocks, subtitleStyle.Render(degree))
+		}
+		if e.Score != "" {
+			blocks = append(blocks, lipgloss.JoinHorizontal(lipgloss.Top, labelStyle.Render("Score"), bodyStyle.Render(e.Score)))
+		}
+		if len(e.Courses) > 0 {
+			blocks = append(blocks, "", renderChips(e.Courses, layoutWidth))
+		}
+	}
+
+	return pageStyle.Render(strings.Join(blocks, "\n"))
+}
+
+/**
+ * Renders the Skills page as a level meter plus keyword chips per skill.
+ */
+func renderResumeSkills(r *Resume) string {
+	blocks := []string{titleStyle.Render("SKILLS"), ""}
+
+	nameWidth := 0
+	for _, s := range r.Skills {
+		if w := lipgloss.Width(s.Name); w > nameWidth {
+			nameWidth = w
+		}
+	}
+	nameWidth += 2
+
+	for _, s := range r.Skills {
+		row := lipgloss.JoinHorizontal(lipgloss.Top,
+			titleStyle.Width(nameWidth).Render(s.Name),
+			renderSkillLevel(s.Level),
+		)
+		blocks = append(blocks, row)
+		if len(s.Keywords) > 0 {
+			blocks = append(blocks, lipgloss.NewStyle().PaddingLeft(nameWidth).Render(renderChips(s.Keywords, layoutWidth-nameWidth)))
+		}
+		blocks = append(blocks, "")
+	}
+
+	return pageStyle.Render(strings.Join(blocks, "\n"))
+}
+
+// Skill levels in increasing order, as commonly used in JSON Resume files
+var skillLevels = []string{"beginner", "intermediate", "advanced", "expert"}
+
+/**
+ * Renders a skill level as a four-step meter followed by its label.
+ * Unknown levels are shown as plain text.
+ */
+func renderSkillLevel(level string) string {
+	if level == "" {
+		return ""
+	}
+
+	filled := 0
+	for i, l := range skillLevels {
+		if strings.EqualFold(level, l) || (l == "expert" && strings.EqualFold(level, "master")) {
+			filled = i + 1
+		}
+	}
+	if filled == 0 {
+		return mutedStyle.Render(level)
+	}
+
+	meter := meterStyle.Render(strings.Repeat("■", filled)) +
+		mutedStyle.Render(strings.Repeat("□", len(skillLevels)-filled))
+	return meter + "  " + mutedStyle.Render(level)
+}
+
+/**
+ * Lays out chips left to right, wrapping at the given width.
+ */
+func renderChips(items []string, width int) string {
+	var lines []string
+	var line string
+
+	for _, item := range items {
+		chip := chipStyle.Render(item)
+		if line != "" && lipgloss.Width(line)+1+lipgloss.Width(chip) > width {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += chip
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
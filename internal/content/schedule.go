@@ -0,0 +1,166 @@
+package content
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"time"
+)
+
+// Returned by renderFile for pages the session is not allowed to see
+var errHidden = errors.New("page is not published")
+
+// Accepted layouts for publish_at / expire_at, most specific first.
+// Values without a zone are interpreted in the server's local time.
+var scheduleLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+/**
+ * Publication state of a page derived from its front matter.
+ */
+type publishState int
+
+const (
+	statePublished publishState = iota // Visible to everyone
+	stateDraft                         // draft: true
+	stateScheduled                     // publish_at is in the future
+	stateExpired                       // expire_at has passed
+)
+
+/**
+ * Determines whether a page is live at the given time.
+ * @param meta - Page front matter
+ * @param now - Time to evaluate against
+ * @return Publication state
+ * @return error if draft, publish_at or expire_at are malformed
+ */
+func pageState(meta map[string]string, now time.Time) (publishState, error) {
+	if v, ok := meta["draft"]; ok {
+		draft, err := strconv.ParseBool(v)
+		if err != nil {
+			return statePublished, fmt.Errorf("draft: expected true or false, got %q", v)
+		}
+		if draft {
+			return stateDraft, nil
+		}
+	}
+
+	publishAt, err := scheduleTime(meta, "publish_at")
+	if err != nil {
+		return statePublished, err
+	}
+	expireAt, err := scheduleTime(meta, "expire_at")
+	if err != nil {
+		return statePublished, err
+	}
+
+	if !expireAt.IsZero() && !now.Before(expireAt) {
+		return stateExpired, nil
+	}
+	if !publishAt.IsZero() && now.Before(publishAt) {
+		return stateScheduled, nil
+	}
+	return statePublished, nil
+}
+
+/**
+ * Reports whether a page in the given state is shown to the session.
+ * Admins additionally preview drafts and scheduled pages;
+ * expired pages are hidden from everyone.
+ */
+func (s publishState) visibleTo(sess Session) bool {
+	switch s {
+	case statePublished:
+		return true
+	case stateDraft, stateScheduled:
+		return sess.Admin
+	}
+	return false
+}
+
+/**
+ * Short marker appended to the titles of pages only admins can see.
+ */
+func (s publishState) label() string {
+	switch s {
+	case stateDraft:
+		return "draft"
+	case stateScheduled:
+		return "scheduled"
+	}
+	return ""
+}
+
+/**
+ * Parses a schedule timestamp from front matter.
+ * @param meta - Page front matter
+ * @param key - "publish_at" or "expire_at"
+ * @return Parsed time, or zero if the key is absent
+ * @return error if the value matches no accepted layout
+ */
+func scheduleTime(meta map[string]string, key string) (time.Time, error) {
+	v := meta[key]
+	if v == "" {
+		return time.Time{}, nil
+	}
+
+	for _, layout := range scheduleLayouts {
+		if t, err := time.ParseInLocation(layout, v, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%s: invalid time %q (want RFC 3339, \"YYYY-MM-DD HH:MM\" or YYYY-MM-DD)", key, v)
+}
+
+/**
+ * Finds the next moment any page or post is due to appear or disappear,
+ * so live sessions can reload exactly then. The pages checked are the
+ * ones the tab manifest shows, wherever they are.
+ * @param contentDir - Content directory
+ * @param now - Current time
+ * @return Earliest publish_at/expire_at after now, or zero if none
+ */
+func NextChange(contentDir string, now time.Time) time.Time {
+	fsys := contentFS(contentDir)
+	specs, err := loadManifest(fsys)
+	if err != nil {
+		return time.Time{}
+	}
+
+	var files []string
+	for _, spec := range specs {
+		switch {
+		case spec.File != "":
+			files = append(files, spec.File)
+		case spec.Blog:
+			posts, _ := fs.Glob(fsys, "blog/*.md")
+			files = append(files, posts...)
+		}
+	}
+
+	var next time.Time
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			continue
+		}
+		meta, _, err := parseFrontMatter(string(data))
+		if err != nil {
+			continue
+		}
+
+		for _, key := range []string{"publish_at", "expire_at"} {
+			t, err := scheduleTime(meta, key)
+			if err == nil && t.After(now) && (next.IsZero() || t.Before(next)) {
+				next = t
+			}
+		}
+	}
+
+	return next
+}
@@ -0,0 +1,127 @@
+package content
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+/**
+ * Tests publication state from draft, publish_at and expire_at.
+ */
+func TestPageState(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		meta map[string]string
+		want publishState
+	}{
+		{map[string]string{}, statePublished},
+		{map[string]string{"draft": "true"}, stateDraft},
+		{map[string]string{"draft": "false"}, statePublished},
+		{map[string]string{"publish_at": "2026-03-02"}, stateScheduled},
+		{map[string]string{"publish_at": "2026-03-01 11:59"}, statePublished},
+		{map[string]string{"expire_at": "2026-03-01T12:00"}, stateExpired},
+		{map[string]string{"expire_at": "2026-04-01"}, statePublished},
+	}
+
+	for _, tt := range tests {
+		got, err := pageState(tt.meta, now)
+		if err != nil {
+			t.Errorf("pageState(%v) failed: %v", tt.meta, err)
+		}
+		if got != tt.want {
+			t.Errorf("pageState(%v) = %d, want %d", tt.meta, got, tt.want)
+		}
+	}
+
+	if _, err := pageState(map[string]string{"publish_at": "soon"}, now); err == nil {
+		t.Error("Expected error for invalid publish_at")
+	}
+}
+
+/**
+ * Tests drafts are hidden from visitors and marked for admins.
+ */
+func TestLoadSessionTabs_Drafts(t *testing.T) {
+	tempDir := t.TempDir()
+	files := map[string]string{
+		"about.md":  "---\ndraft: true\n---\n# About",
+		"future.md": "---\nexpire_at: 2000-01-01\n---\n# Old",
+	}
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(body), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	names := func(sess Session) string {
+		tabs, err := LoadSessionTabs(tempDir, sess)
+		if err != nil {
+			t.Fatalf("LoadSessionTabs failed: %v", err)
+		}
+		var out []string
+		for _, tab := range tabs {
+			out = append(out, tab.Name)
+		}
+		return strings.Join(out, ",")
+	}
+
+	if got := names(Session{}); got != "Welcome,Projects" {
+		t.Errorf("Visitor tabs: got %s", got)
+	}
+	if got := names(Session{Admin: true}); got != "Welcome,About (draft),Projects" {
+		t.Errorf("Admin tabs: got %s", got)
+	}
+}
+
+/**
+ * Tests the next scheduled change is found across pages and posts.
+ */
+func TestNextChange(t *testing.T) {
+	contentDir := writeTestPosts(t, map[string]string{
+		"post.md": "---\ndate: 2026-01-01\npublish_at: 2026-05-01\n---\nSoon",
+	})
+	if err := os.WriteFile(filepath.Join(contentDir, "welcome.md"), []byte("---\nexpire_at: 2026-04-01\n---\nHi"), 0644); err != nil {
+		t.Fatalf("Failed to write welcome.md: %v", err)
+	}
+
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local)
+	want := time.Date(2026, 4, 1, 0, 0, 0, 0, time.Local)
+	if got := NextChange(contentDir, now); !got.Equal(want) {
+		t.Errorf("Expected next change %v, got %v", want, got)
+	}
+
+	if got := NextChange(contentDir, want.Add(90*24*time.Hour)); !got.IsZero() {
+		t.Errorf("Expected no further changes, got %v", got)
+	}
+}
+
+/**
+ * Tests that pages in subdirectories named by the manifest are checked.
+ */
+func TestNextChange_Manifest(t *testing.T) {
+	contentDir := t.TempDir()
+	files := map[string]string{
+		"tabs.json":       `{"tabs": [{"name": "Talks", "file": "pages/talks.md"}]}`,
+		"pages/talks.md":  "---\npublish_at: 2026-05-01\n---\nSoon",
+		"pages/unused.md": "---\npublish_at: 2026-04-01\n---\nNot a tab",
+	}
+	for name, body := range files {
+		path := filepath.Join(contentDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local)
+	want := time.Date(2026, 5, 1, 0, 0, 0, 0, time.Local)
+	if got := NextChange(contentDir, now); !got.Equal(want) {
+		t.Errorf("Expected next change %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,212 @@
+package content
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adamdeleeuw/ssh-portfolio/internal/extproc"
+	"github.com/adamdeleeuw/ssh-portfolio/internal/probe"
+	"github.com/adamdeleeuw/ssh-portfolio/internal/tui"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// How often a status tab redraws; probes run on the tab's own interval
+const statusRefresh = 5 * time.Second
+
+// Results drawn in each latency sparkline
+const sparklineWidth = 30
+
+// Sparkline levels from fastest to slowest
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+// Monitors shared by every session showing the same probes
+var monitors = struct {
+	sync.Mutex
+	entries map[string]*probe.Monitor
+}{entries: make(map[string]*probe.Monitor)}
+
+/**
+ * Builds the tab for a status entry in the manifest. Probes run in the
+ * background on the tab's interval, once for all sessions; the tab
+ * redraws from their shared history. Probes connect out from the
+ * server or run programs, so they follow the same opt-in as commands.
+ * @param spec - Checked manifest entry
+ * @param contentDir - Directory command probes run in
+ * @return Tab driven by an interval panel
+ */
+func statusTab(spec tabSpec, contentDir string) tui.Tab {
+	if !commandsEnabled.Load() {
+		return tui.Tab{
+			Name:    spec.Name,
+			Content: fmt.Sprintf("Content for %s is unavailable.\n\nStatus tabs are disabled on this server.", spec.Name),
+		}
+	}
+
+	probes := statusProbes(spec, contentDir)
+	footer := fmt.Sprintf("⏱ Every %s • Last %d checks", spec.interval, spec.History)
+
+	monitor := monitorFor(spec, contentDir, probes)
+	return tui.Tab{Name: spec.Name, Panel: &intervalPanel{
+		load: func() (string, error) {
+			return renderStatus(monitor.Snapshot(), footer, time.Now()), nil
+		},
+		interval: statusRefresh,
+	}}
+}
+
+/**
+ * Builds the probes of a status entry.
+ * @param spec - Checked manifest entry
+ * @param contentDir - Directory command probes run in
+ * @return Probes to run
+ */
+func statusProbes(spec tabSpec, contentDir string) []probe.Probe {
+	probes := make([]probe.Probe, 0, len(spec.Probes))
+	for _, ps := range spec.Probes {
+		p := probe.Probe{Name: ps.Name, TCP: ps.TCP, HTTP: ps.HTTP, Timeout: ps.timeout}
+		if len(ps.Command) > 0 {
+			p.Command = &extproc.Command{Args: ps.Command, Dir: contentDir}
+		}
+		probes = append(probes, p)
+	}
+	return probes
+}
+
+/**
+ * Identifies the monitor of a status entry. The content directory is
+ * left out so a new export of the same manifest keeps its history.
+ */
+func monitorKey(spec tabSpec, probes []probe.Probe) string {
+	config, _ := json.Marshal(spec.Probes)
+	return fmt.Sprintf("%s\x00%s\x00%d\x00%d", config, spec.interval, len(probes), spec.History)
+}
+
+/**
+ * Returns the shared monitor for a status tab, creating it on first use.
+ */
+func monitorFor(spec tabSpec, contentDir string, probes []probe.Probe) *probe.Monitor {
+	key := monitorKey(spec, probes)
+
+	monitors.Lock()
+	defer monitors.Unlock()
+	m, ok := monitors.entries[key]
+	if !ok {
+		m = probe.NewMonitor(probes, spec.interval, spec.History)
+		monitors.entries[key] = m
+	}
+	return m
+}
+
+/**
+ * Renders the status page: overall state, then one row per service
+ * with its latest state, latency sparkline and uptime.
+ * @param statuses - Probe history from the monitor
+ * @param footer - Summary of the configuration
+ * @param now - Time the page is drawn, for the age of the last check
+ * @return Rendered page
+ */
+func renderStatus(statuses []probe.Status, footer string, now time.Time) string {
+	nameWidth := 0
+	down, checked := 0, 0
+	var last time.Time
+	for _, s := range statuses {
+		nameWidth = max(nameWidth, ansi.StringWidth(s.Name))
+		if r, ok := s.Latest(); ok {
+			checked++
+			if !r.Up {
+				down++
+			}
+			if r.At.After(last) {
+				last = r.At
+			}
+		}
+	}
+	nameWidth = min(nameWidth, 24)
+
+	var summary string
+	switch {
+	case checked == 0:
+		summary = mutedStyle.Render("Checking…")
+	case down == 0:
+		summary = meterStyle.Render("All systems operational")
+	default:
+		summary = errorStyle.Render(fmt.Sprintf("%d of %d %s down", down, len(statuses), plural(len(statuses), "service", "services")))
+	}
+
+	blocks := []string{titleStyle.Render("STATUS"), summary, ""}
+	for _, s := range statuses {
+		blocks = append(blocks, renderStatusRow(s, nameWidth)...)
+	}
+
+	if !last.IsZero() {
+		footer += fmt.Sprintf(" • Checked %s ago", now.Sub(last).Round(time.Second))
+	}
+	blocks = append(blocks, statsStyle.Render(footer))
+
+	return pageStyle.Render(strings.Join(blocks, "\n"))
+}
+
+/**
+ * Renders one service: a status row, plus the reason when it is down.
+ */
+func renderStatusRow(s probe.Status, nameWidth int) []string {
+	name := bodyStyle.Width(nameWidth).Render(ansi.Truncate(s.Name, nameWidth, "…"))
+
+	latest, ok := s.Latest()
+	if !ok {
+		return []string{mutedStyle.Render("○ ") + name + "  " + mutedStyle.Render("pending")}
+	}
+
+	dot, state, latency := meterStyle.Render("● "), meterStyle.Render("up  "), fmt.Sprintf("%5dms", latest.Latency.Milliseconds())
+	if !latest.Up {
+		dot, state, latency = errorStyle.Render("● "), errorStyle.Render("down"), "      —"
+	}
+
+	row := fmt.Sprintf("%s%s  %s  %s  %s  %s",
+		dot, name, state,
+		mutedStyle.Render(latency),
+		renderSparkline(s.Results),
+		bodyStyle.Render(fmt.Sprintf("%5.1f%%", s.Uptime()*100)),
+	)
+	rows := []string{row}
+	if !latest.Up && latest.Err != "" {
+		reason := ansi.Truncate(stripControls(strings.ReplaceAll(latest.Err, "\n", " ")), layoutWidth-nameWidth-6, "…")
+		rows = append(rows, strings.Repeat(" ", nameWidth+4)+mutedStyle.Render(reason))
+	}
+	return rows
+}
+
+/**
+ * Draws the latest results as a latency sparkline, scaled to the
+ * slowest successful check. Failed checks are drawn as red crosses.
+ */
+func renderSparkline(results []probe.Result) string {
+	if len(results) > sparklineWidth {
+		results = results[len(results)-sparklineWidth:]
+	}
+
+	var slowest time.Duration
+	for _, r := range results {
+		if r.Up {
+			slowest = max(slowest, r.Latency)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(mutedStyle.Render(strings.Repeat("·", sparklineWidth-len(results))))
+	for _, r := range results {
+		if !r.Up {
+			b.WriteString(errorStyle.Render("×"))
+			continue
+		}
+		level := 0
+		if slowest > 0 {
+			level = int(int64(r.Latency) * int64(len(sparkLevels)-1) / int64(slowest))
+		}
+		b.WriteString(meterStyle.Render(string(sparkLevels[level])))
+	}
+	return b.String()
+}
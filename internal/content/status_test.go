@@ -0,0 +1,146 @@
+package content
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adamdeleeuw/ssh-portfolio/internal/probe"
+	"github.com/charmbracelet/x/ansi"
+)
+
+/**
+ * Tests the status page summarises services and explains failures.
+ */
+func TestRenderStatus(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	statuses := []probe.Status{
+		{Name: "Blog", Results: []probe.Result{
+			{At: now.Add(-2 * time.Minute), Up: true, Latency: 40 * time.Millisecond},
+			{At: now.Add(-time.Minute), Up: true, Latency: 80 * time.Millisecond},
+		}},
+		{Name: "Demo", Results: []probe.Result{
+			{At: now.Add(-2 * time.Minute), Up: true, Latency: 10 * time.Millisecond},
+			{At: now.Add(-time.Minute), Up: false, Err: "dial tcp: connection \x1b[31mrefused"},
+		}},
+		{Name: "Game"},
+	}
+
+	out := ansi.Strip(renderStatus(statuses, "⏱ Every 1m", now))
+	for _, want := range []string{"1 of 3 services down", "Blog", "80ms", "100.0%", " 50.0%", "connection refused", "pending", "Checked 1m0s ago"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in output:\n%s", want, out)
+		}
+	}
+
+	out = ansi.Strip(renderStatus(statuses[:1], "", now))
+	if !strings.Contains(out, "All systems operational") {
+		t.Errorf("Expected all operational:\n%s", out)
+	}
+}
+
+/**
+ * Tests sparklines are padded to a fixed width and scaled to the slowest check.
+ */
+func TestRenderSparkline(t *testing.T) {
+	got := ansi.Strip(renderSparkline([]probe.Result{
+		{Up: true, Latency: 10 * time.Millisecond},
+		{Up: false},
+		{Up: true, Latency: 80 * time.Millisecond},
+	}))
+	want := strings.Repeat("·", sparklineWidth-3) + "▁×█"
+	if got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+/**
+ * Tests a status tab probes its services once for every session, and
+ * only when the operator allows commands.
+ */
+func TestLoadTabs_Status(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	dir := writeContent(t, map[string]string{
+		"tabs.json": `{"tabs": [{"name": "Status", "probes": [{"name": "Server", "tcp": "` + ln.Addr().String() + `"}]}]}`,
+	})
+
+	tabs, err := LoadTabs(dir)
+	if err != nil {
+		t.Fatalf("LoadTabs failed: %v", err)
+	}
+	if tabs[0].Panel != nil || !strings.Contains(tabs[0].Content, "disabled on this server") {
+		t.Fatalf("Expected a notice while commands are disabled, got %+v", tabs[0])
+	}
+
+	withCommands(t)
+	first, err := LoadTabs(dir)
+	if err != nil {
+		t.Fatalf("LoadTabs failed: %v", err)
+	}
+	second, err := LoadTabs(dir)
+	if err != nil {
+		t.Fatalf("LoadTabs failed: %v", err)
+	}
+	p1, ok1 := first[0].Panel.(*intervalPanel)
+	p2, ok2 := second[0].Panel.(*intervalPanel)
+	if !ok1 || !ok2 {
+		t.Fatalf("Expected status panels, got %+v", first)
+	}
+
+	p1.load()
+	var content string
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		content, _ = p2.load()
+		if strings.Contains(ansi.Strip(content), "operational") {
+			break
+		}
+	}
+
+	if out := ansi.Strip(content); !strings.Contains(out, "All systems operational") {
+		t.Errorf("Expected the shared monitor's results:\n%s", out)
+	}
+}
+
+/**
+ * Tests a new export of the same manifest reuses the monitor, and one
+ * that drops a status tab stops its monitor.
+ */
+func TestRetainShared(t *testing.T) {
+	withCommands(t)
+	manifest := `{"tabs": [{"name": "Status", "probes": [{"name": "Web", "http": "http://127.0.0.1:1/retain"}]}]}`
+	first := writeContent(t, map[string]string{"tabs.json": manifest})
+	second := writeContent(t, map[string]string{"tabs.json": manifest})
+
+	specs, err := loadManifest(contentFS(first))
+	if err != nil {
+		t.Fatal(err)
+	}
+	probes := statusProbes(specs[0], first)
+	key := monitorKey(specs[0], probes)
+
+	LoadTabs(first)
+	monitors.Lock()
+	m := monitors.entries[key]
+	monitors.Unlock()
+	LoadTabs(second)
+	monitors.Lock()
+	again := monitors.entries[key]
+	monitors.Unlock()
+	if m == nil || again != m {
+		t.Fatal("Expected both exports to share the monitor")
+	}
+
+	retainShared(nil, second)
+	monitors.Lock()
+	_, ok := monitors.entries[key]
+	monitors.Unlock()
+	if ok {
+		t.Error("Expected the monitor of the dropped tab to be removed")
+	}
+}
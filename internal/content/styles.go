@@ -0,0 +1,92 @@
+package content
+
+import "github.com/charmbracelet/lipgloss"
+
+// Tokyo Night palette, matching internal/tui/styles.go
+const (
+	colorBackground = "#1a1b26"
+	colorForeground = "#c0caf5"
+
+	colorAccent    = "#7aa2f7" // Headings
+	colorHighlight = "#bb9af7" // Links, emphasis
+	colorGreen     = "#9ece6a" // Positive values, bars
+	colorYellow    = "#e0af68" // Warnings
+	colorRed       = "#f7768e" // Errors, cautions
+
+	colorBorder = "#414868" // Borders, dividers
+	colorMuted  = "#565f89" // Dim text
+
+	colorSurface = "#292e42" // Empty calendar days
+)
+
+// Contribution calendar shades from no commits to the busiest days
+var heatColors = [...]string{colorSurface, "#394b70", "#41a6b5", "#73daca", colorGreen}
+
+// Width of generated (non-glamour) layouts, matching the glamour word wrap
+const layoutWidth = 96
+
+var (
+	// Outer margin so generated pages line up with glamour output
+	pageStyle = lipgloss.NewStyle().
+			Margin(1, 2)
+
+	// Large page title
+	titleStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color(colorAccent)).
+			Bold(true)
+
+	// Section heading with underline rule
+	sectionStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color(colorAccent)).
+			Bold(true).
+			BorderStyle(lipgloss.NormalBorder()).
+			BorderForeground(lipgloss.Color(colorBorder)).
+			BorderBottom(true).
+			MarginTop(1)
+
+	// Secondary line under a title (labels, roles)
+	subtitleStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color(colorHighlight)).
+			Italic(true)
+
+	// Plain body text
+	bodyStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color(colorForeground))
+
+	// Dates and other secondary details
+	mutedStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color(colorMuted))
+
+	// Key column in key/value listings
+	labelStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color(colorMuted)).
+			Width(12)
+
+	// URLs and e-mail addresses
+	linkStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color(colorHighlight)).
+			Underline(true)
+
+	// Keyword chip
+	chipStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color(colorBackground)).
+			Background(lipgloss.Color(colorAccent)).
+			Padding(0, 1)
+
+	// Failures shown in place of content (e.g. a command tab's program)
+	errorStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color(colorRed))
+
+	// Footer of a status tab, matching the stats bar in internal/tui
+	statsStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color(colorHighlight)).
+			BorderStyle(lipgloss.NormalBorder()).
+			BorderForeground(lipgloss.Color(colorBorder)).
+			BorderTop(true).
+			Padding(0, 1).
+			MarginTop(1)
+
+	// Filled part of a meter
+	meterStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color(colorGreen))
+)
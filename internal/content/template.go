@@ -0,0 +1,186 @@
+package content
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+)
+
+/**
+ * Per-session values made available to content templates.
+ */
+type Session struct {
+	User        string    // SSH user name as sent by the client (unsanitized)
+	Visitors    int       // Sessions served since the server started
+	ServerStart time.Time // When the server started
+	Now         time.Time // Render time; zero means time.Now()
+	Admin       bool      // Authenticated with an admin key; may preview drafts
+	Theme       string    // Glamour style for markdown pages: "dark" (default) or "light"
+	Hyperlinks  bool      // Terminal opens OSC 8 hyperlinks; otherwise links get footnotes
+}
+
+/**
+ * Returns the glamour style for the session's markdown pages.
+ */
+func (s Session) glamourStyle() string {
+	if s.Theme == "light" {
+		return "light"
+	}
+	return "dark"
+}
+
+/**
+ * Returns the render time for the session.
+ */
+func (s Session) now() time.Time {
+	if s.Now.IsZero() {
+		return time.Now()
+	}
+	return s.Now
+}
+
+/**
+ * Data passed to each markdown file's template.
+ */
+type pageData struct {
+	Visitor  string            // Sanitized visitor name
+	Visitors int               // Sessions served since the server started
+	Uptime   time.Duration     // Server uptime
+	Now      time.Time         // Current time
+	Modified time.Time         // File modification time
+	Meta     map[string]string // Front matter of the file
+}
+
+// Functions available to content templates. Nothing here touches the
+// filesystem, network or process state.
+var templateFuncs = template.FuncMap{
+	"date":     formatDate,
+	"duration": formatDuration,
+	"upper":    strings.ToUpper,
+	"lower":    strings.ToLower,
+	"default":  defaultValue,
+	"plural":   plural,
+}
+
+/**
+ * Runs a markdown body through text/template.
+ * @param name - Template name used in error messages (the file name)
+ * @param body - Markdown source
+ * @param data - Values exposed to the template
+ * @return Expanded markdown
+ * @return error if the template fails to parse or execute
+ */
+func executeTemplate(name, body string, data pageData) (string, error) {
+	tmpl, err := template.New(name).
+		Funcs(templateFuncs).
+		Option("missingkey=zero").
+		Parse(body)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+/**
+ * Builds the template data for one file.
+ * @param sess - Session values
+ * @param modified - File modification time
+ * @param meta - File front matter
+ * @return Template data
+ */
+func newPageData(sess Session, modified time.Time, meta map[string]string) pageData {
+	now := sess.now()
+
+	var uptime time.Duration
+	if !sess.ServerStart.IsZero() {
+		uptime = now.Sub(sess.ServerStart)
+	}
+
+	return pageData{
+		Visitor:  sanitizeVisitor(sess.User),
+		Visitors: sess.Visitors,
+		Uptime:   uptime,
+		Now:      now,
+		Modified: modified,
+		Meta:     meta,
+	}
+}
+
+/**
+ * Reduces a client-supplied user name to something safe to print.
+ * Keeps letters, digits, '-', '_' and '.', capped at 32 characters,
+ * so it cannot inject markdown, escape sequences or template syntax.
+ * @param name - Raw SSH user name
+ * @return Sanitized name, or "guest" if nothing is left
+ */
+func sanitizeVisitor(name string) string {
+	var b strings.Builder
+	count := 0
+
+	for _, r := range name {
+		if count >= 32 {
+			break
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' || r == '.' {
+			b.WriteRune(r)
+			count++
+		}
+	}
+
+	if b.Len() == 0 {
+		return "guest"
+	}
+	return b.String()
+}
+
+/**
+ * Formats a time with a Go layout, e.g. {{ .Modified | date "January 2006" }}.
+ */
+func formatDate(layout string, t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(layout)
+}
+
+/**
+ * Formats a duration coarsely, e.g. "3d 4h", "2h 15m" or "42s".
+ */
+func formatDuration(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd %dh", int(d.Hours())/24, int(d.Hours())%24)
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+	case d >= time.Minute:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%ds", int(d.Seconds()))
+}
+
+/**
+ * Returns value, or def if value is empty: {{ .Meta.role | default "Student" }}.
+ */
+func defaultValue(def, value string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
+
+/**
+ * Picks the singular or plural word for n: {{ plural .Visitors "visitor" "visitors" }}.
+ */
+func plural(n int, singular, pluralForm string) string {
+	if n == 1 {
+		return singular
+	}
+	return pluralForm
+}
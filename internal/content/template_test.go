@@ -0,0 +1,74 @@
+package content
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+/**
+ * Tests template expansion with session values and front matter.
+ */
+func TestLoadSessionTabs_Template(t *testing.T) {
+	tempDir := t.TempDir()
+
+	welcome := "---\nrole: tester\n---\n# Hi {{ .Visitor }}\n\n" +
+		"Visitor {{ .Visitors }} ({{ .Meta.role }}), up {{ duration .Uptime }}, {{ .Now | date \"2006\" }}"
+	if err := os.WriteFile(filepath.Join(tempDir, "welcome.md"), []byte(welcome), 0644); err != nil {
+		t.Fatalf("Failed to write welcome.md: %v", err)
+	}
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tabs, err := LoadSessionTabs(tempDir, Session{
+		User:        "ada\x1b[31m*",
+		Visitors:    7,
+		ServerStart: now.Add(-2 * time.Hour),
+		Now:         now,
+	})
+	if err != nil {
+		t.Fatalf("LoadSessionTabs failed: %v", err)
+	}
+
+	content := tabs[0].Content
+	for _, want := range []string{"ada31m", "Visitor 7", "tester", "up 2h 0m", "2026"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Rendered content missing %q", want)
+		}
+	}
+}
+
+/**
+ * Tests that template errors name the offending file.
+ */
+func TestLoadSessionTabs_TemplateError(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tempDir, "about.md"), []byte("{{ .Missing "), 0644); err != nil {
+		t.Fatalf("Failed to write about.md: %v", err)
+	}
+
+	_, err := LoadSessionTabs(tempDir, Session{})
+	if err == nil || !strings.Contains(err.Error(), "about.md") {
+		t.Errorf("Expected template error mentioning about.md, got %v", err)
+	}
+}
+
+/**
+ * Tests visitor name sanitization.
+ */
+func TestSanitizeVisitor(t *testing.T) {
+	tests := map[string]string{
+		"adam":                  "adam",
+		"":                      "guest",
+		"**{{x}}**":             "x",
+		"\x1b]52;c;bad\x07":     "52cbad",
+		strings.Repeat("a", 40): strings.Repeat("a", 32),
+	}
+
+	for input, want := range tests {
+		if got := sanitizeVisitor(input); got != want {
+			t.Errorf("sanitizeVisitor(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,514 @@
+package content
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adamdeleeuw/ssh-portfolio/internal/tui"
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Widest rendered line: prose after glamour's left margin, and the
+// terminal it fits in once the viewport has lost four columns to padding
+const (
+	contentWidth  = layoutWidth + 2
+	terminalWidth = contentWidth + 4
+)
+
+var (
+	// [text](target) and ![alt](target), with an optional "title"
+	linkPattern = regexp.MustCompile(`!?\[[^\]]*\]\(\s*<?([^)\s>]+)>?(?:\s+"[^"]*")?\s*\)`)
+
+	// Inline code spans, which may contain link-like text
+	codeSpanPattern = regexp.MustCompile("`[^`]*`")
+
+	// Line numbers in front matter, template and block errors
+	errorLinePattern = regexp.MustCompile(`(front matter line |template: [^:]+:|line )(\d+)`)
+)
+
+/**
+ * A problem found in the content directory.
+ */
+type Problem struct {
+	File    string // Slash-separated path relative to the content directory
+	Line    int    // 1-based line number, or 0 if it applies to the whole file
+	Message string
+}
+
+/**
+ * Formats the problem as "file:line: message".
+ */
+func (p Problem) String() string {
+	if p.Line == 0 {
+		return fmt.Sprintf("%s: %s", p.File, p.Message)
+	}
+	return fmt.Sprintf("%s:%d: %s", p.File, p.Line, p.Message)
+}
+
+/**
+ * Checks a content directory for problems that would otherwise only
+ * show up in production: missing tab files, programs and repositories,
+ * malformed front matter or data files, template and render errors,
+ * broken internal links and anchors, raw escape sequences and lines too
+ * wide for the terminal width pages are laid out for. Drafts and scheduled pages are checked too.
+ * Only the directory itself is read; built-in content is ignored.
+ * @param contentDir - Content directory
+ * @return Problems sorted by file and line (empty if the content is clean)
+ * @return error if the directory cannot be read
+ */
+func Validate(contentDir string) ([]Problem, error) {
+	if _, err := os.ReadDir(contentDir); err != nil {
+		return nil, err
+	}
+	fsys := os.DirFS(contentDir)
+
+	// Render exactly as sessions do, so widths match what visitors see
+	renderer, err := newSessionRenderer(Session{})
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []Problem
+	report := func(file string, line int, format string, args ...any) {
+		problems = append(problems, Problem{File: file, Line: line, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// The manifest, if any, decides which files tabs need
+	specs := defaultTabs
+	if data, err := fs.ReadFile(fsys, manifestFile); err == nil {
+		if parsed, err := parseManifest(data); err != nil {
+			report(manifestFile, jsonErrorLine(data, err), "%v", strings.TrimPrefix(err.Error(), manifestFile+": "))
+		} else {
+			specs = parsed
+		}
+	} else if !isNotExist(err) {
+		report(manifestFile, 0, "%v", err)
+	}
+
+	// Every tab needs its markdown or data file, its program or its repositories
+	for _, spec := range specs {
+		switch {
+		case spec.Blog:
+		case len(spec.Command) > 0:
+			if msg := checkCommand(fsys, spec.Command[0]); msg != "" {
+				report(manifestFile, 0, "%s tab: %s", spec.Name, msg)
+			}
+		case len(spec.Probes) > 0:
+			for _, p := range spec.Probes {
+				if len(p.Command) == 0 {
+					continue
+				}
+				if msg := checkCommand(fsys, p.Command[0]); msg != "" {
+					report(manifestFile, 0, "%s tab: probe %s: %s", spec.Name, p.Name, msg)
+				}
+			}
+		case len(spec.Repos) > 0:
+			for _, pattern := range spec.Repos {
+				switch {
+				case slices.ContainsFunc(resolveRepos([]string{pattern}, contentDir), isGitRepo):
+				case !filepath.IsLocal(pattern):
+					report(manifestFile, 0, "%s tab: no allowed git repository matches %s; repositories outside the content directory must be allowed with ACTIVITY_REPOS", spec.Name, pattern)
+				default:
+					report(manifestFile, 0, "%s tab: no git repository matches %s", spec.Name, pattern)
+				}
+			}
+		case exists(fsys, spec.File) || (spec.Data != "" && exists(fsys, spec.Data)):
+		default:
+			report(firstNonEmpty(spec.File, spec.Data), 0, "missing; the %s tab will show a placeholder", spec.Name)
+		}
+	}
+
+	// Structured data files
+	for _, dataFile := range []string{"resume.json", "projects.json"} {
+		data, err := fs.ReadFile(fsys, dataFile)
+		if err != nil {
+			if !isNotExist(err) {
+				report(dataFile, 0, "%v", err)
+			}
+			continue
+		}
+		if dataFile == "resume.json" {
+			_, err = parseResume(data, dataFile)
+		} else {
+			_, err = parseProjects(data, dataFile)
+		}
+		if err != nil {
+			report(dataFile, jsonErrorLine(data, err), "%v", err)
+		}
+	}
+
+	// Markdown pages and posts
+	pages, _ := fs.Glob(fsys, "*.md")
+	posts, _ := fs.Glob(fsys, "blog/*.md")
+	for _, file := range append(pages, posts...) {
+		problems = append(problems, validatePage(renderer, fsys, file)...)
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].File != problems[j].File {
+			return problems[i].File < problems[j].File
+		}
+		return problems[i].Line < problems[j].Line
+	})
+	return problems, nil
+}
+
+/**
+ * Runs one markdown file through the content pipeline, collecting
+ * problems instead of stopping at the first.
+ * @param renderer - Glamour renderer, as sessions use
+ * @param fsys - Content filesystem
+ * @param file - Slash-separated path of the file
+ * @return Problems found in the file
+ */
+func validatePage(renderer *glamour.TermRenderer, fsys fs.FS, file string) []Problem {
+	var problems []Problem
+	report := func(line int, format string, args ...any) {
+		problems = append(problems, Problem{File: file, Line: line, Message: fmt.Sprintf(format, args...)})
+	}
+
+	data, err := fs.ReadFile(fsys, file)
+	if err != nil {
+		report(0, "%v", err)
+		return problems
+	}
+	src := string(data)
+	srcLines := strings.Split(src, "\n")
+
+	for i, line := range strings.Split(src, "\n") {
+		if col := controlColumn(line); col > 0 {
+			report(i+1, "raw control character at column %d; escape sequences would reach the visitor's terminal", col)
+		}
+	}
+
+	meta, body, err := parseFrontMatter(src)
+	if err != nil {
+		report(errorLine(err, 0), "invalid front matter: %v", err)
+		return problems
+	}
+	// Lines taken up by front matter, to map body lines back to the file
+	offset := strings.Count(src, "\n") - strings.Count(body, "\n")
+
+	state, err := pageState(meta, time.Now())
+	if err != nil {
+		report(metaLine(src, err), "invalid front matter: %v", err)
+	}
+
+	if meta["template"] != "false" {
+		var modified time.Time
+		if info, err := fs.Stat(fsys, file); err == nil {
+			modified = info.ModTime()
+		}
+		expanded, err := executeTemplate(path.Base(file), body, newPageData(Session{}, modified, meta))
+		if err != nil {
+			report(errorLine(err, offset), "%v", err)
+		} else {
+			body = expanded
+		}
+	}
+
+	if strings.HasPrefix(file, "blog/") {
+		if _, err := newPost(path.Base(file), page{meta: meta, state: state, source: body}); err != nil {
+			report(1, "%v", err)
+		}
+	}
+
+	for _, link := range findLinks(body) {
+		if msg := checkLink(fsys, file, link.target); msg != "" {
+			report(link.line+offset, "%s", msg)
+		}
+	}
+
+	if _, err := pageContact(meta); err != nil {
+		report(metaLine(src, err), "invalid front matter: %v", err)
+	}
+	stripped, codes, err := extractQRCodes(body)
+	if err != nil {
+		report(errorLine(err, offset), "%v", err)
+		return problems
+	}
+	for _, code := range codes {
+		// Codes are drawn with a two-module border inside the page margin
+		if width := code.Code.Size + 4; width > contentWidth-4 {
+			report(sourceLine(srcLines, code.Text), "QR code is %d columns wide; a %d-column terminal shows it as text", width, terminalWidth)
+		}
+	}
+
+	stripped, blocks, err := extractBlocks(stripped, layoutWidth)
+	if err != nil {
+		report(errorLine(err, offset), "%v", err)
+		return problems
+	}
+	_, _, imageErrs := extractImages(stripped, fsys, file)
+	for _, err := range imageErrs {
+		report(sourceLine(srcLines, err.Error()), "%v", err)
+	}
+	// Footnotes print each URL in full, so check the wider of the two link styles
+	stripped, _ = markLinks(stripped, false)
+	rendered, err := renderer.Render(stripped)
+	if err != nil {
+		report(0, "render failed: %v", err)
+		return problems
+	}
+	rendered = spliceBlocks(rendered, blocks, 2)
+
+	// Lines still too wide after wrapping: code, tables, long words, blocks
+	seen := make(map[int]bool)
+	for _, line := range strings.Split(rendered, "\n") {
+		plain := strings.TrimRight(ansi.Strip(line), " ")
+		width := ansi.StringWidth(plain)
+		if width <= contentWidth {
+			continue
+		}
+		n := sourceLine(srcLines, plain)
+		if seen[n] {
+			continue
+		}
+		seen[n] = true
+		report(n, "rendered line is %d columns wide; a %d-column terminal shows %d", width, terminalWidth, contentWidth)
+	}
+
+	return problems
+}
+
+/**
+ * Checks that a command tab's program is in the content directory and
+ * executable. Programs looked up on PATH are not checked.
+ * @param fsys - Content filesystem
+ * @param program - First element of the command
+ * @return Problem description, or "" if the program looks runnable
+ */
+func checkCommand(fsys fs.FS, program string) string {
+	if !strings.Contains(program, "/") || path.IsAbs(program) {
+		return ""
+	}
+	info, err := fs.Stat(fsys, path.Clean(program))
+	if err != nil {
+		return fmt.Sprintf("program %s not found", program)
+	}
+	if info.IsDir() || info.Mode().Perm()&0111 == 0 {
+		return fmt.Sprintf("program %s is not executable", program)
+	}
+	return ""
+}
+
+/**
+ * A link target and the body line it appears on.
+ */
+type linkRef struct {
+	target string
+	line   int // 1-based, relative to the body
+}
+
+/**
+ * Finds markdown links and images outside code blocks and code spans.
+ */
+func findLinks(body string) []linkRef {
+	var links []linkRef
+	fence := ""
+
+	for i, line := range strings.Split(body, "\n") {
+		if fence != "" {
+			if isClosingFence(line, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if marker, _, ok := parseFence(line); ok {
+			fence = marker
+			continue
+		}
+
+		line = codeSpanPattern.ReplaceAllString(line, "")
+		for _, m := range linkPattern.FindAllStringSubmatch(line, -1) {
+			links = append(links, linkRef{target: m[1], line: i + 1})
+		}
+	}
+	return links
+}
+
+/**
+ * Checks that an internal link points at an existing file and heading.
+ * External links (anything with a URL scheme) are not checked.
+ * @param fsys - Content filesystem
+ * @param from - File containing the link
+ * @param target - Link target as written
+ * @return Problem description, or "" if the link is fine
+ */
+func checkLink(fsys fs.FS, from, target string) string {
+	if u, err := url.Parse(target); err == nil && u.Scheme != "" {
+		return ""
+	}
+
+	file, anchor, _ := strings.Cut(target, "#")
+	resolved := from
+	if file != "" {
+		resolved = resolvePath(from, file)
+		if !fs.ValidPath(resolved) || !exists(fsys, resolved) {
+			return fmt.Sprintf("broken link %q: %s not found", target, resolved)
+		}
+	}
+
+	if anchor == "" {
+		return ""
+	}
+	if path.Ext(resolved) != ".md" {
+		return fmt.Sprintf("broken link %q: anchors only work in markdown files", target)
+	}
+	data, err := fs.ReadFile(fsys, resolved)
+	if err != nil {
+		return fmt.Sprintf("broken link %q: %v", target, err)
+	}
+	for _, slug := range headingSlugs(string(data)) {
+		if slug == anchor {
+			return ""
+		}
+	}
+	return fmt.Sprintf("broken link %q: no heading #%s in %s", target, anchor, resolved)
+}
+
+/**
+ * Returns GitHub-style anchors for the ATX headings of a document,
+ * with -1, -2, ... appended to repeated headings.
+ */
+func headingSlugs(md string) []string {
+	var slugs []string
+	counts := make(map[string]int)
+	fence := ""
+
+	for _, line := range strings.Split(md, "\n") {
+		if fence != "" {
+			if isClosingFence(line, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if marker, _, ok := parseFence(line); ok {
+			fence = marker
+			continue
+		}
+		if !strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		slug := tui.Anchor(strings.TrimLeft(line, "#"))
+		if n := counts[slug]; n > 0 {
+			slugs = append(slugs, fmt.Sprintf("%s-%d", slug, n))
+		} else {
+			slugs = append(slugs, slug)
+		}
+		counts[slug]++
+	}
+	return slugs
+}
+
+/**
+ * Finds the first control character other than tab and carriage
+ * return, including the single-character CSI (U+009B).
+ * @return 1-based column, or 0 if there is none
+ */
+func controlColumn(line string) int {
+	col := 0
+	for _, r := range line {
+		col++
+		if r == '\t' || r == '\r' {
+			continue
+		}
+		if r < 0x20 || r == 0x7f || r == 0x9b {
+			return col
+		}
+	}
+	return 0
+}
+
+/**
+ * Finds the source line a rendered line most likely came from, by
+ * searching for its first word of four or more characters.
+ * @return 1-based line number, or 0 if no line matches
+ */
+func sourceLine(lines []string, rendered string) int {
+	for _, word := range strings.Fields(rendered) {
+		if len(word) < 4 {
+			continue
+		}
+		for i, line := range lines {
+			if strings.Contains(line, word) {
+				return i + 1
+			}
+		}
+		return 0
+	}
+	return 0
+}
+
+/**
+ * Extracts the line number from a front matter, template or block error.
+ * @param err - Error from the content pipeline
+ * @param offset - Added to body-relative line numbers
+ * @return 1-based file line, or 0 if the error carries none
+ */
+func errorLine(err error, offset int) int {
+	m := errorLinePattern.FindStringSubmatch(err.Error())
+	if m == nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(m[2])
+	if m[1] == "front matter line " {
+		return n
+	}
+	return n + offset
+}
+
+/**
+ * Finds the front matter line holding the key named in a schedule error.
+ */
+func metaLine(src string, err error) int {
+	key, _, _ := strings.Cut(err.Error(), ":")
+	for i, line := range strings.Split(src, "\n") {
+		if k, _, ok := strings.Cut(line, ":"); ok && strings.EqualFold(strings.TrimSpace(k), key) {
+			return i + 1
+		}
+	}
+	return 1
+}
+
+/**
+ * Converts the byte offset of a JSON decoding error to a line number.
+ * @return 1-based line, or 0 if the error has no offset
+ */
+func jsonErrorLine(data []byte, err error) int {
+	var offset int64
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		offset = typeErr.Offset
+	default:
+		return 0
+	}
+	offset = min(offset, int64(len(data)))
+	return strings.Count(string(data[:offset]), "\n") + 1
+}
+
+/**
+ * Reports whether a file exists in the filesystem.
+ */
+func exists(fsys fs.FS, name string) bool {
+	_, err := fs.Stat(fsys, name)
+	return err == nil
+}
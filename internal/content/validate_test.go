@@ -0,0 +1,157 @@
+package content
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+/**
+ * Writes files into a temporary content directory.
+ */
+func writeContent(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, data := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+/**
+ * Tests that clean content produces no problems. Pages are rendered as
+ * sessions render them, so prose and code up to the page width fit.
+ */
+func TestValidate_Clean(t *testing.T) {
+	dir := writeContent(t, map[string]string{
+		"welcome.md":  "# Welcome\n\nSee [projects](projects.md#my-projects) and [me](https://example.com).\n",
+		"about.md":    "# About\n\nHello {{ .Visitor }}. " + strings.Repeat("Long paragraphs wrap to fit. ", 20) + "\n",
+		"projects.md": "# My Projects\n\n```\n" + strings.Repeat("x", 90) + "\n```\n",
+		"future.md":   "# Future\n\n[Top](#future)\n",
+	})
+
+	problems, err := Validate(dir)
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	for _, p := range problems {
+		t.Errorf("Unexpected problem: %s", p)
+	}
+}
+
+/**
+ * Tests that each kind of problem is reported with its location.
+ */
+func TestValidate_Problems(t *testing.T) {
+	dir := writeContent(t, map[string]string{
+		"welcome.md":    "# Welcome\n\n[Gone](missing.md)\n[Bad anchor](about.md#nope)\n",
+		"about.md":      "---\ntitle: About\ndraft: maybe\n---\n# About\n\n{{ .Nope }\n",
+		"future.md":     "# Future\n\nColour \x1b[31mred\n\n```\n" + strings.Repeat("x", 100) + "\n```\n",
+		"projects.json": "[\n  {\"name\": \"\"}\n]\n",
+		"blog/post.md":  "# Undated\n",
+	})
+
+	problems, err := Validate(dir)
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	var got []string
+	for _, p := range problems {
+		got = append(got, p.String())
+	}
+	all := strings.Join(got, "\n")
+
+	want := []string{
+		"about.md:3: invalid front matter: draft",
+		"about.md:7: template: about.md:3: unexpected",
+		"blog/post.md:1: missing date",
+		"future.md:3: raw control character at column 8",
+		"future.md:6: rendered line is 102 columns wide",
+		"projects.json: project 1 in projects.json has no name",
+		"welcome.md:3: broken link \"missing.md\"",
+		"welcome.md:4: broken link \"about.md#nope\": no heading #nope in about.md",
+	}
+	for _, w := range want {
+		if !strings.Contains(all, w) {
+			t.Errorf("Expected problem starting %q, got:\n%s", w, all)
+		}
+	}
+}
+
+/**
+ * Tests a missing tab file and an unreadable directory.
+ */
+func TestValidate_Missing(t *testing.T) {
+	dir := writeContent(t, map[string]string{
+		"welcome.md":    "# Welcome\n",
+		"about.md":      "# About\n",
+		"projects.json": "[]",
+	})
+
+	problems, err := Validate(dir)
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if len(problems) != 1 || problems[0].String() != "future.md: missing; the Future tab will show a placeholder" {
+		t.Errorf("Expected only future.md to be missing, got %v", problems)
+	}
+
+	if _, err := Validate(filepath.Join(dir, "nope")); err == nil {
+		t.Error("Expected error for missing directory")
+	}
+}
+
+/**
+ * Tests tabs come from the manifest when there is one.
+ */
+func TestValidate_Manifest(t *testing.T) {
+	dir := writeContent(t, map[string]string{
+		"tabs.json":             `{"tabs": [{"name": "Home", "file": "home.md"}, {"name": "Status", "command": ["./status.sh"]}, {"name": "Uptime", "command": ["uptime"]}, {"name": "Activity", "repos": ["repos/*", "/srv/git/*"]}]}`,
+		"repos/notes/README.md": "# Not a repository",
+	})
+
+	problems, err := Validate(dir)
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	var got []string
+	for _, p := range problems {
+		got = append(got, p.String())
+	}
+	want := "home.md: missing; the Home tab will show a placeholder\ntabs.json: Status tab: program ./status.sh not found\ntabs.json: Activity tab: no git repository matches repos/*\ntabs.json: Activity tab: no allowed git repository matches /srv/git/*; repositories outside the content directory must be allowed with ACTIVITY_REPOS"
+	if strings.Join(got, "\n") != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, strings.Join(got, "\n"))
+	}
+
+	dir = writeContent(t, map[string]string{"tabs.json": "{\n  \"tabs\": [\n    {\"name\": 1}\n  ]\n}\n"})
+	problems, err = Validate(dir)
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	found := false
+	for _, p := range problems {
+		found = found || (p.File == "tabs.json" && p.Line == 3)
+	}
+	if !found {
+		t.Errorf("Expected a problem at tabs.json:3, got %v", problems)
+	}
+}
+
+/**
+ * Tests GitHub-style heading anchors.
+ */
+func TestHeadingSlugs(t *testing.T) {
+	got := headingSlugs("# Hello, World!\n```\n# not a heading\n```\n## Hello World\n### C++ & Go\n")
+	want := []string{"hello-world", "hello-world-1", "c--go"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
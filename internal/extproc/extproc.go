@@ -0,0 +1,402 @@
+// Package extproc runs external programs on behalf of a session, with
+// time and output limits so a misbehaving program cannot hang or flood
+// the server. Programs run in their own process group and are killed
+// together with anything they spawn.
+package extproc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Defaults for zero Command fields
+const (
+	DefaultTimeout   = 5 * time.Second
+	DefaultMaxOutput = 64 << 10
+)
+
+// Bytes of stderr kept for error messages
+const maxStderr = 4 << 10
+
+// Messages queued for a program that is not reading its input
+const sendQueue = 64
+
+// ErrOutputLimit is returned when a program writes more than MaxOutput
+var ErrOutputLimit = errors.New("output limit exceeded")
+
+// ErrBusy is returned by Start when MaxProcesses programs are running
+var ErrBusy = errors.New("too many programs running")
+
+// Long-running programs across all sessions, and how many may run at once
+var processes = struct {
+	sync.Mutex
+	running int
+	max     int
+}{max: 20}
+
+/**
+ * Limits how many programs started with Start may run at once across
+ * the server. Each one holds a process for as long as its visitor
+ * keeps the tab open, so the limit bounds what visitors can make the
+ * server run.
+ * @param n - Maximum number of processes (at least 1)
+ */
+func SetMaxProcesses(n int) {
+	processes.Lock()
+	processes.max = max(n, 1)
+	processes.Unlock()
+}
+
+/**
+ * Claims a slot for a long-running program.
+ * @return false if every slot is taken
+ */
+func acquireProcess() bool {
+	processes.Lock()
+	defer processes.Unlock()
+	if processes.running >= processes.max {
+		return false
+	}
+	processes.running++
+	return true
+}
+
+/**
+ * Frees the slot of a program that has exited.
+ */
+func releaseProcess() {
+	processes.Lock()
+	processes.running--
+	processes.Unlock()
+}
+
+/**
+ * An external program and its limits.
+ */
+type Command struct {
+	Args      []string      // Program and arguments; a relative program path is resolved against Dir
+	Dir       string        // Working directory
+	Env       []string      // Extra "KEY=value" entries added to a minimal environment
+	Timeout   time.Duration // Run: limit for the whole run. Start: limit for the first message
+	MaxOutput int           // Run: limit on stdout. Start: limit on each message line
+}
+
+/**
+ * Fills in defaults for zero limits.
+ */
+func (c Command) withDefaults() Command {
+	if c.Timeout <= 0 {
+		c.Timeout = DefaultTimeout
+	}
+	if c.MaxOutput <= 0 {
+		c.MaxOutput = DefaultMaxOutput
+	}
+	return c
+}
+
+/**
+ * Builds the exec.Cmd. The environment is deliberately minimal so the
+ * server's own variables (keys, paths) never reach the program.
+ */
+func (c Command) command(ctx context.Context) (*exec.Cmd, error) {
+	if len(c.Args) == 0 {
+		return nil, errors.New("no command")
+	}
+
+	name := c.Args[0]
+	if strings.ContainsRune(name, filepath.Separator) && !filepath.IsAbs(name) {
+		name = filepath.Join(c.Dir, name)
+	}
+
+	cmd := exec.CommandContext(ctx, name, c.Args[1:]...)
+	cmd.Dir = c.Dir
+	cmd.Env = append([]string{
+		"PATH=" + os.Getenv("PATH"),
+		"LANG=C.UTF-8",
+		"TERM=xterm-256color",
+	}, c.Env...)
+	cmd.WaitDelay = time.Second
+	isolate(cmd)
+	return cmd, nil
+}
+
+/**
+ * Runs a program to completion and returns its standard output.
+ * @param ctx - Cancels the run
+ * @param c - Program and limits
+ * @return Standard output
+ * @return error if the program cannot start, fails, times out or
+ *         writes more than MaxOutput bytes
+ */
+func Run(ctx context.Context, c Command) ([]byte, error) {
+	c = c.withDefaults()
+	ctx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	cmd, err := c.command(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stdout := &limitBuffer{max: c.MaxOutput, overflow: cancel}
+	stderr := &limitBuffer{max: maxStderr}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	err = cmd.Run()
+	switch {
+	case stdout.exceeded:
+		return nil, fmt.Errorf("%w (%d bytes)", ErrOutputLimit, c.MaxOutput)
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return nil, fmt.Errorf("timed out after %s", c.Timeout)
+	case err != nil:
+		return nil, withStderr(err, stderr)
+	}
+	return stdout.Bytes(), nil
+}
+
+/**
+ * A long-running program exchanging newline-delimited messages over
+ * stdin and stdout.
+ */
+type Process struct {
+	cmd    *exec.Cmd
+	ctx    context.Context // Cancelled to kill the program
+	cancel context.CancelFunc
+	stderr *limitBuffer
+
+	out  chan []byte   // Lines from the program; closed when it exits
+	in   chan []byte   // Lines for the program
+	done chan struct{} // Closed once the program has exited
+
+	mu  sync.Mutex
+	err error // Why the program stopped
+}
+
+/**
+ * Starts a program. It is killed if it sends nothing within Timeout,
+ * if a line exceeds MaxOutput bytes, or when Close is called.
+ * @param c - Program and limits
+ * @return Running process
+ * @return error if the program cannot be started, or ErrBusy if too
+ *         many are running already
+ */
+func Start(c Command) (*Process, error) {
+	c = c.withDefaults()
+	if !acquireProcess() {
+		return nil, ErrBusy
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	abort := func(err error) (*Process, error) {
+		cancel()
+		releaseProcess()
+		return nil, err
+	}
+
+	cmd, err := c.command(ctx)
+	if err != nil {
+		return abort(err)
+	}
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return abort(err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return abort(err)
+	}
+	p := &Process{
+		cmd:    cmd,
+		ctx:    ctx,
+		cancel: cancel,
+		stderr: &limitBuffer{max: maxStderr},
+		out:    make(chan []byte),
+		in:     make(chan []byte, sendQueue),
+		done:   make(chan struct{}),
+	}
+	cmd.Stderr = p.stderr
+
+	if err := cmd.Start(); err != nil {
+		return abort(err)
+	}
+
+	first := time.AfterFunc(c.Timeout, func() {
+		p.fail(fmt.Errorf("no output within %s", c.Timeout))
+	})
+	go p.write(stdin)
+	go p.read(stdout, c.MaxOutput, first)
+	return p, nil
+}
+
+/**
+ * Queues a line for the program without blocking.
+ * @param line - Message, without the trailing newline
+ * @return error if the program has exited or is not reading its input
+ */
+func (p *Process) Send(line []byte) error {
+	select {
+	case <-p.done:
+		return p.Err()
+	default:
+	}
+
+	select {
+	case p.in <- append(line, '\n'):
+		return nil
+	default:
+		return errors.New("program is not reading its input")
+	}
+}
+
+/**
+ * Waits for the next line from the program.
+ * @return Line without its newline
+ * @return error (see Err) once the program has exited
+ */
+func (p *Process) Next() ([]byte, error) {
+	line, ok := <-p.out
+	if !ok {
+		return nil, p.Err()
+	}
+	return line, nil
+}
+
+/**
+ * Kills the program. Safe to call more than once.
+ */
+func (p *Process) Close() {
+	p.fail(errors.New("closed"))
+}
+
+/**
+ * Reports why the program stopped, or nil while it is running.
+ */
+func (p *Process) Err() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.err
+}
+
+/**
+ * Records the first reason the program stopped and kills it.
+ */
+func (p *Process) fail(err error) {
+	p.mu.Lock()
+	if p.err == nil {
+		p.err = err
+	}
+	p.mu.Unlock()
+	p.cancel()
+}
+
+/**
+ * Feeds queued lines to the program's stdin until it exits.
+ */
+func (p *Process) write(stdin io.WriteCloser) {
+	defer stdin.Close()
+	for {
+		select {
+		case line := <-p.in:
+			if _, err := stdin.Write(line); err != nil {
+				return
+			}
+		case <-p.done:
+			return
+		}
+	}
+}
+
+/**
+ * Reads lines from the program's stdout until it exits, then reaps it.
+ */
+func (p *Process) read(stdout io.Reader, maxLine int, first *time.Timer) {
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, min(maxLine, 64<<10)), maxLine)
+
+	for scanner.Scan() {
+		first.Stop()
+		line := append([]byte(nil), scanner.Bytes()...)
+		select {
+		case p.out <- line:
+		case <-p.ctx.Done():
+		}
+	}
+	if errors.Is(scanner.Err(), bufio.ErrTooLong) {
+		p.fail(fmt.Errorf("%w (%d bytes per line)", ErrOutputLimit, maxLine))
+	}
+
+	// Drain so the program is not blocked writing while it is killed
+	go io.Copy(io.Discard, stdout)
+	err := p.cmd.Wait()
+	first.Stop()
+
+	if err == nil {
+		err = errors.New("exited")
+	} else {
+		err = withStderr(err, p.stderr)
+	}
+	p.fail(err)
+	releaseProcess()
+	close(p.done)
+	close(p.out)
+}
+
+/**
+ * Adds the last line of stderr to an error, where programs usually
+ * explain what went wrong.
+ */
+func withStderr(err error, stderr *limitBuffer) error {
+	lines := strings.Split(strings.TrimSpace(stderr.String()), "\n")
+	if last := strings.TrimSpace(lines[len(lines)-1]); last != "" {
+		return fmt.Errorf("%w: %s", err, last)
+	}
+	return err
+}
+
+/**
+ * Buffer that keeps at most max bytes. Further writes are discarded
+ * (not refused, so the program is not blocked) and overflow is called.
+ */
+type limitBuffer struct {
+	mu       sync.Mutex
+	buf      bytes.Buffer
+	max      int
+	exceeded bool
+	overflow func()
+}
+
+func (b *limitBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if room := b.max - b.buf.Len(); len(p) > room {
+		b.buf.Write(p[:max(room, 0)])
+		if !b.exceeded && b.overflow != nil {
+			b.overflow()
+		}
+		b.exceeded = true
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+func (b *limitBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Bytes()
+}
+
+func (b *limitBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
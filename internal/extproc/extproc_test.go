@@ -0,0 +1,147 @@
+package extproc
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+/**
+ * Skips tests that rely on a POSIX shell.
+ */
+func requireShell(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("requires /bin/sh")
+	}
+}
+
+/**
+ * Tests output is returned and the environment is minimal.
+ */
+func TestRun(t *testing.T) {
+	requireShell(t)
+	t.Setenv("SECRET_FOR_TEST", "leak")
+
+	out, err := Run(context.Background(), Command{
+		Args: []string{"sh", "-c", "echo hello; echo \"[$SECRET_FOR_TEST]\"; echo \"$EXTRA\""},
+		Env:  []string{"EXTRA=passed"},
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if string(out) != "hello\n[]\npassed\n" {
+		t.Errorf("Unexpected output %q", out)
+	}
+}
+
+/**
+ * Tests failures, timeouts and oversized output are reported.
+ */
+func TestRun_Limits(t *testing.T) {
+	requireShell(t)
+
+	_, err := Run(context.Background(), Command{Args: []string{"sh", "-c", "echo broken >&2; exit 3"}})
+	if err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Errorf("Expected failure with stderr, got %v", err)
+	}
+
+	start := time.Now()
+	_, err = Run(context.Background(), Command{Args: []string{"sh", "-c", "sleep 10"}, Timeout: 100 * time.Millisecond})
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Expected timeout, got %v", err)
+	}
+	if time.Since(start) > 3*time.Second {
+		t.Errorf("Expected the program to be killed promptly, took %s", time.Since(start))
+	}
+
+	_, err = Run(context.Background(), Command{Args: []string{"sh", "-c", "yes"}, MaxOutput: 1000})
+	if !errors.Is(err, ErrOutputLimit) {
+		t.Errorf("Expected ErrOutputLimit, got %v", err)
+	}
+}
+
+/**
+ * Tests line exchange with a long-running program.
+ */
+func TestStart(t *testing.T) {
+	requireShell(t)
+
+	p, err := Start(Command{Args: []string{"sh", "-c", "echo ready; while read -r line; do echo \"got $line\"; done"}})
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer p.Close()
+
+	if line, err := p.Next(); err != nil || string(line) != "ready" {
+		t.Fatalf("Expected ready, got %q, %v", line, err)
+	}
+	if err := p.Send([]byte("ping")); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if line, err := p.Next(); err != nil || string(line) != "got ping" {
+		t.Fatalf("Expected echo, got %q, %v", line, err)
+	}
+
+	p.Close()
+	if _, err := p.Next(); err == nil {
+		t.Error("Expected Next to fail after Close")
+	}
+}
+
+/**
+ * Tests programs that stay silent or send huge lines are stopped.
+ */
+func TestStart_Limits(t *testing.T) {
+	requireShell(t)
+
+	p, err := Start(Command{Args: []string{"sh", "-c", "sleep 10"}, Timeout: 100 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Next(); err == nil || !strings.Contains(err.Error(), "no output") {
+		t.Errorf("Expected first-message timeout, got %v", err)
+	}
+
+	p, err = Start(Command{Args: []string{"sh", "-c", "yes | tr -d '\\n'"}, MaxOutput: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Next(); !errors.Is(err, ErrOutputLimit) {
+		t.Errorf("Expected ErrOutputLimit, got %v", err)
+	}
+}
+
+/**
+ * Tests no more than MaxProcesses programs run at once, and a slot is
+ * freed when a program exits.
+ */
+func TestStart_MaxProcesses(t *testing.T) {
+	requireShell(t)
+	SetMaxProcesses(1)
+	t.Cleanup(func() { SetMaxProcesses(20) })
+
+	cmd := Command{Args: []string{"sh", "-c", "echo ready; cat"}}
+	p, err := Start(cmd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Start(cmd); !errors.Is(err, ErrBusy) {
+		t.Errorf("Expected ErrBusy, got %v", err)
+	}
+
+	p.Close()
+	for {
+		if _, err := p.Next(); err != nil {
+			break
+		}
+	}
+	p, err = Start(cmd)
+	if err != nil {
+		t.Fatalf("Expected the slot to be freed, got %v", err)
+	}
+	p.Close()
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package extproc
+
+import "os/exec"
+
+/**
+ * Process groups are Unix-only; elsewhere only the program itself is killed.
+ */
+func isolate(cmd *exec.Cmd) {}
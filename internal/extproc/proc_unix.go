@@ -0,0 +1,19 @@
+//go:build unix
+
+package extproc
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+/**
+ * Runs the program in its own process group so that cancelling it
+ * also kills anything it started.
+ */
+func isolate(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
@@ -0,0 +1,437 @@
+package history
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adamdeleeuw/ssh-portfolio/internal/tui"
+)
+
+// Visitors not seen for this long are forgotten
+const retention = 365 * 24 * time.Hour
+
+// Visitors kept at most; the least recently seen are forgotten first
+const maxVisitors = 10000
+
+// How long after a visit the file is written, so a burst of sessions
+// costs one write
+const saveDelay = 10 * time.Second
+
+/**
+ * Hash of one markdown section, identified by its heading.
+ */
+type Section struct {
+	Heading string `json:"heading"` // "" for text before the first heading
+	Hash    string `json:"hash"`
+
+	text string // Section body, when known
+}
+
+/**
+ * Fingerprint of a tab's content at one point in time.
+ */
+type Page struct {
+	Hash     string    `json:"hash"`
+	Sections []Section `json:"sections"`
+}
+
+/**
+ * What a visitor saw in their most recent session.
+ */
+type visit struct {
+	LastSeen time.Time       `json:"last_seen"`
+	Pages    map[string]Page `json:"pages"` // Keyed by tab name
+}
+
+/**
+ * Contents of the history file.
+ */
+type storeFile struct {
+	Visitors map[string]visit  `json:"visitors"`
+	Sections map[string]string `json:"sections"` // Section text by hash, shared by all visitors
+}
+
+/**
+ * Per-visitor content history, persisted as a JSON file.
+ * Visitors are identified by their public key fingerprint. The text
+ * of each section version they saw is kept once for everyone, so
+ * changes can be shown line by line. Writes are batched: the file is
+ * saved saveDelay after a visit, and on Close.
+ * Safe for concurrent use.
+ */
+type Store struct {
+	path  string
+	delay time.Duration
+
+	mu       sync.Mutex
+	visitors map[string]visit
+	texts    map[string]string // Section text by hash
+	dirty    bool              // Changed since the last save
+	timer    *time.Timer       // Pending save
+	saveErr  error             // Why the last save failed
+}
+
+/**
+ * Opens the history file, creating an empty store if it does not exist.
+ * @param path - JSON file to read and write
+ * @return Loaded store
+ * @return error if the file exists but cannot be read or parsed
+ */
+func Open(path string) (*Store, error) {
+	s := &Store{path: path, delay: saveDelay, visitors: make(map[string]visit), texts: make(map[string]string)}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var f storeFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if f.Visitors == nil {
+		// Files from before section texts were kept hold just the visitors
+		if err := json.Unmarshal(data, &f.Visitors); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	}
+	if f.Visitors != nil {
+		s.visitors = f.Visitors
+	}
+	if f.Sections != nil {
+		s.texts = f.Sections
+	}
+	return s, nil
+}
+
+/**
+ * Records a session and reports what changed since the visitor's last one.
+ * @param fingerprint - Public key fingerprint identifying the visitor
+ * @param tabs - Tabs shown in this session
+ * @param now - Session start
+ * @return Changed tabs in display order (nil on a first visit)
+ * @return Start of the previous session (zero on a first visit)
+ * @return error if the last save failed; changes are still returned
+ */
+func (s *Store) Visit(fingerprint string, tabs []tui.Tab, now time.Time) ([]tui.TabChange, time.Time, error) {
+	current := Snapshot(tabs)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prev, returning := s.visitors[fingerprint]
+	s.visitors[fingerprint] = visit{LastSeen: now, Pages: current}
+	for _, page := range current {
+		for _, sec := range page.Sections {
+			s.texts[sec.Hash] = sec.text
+		}
+	}
+
+	var changes []tui.TabChange
+	if returning {
+		changes = Diff(s.withTexts(prev.Pages), current, tabs)
+	}
+
+	s.prune(now)
+	s.dirty = true
+	if s.timer == nil {
+		s.timer = time.AfterFunc(s.delay, func() { s.Flush() })
+	}
+	return changes, prev.LastSeen, s.saveErr
+}
+
+/**
+ * Fills in the text of stored sections.
+ * Callers must hold s.mu.
+ */
+func (s *Store) withTexts(pages map[string]Page) map[string]Page {
+	out := make(map[string]Page, len(pages))
+	for name, page := range pages {
+		secs := make([]Section, len(page.Sections))
+		for i, sec := range page.Sections {
+			sec.text = s.texts[sec.Hash]
+			secs[i] = sec
+		}
+		page.Sections = secs
+		out[name] = page
+	}
+	return out
+}
+
+/**
+ * Forgets visitors past the retention period, then the least recently
+ * seen ones beyond maxVisitors.
+ * Callers must hold s.mu.
+ */
+func (s *Store) prune(now time.Time) {
+	for fp, v := range s.visitors {
+		if now.Sub(v.LastSeen) > retention {
+			delete(s.visitors, fp)
+		}
+	}
+	if len(s.visitors) <= maxVisitors {
+		return
+	}
+
+	fps := make([]string, 0, len(s.visitors))
+	for fp := range s.visitors {
+		fps = append(fps, fp)
+	}
+	sort.Slice(fps, func(i, j int) bool {
+		return s.visitors[fps[i]].LastSeen.Before(s.visitors[fps[j]].LastSeen)
+	})
+	for _, fp := range fps[:len(fps)-maxVisitors] {
+		delete(s.visitors, fp)
+	}
+}
+
+/**
+ * Saves pending changes now.
+ * @return error if the file cannot be written
+ */
+func (s *Store) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	if !s.dirty {
+		return s.saveErr
+	}
+	s.saveErr = s.save()
+	s.dirty = s.saveErr != nil
+	return s.saveErr
+}
+
+/**
+ * Writes the store to disk, dropping section texts no visitor refers
+ * to any more. Writes to a temporary file first so a crash never
+ * leaves half a file.
+ * Callers must hold s.mu.
+ */
+func (s *Store) save() error {
+	used := make(map[string]bool)
+	for _, v := range s.visitors {
+		for _, page := range v.Pages {
+			for _, sec := range page.Sections {
+				used[sec.Hash] = true
+			}
+		}
+	}
+	for h := range s.texts {
+		if !used[h] {
+			delete(s.texts, h)
+		}
+	}
+
+	data, err := json.Marshal(storeFile{Visitors: s.visitors, Sections: s.texts})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+/**
+ * Fingerprints every tab's source.
+ * @param tabs - Loaded tabs
+ * @return Page hashes keyed by tab name
+ */
+func Snapshot(tabs []tui.Tab) map[string]Page {
+	pages := make(map[string]Page, len(tabs))
+	for _, tab := range tabs {
+		pages[tab.Name] = Page{Hash: hash(tab.Source), Sections: sections(tab.Source)}
+	}
+	return pages
+}
+
+/**
+ * Compares two snapshots section by section.
+ * Tabs that disappeared are not reported; there is nothing to badge.
+ * @param old - Snapshot from the previous session
+ * @param current - Snapshot from this session
+ * @param tabs - Current tabs, for display order
+ * @return One entry per new or changed tab
+ */
+func Diff(old, current map[string]Page, tabs []tui.Tab) []tui.TabChange {
+	var changes []tui.TabChange
+
+	for _, tab := range tabs {
+		cur := current[tab.Name]
+		prev, existed := old[tab.Name]
+		if !existed {
+			changes = append(changes, tui.TabChange{Tab: tab.Name, New: true})
+			continue
+		}
+		if prev.Hash == cur.Hash {
+			continue
+		}
+
+		change := tui.TabChange{Tab: tab.Name, Details: make(map[string][]tui.DiffLine)}
+		before := sectionsByHeading(prev.Sections)
+		after := sectionsByHeading(cur.Sections)
+
+		for _, sec := range cur.Sections {
+			old, ok := before[sec.Heading]
+			switch {
+			case !ok:
+				change.Added = append(change.Added, sec.Heading)
+				change.Details[sec.Heading] = diffLines("", sec.text)
+			case old.Hash != sec.Hash:
+				change.Updated = append(change.Updated, sec.Heading)
+				// The old text is gone if the file was written by an older version
+				if old.text != "" {
+					change.Details[sec.Heading] = diffLines(old.text, sec.text)
+				}
+			}
+		}
+		for _, sec := range prev.Sections {
+			if _, ok := after[sec.Heading]; !ok {
+				change.Removed = append(change.Removed, sec.Heading)
+				change.Details[sec.Heading] = diffLines(sec.text, "")
+			}
+		}
+
+		// Whitespace-only edits change the page hash but no section
+		if len(change.Added)+len(change.Updated)+len(change.Removed) > 0 {
+			changes = append(changes, change)
+		}
+	}
+
+	return changes
+}
+
+/**
+ * Splits markdown at ATX headings and hashes each section.
+ * Text before the first heading forms a section with an empty heading
+ * (omitted if blank). Headings inside code fences are ignored.
+ * @param md - Markdown source
+ * @return Sections in document order
+ */
+func sections(md string) []Section {
+	var out []Section
+	heading := ""
+	var body strings.Builder
+	inFence := false
+
+	flush := func() {
+		if heading != "" || strings.TrimSpace(body.String()) != "" {
+			out = append(out, Section{Heading: heading, Hash: hash(body.String()), text: strings.TrimSpace(body.String())})
+		}
+		body.Reset()
+	}
+
+	for _, line := range strings.Split(md, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+		}
+		if !inFence && strings.HasPrefix(line, "#") {
+			flush()
+			heading = strings.TrimSpace(strings.TrimLeft(line, "#"))
+			continue
+		}
+		body.WriteString(line + "\n")
+	}
+	flush()
+
+	return out
+}
+
+/**
+ * Maps headings to sections. Repeated headings keep the first section.
+ */
+func sectionsByHeading(secs []Section) map[string]Section {
+	m := make(map[string]Section, len(secs))
+	for _, sec := range secs {
+		if _, ok := m[sec.Heading]; !ok {
+			m[sec.Heading] = sec
+		}
+	}
+	return m
+}
+
+/**
+ * Compares two texts line by line.
+ * @param old - Text before the change ("" for a new section)
+ * @param cur - Text after the change ("" for a removed section)
+ * @return Lines removed from old and added in cur, in document order;
+ *         unchanged and blank lines are left out
+ */
+func diffLines(old, cur string) []tui.DiffLine {
+	a, b := splitLines(old), splitLines(cur)
+
+	// lcs[i][j] is the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out []tui.DiffLine
+	add := func(op byte, line string) {
+		if strings.TrimSpace(line) != "" {
+			out = append(out, tui.DiffLine{Op: op, Text: line})
+		}
+	}
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			i, j = i+1, j+1
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			add('-', a[i])
+			i++
+		default:
+			add('+', b[j])
+			j++
+		}
+	}
+	return out
+}
+
+/**
+ * Splits text into lines; empty text has none.
+ */
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+/**
+ * Hashes text, ignoring surrounding whitespace so re-saved files compare equal.
+ */
+func hash(s string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(s)))
+	return hex.EncodeToString(sum[:8])
+}
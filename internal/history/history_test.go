@@ -0,0 +1,193 @@
+package history
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/adamdeleeuw/ssh-portfolio/internal/tui"
+)
+
+/**
+ * Tests that a returning visitor gets section-level changes,
+ * and that history survives reopening the store.
+ */
+func TestStore_Visit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	first := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	store, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	tabs := []tui.Tab{
+		{Name: "Welcome", Source: "# Welcome\n\nHi {{ .Visitor }}\n"},
+		{Name: "Projects", Source: "## Alpha\n\nOld\n\n## Beta\n\nSame\n"},
+	}
+	changes, since, err := store.Visit("SHA256:abc", tabs, first)
+	if err != nil {
+		t.Fatalf("Visit failed: %v", err)
+	}
+	if changes != nil || !since.IsZero() {
+		t.Errorf("Expected no changes on first visit, got %v since %v", changes, since)
+	}
+
+	// Reopen to make sure the visit was persisted
+	if err := store.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	store, err = Open(path)
+	if err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+
+	tabs = []tui.Tab{
+		{Name: "Welcome", Source: "# Welcome\n\nHi {{ .Visitor }}\n"},
+		{Name: "Projects", Source: "## Alpha\n\nNew\n\n## Gamma\n\nAdded\n"},
+		{Name: "Blog", Source: "## First post\n"},
+	}
+	changes, since, err = store.Visit("SHA256:abc", tabs, first.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("Visit failed: %v", err)
+	}
+	if !since.Equal(first) {
+		t.Errorf("Expected previous visit %v, got %v", first, since)
+	}
+
+	want := []tui.TabChange{
+		{
+			Tab: "Projects", Added: []string{"Gamma"}, Removed: []string{"Beta"}, Updated: []string{"Alpha"},
+			Details: map[string][]tui.DiffLine{
+				"Gamma": {{Op: '+', Text: "Added"}},
+				"Beta":  {{Op: '-', Text: "Same"}},
+				"Alpha": {{Op: '-', Text: "Old"}, {Op: '+', Text: "New"}},
+			},
+		},
+		{Tab: "Blog", New: true},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("Expected %+v, got %+v", want, changes)
+	}
+
+	// Other visitors are tracked separately
+	changes, _, _ = store.Visit("SHA256:other", tabs, first)
+	if changes != nil {
+		t.Errorf("Expected first visit for another key, got %v", changes)
+	}
+}
+
+/**
+ * Tests that visitors past the retention period are dropped on save.
+ */
+func TestStore_Retention(t *testing.T) {
+	store, err := Open(filepath.Join(t.TempDir(), "history.json"))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	store.Visit("SHA256:old", nil, start)
+	store.Visit("SHA256:new", nil, start.Add(retention+time.Hour))
+
+	if _, ok := store.visitors["SHA256:old"]; ok {
+		t.Error("Expected stale visitor to be dropped")
+	}
+	if _, ok := store.visitors["SHA256:new"]; !ok {
+		t.Error("Expected recent visitor to be kept")
+	}
+}
+
+/**
+ * Tests that the least recently seen visitors are dropped beyond
+ * maxVisitors.
+ */
+func TestStore_MaxVisitors(t *testing.T) {
+	store, err := Open(filepath.Join(t.TempDir(), "history.json"))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer store.Flush()
+
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i <= maxVisitors; i++ {
+		store.Visit(fmt.Sprintf("SHA256:%d", i), nil, start.Add(time.Duration(i)*time.Second))
+	}
+
+	if len(store.visitors) != maxVisitors {
+		t.Errorf("Expected %d visitors, got %d", maxVisitors, len(store.visitors))
+	}
+	if _, ok := store.visitors["SHA256:0"]; ok {
+		t.Error("Expected the least recently seen visitor to be dropped")
+	}
+	if _, ok := store.visitors[fmt.Sprintf("SHA256:%d", maxVisitors)]; !ok {
+		t.Error("Expected the latest visitor to be kept")
+	}
+}
+
+/**
+ * Tests that visits are written after a delay rather than one by one.
+ */
+func TestStore_DelayedSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	store, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	store.delay = 50 * time.Millisecond
+
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	store.Visit("SHA256:a", nil, now)
+	store.Visit("SHA256:b", nil, now)
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("Expected no write straight after a visit, got %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := os.Stat(path); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the visits to be written after the delay")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	if len(reopened.visitors) != 2 {
+		t.Errorf("Expected both visits in one write, got %d", len(reopened.visitors))
+	}
+}
+
+/**
+ * Tests the line diff shown for updated sections.
+ */
+func TestDiffLines(t *testing.T) {
+	got := diffLines("Intro\n\n- Go\n- Rust\n\nOutro", "Intro\n\n- Go\n- Zig\n\nOutro\nMore")
+	want := []tui.DiffLine{{Op: '-', Text: "- Rust"}, {Op: '+', Text: "- Zig"}, {Op: '+', Text: "More"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+}
+
+/**
+ * Tests splitting markdown into sections.
+ */
+func TestSections(t *testing.T) {
+	secs := sections("Intro\n\n# One\n```\n# not a heading\n```\n## Two\n")
+
+	var headings []string
+	for _, s := range secs {
+		headings = append(headings, s.Heading)
+	}
+	if want := []string{"", "One", "Two"}; !reflect.DeepEqual(headings, want) {
+		t.Errorf("Expected headings %q, got %q", want, headings)
+	}
+}
@@ -0,0 +1,173 @@
+package probe
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// A monitor nobody has looked at for this long stops probing until
+// it is looked at again
+const idleAfter = 24 * time.Hour
+
+/**
+ * Runs probes on an interval in the background. One monitor serves
+ * every session showing the same probes, so each service is checked
+ * once per interval however many visitors are connected.
+ */
+type Monitor struct {
+	probes   []Probe
+	interval time.Duration
+	size     int // Results kept per probe
+
+	mu       sync.Mutex
+	history  [][]Result // Per probe, oldest first
+	running  bool
+	stopping bool // Stop was called since the last Snapshot
+	lastUsed time.Time
+}
+
+/**
+ * A probe and its recent results.
+ */
+type Status struct {
+	Name    string
+	Results []Result // Oldest first; empty until the first check finishes
+}
+
+/**
+ * Creates a monitor. Probing starts with the first call to Snapshot.
+ * @param probes - Services to check
+ * @param interval - Time between rounds of checks
+ * @param size - Results kept per probe
+ * @return Idle monitor
+ */
+func NewMonitor(probes []Probe, interval time.Duration, size int) *Monitor {
+	return &Monitor{
+		probes:   probes,
+		interval: interval,
+		size:     max(size, 1),
+		history:  make([][]Result, len(probes)),
+	}
+}
+
+/**
+ * Returns the recent results of every probe, starting the background
+ * checks if they are not running.
+ * @return One status per probe, in configuration order
+ */
+func (m *Monitor) Snapshot() []Status {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lastUsed = time.Now()
+	m.stopping = false
+	if !m.running {
+		m.running = true
+		go m.run()
+	}
+
+	statuses := make([]Status, len(m.probes))
+	for i, p := range m.probes {
+		statuses[i] = Status{Name: p.Name, Results: append([]Result(nil), m.history[i]...)}
+	}
+	return statuses
+}
+
+/**
+ * Replaces the probes, e.g. to run command probes from a new content
+ * directory, keeping the history of each.
+ * @param probes - Same services as before, in the same order
+ */
+func (m *Monitor) SetProbes(probes []Probe) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(probes) == len(m.probes) {
+		m.probes = probes
+	}
+}
+
+/**
+ * Stops probing after the current round, unless Snapshot is called
+ * again before then.
+ */
+func (m *Monitor) Stop() {
+	m.mu.Lock()
+	m.stopping = true
+	m.mu.Unlock()
+}
+
+/**
+ * Checks every probe each interval until the monitor goes idle or is
+ * stopped.
+ */
+func (m *Monitor) run() {
+	for {
+		m.round()
+
+		time.Sleep(m.interval)
+		m.mu.Lock()
+		if m.stopping || time.Since(m.lastUsed) > idleAfter {
+			m.running = false
+			m.mu.Unlock()
+			return
+		}
+		m.mu.Unlock()
+	}
+}
+
+/**
+ * Checks every probe concurrently and records the results.
+ */
+func (m *Monitor) round() {
+	m.mu.Lock()
+	probes := m.probes
+	m.mu.Unlock()
+
+	results := make([]Result, len(probes))
+	var wg sync.WaitGroup
+	for i, p := range probes {
+		wg.Go(func() {
+			results[i] = Check(context.Background(), p)
+		})
+	}
+	wg.Wait()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, r := range results {
+		h := append(m.history[i], r)
+		if len(h) > m.size {
+			h = h[len(h)-m.size:]
+		}
+		m.history[i] = h
+	}
+}
+
+/**
+ * Share of results that were up.
+ * @return Fraction between 0 and 1, or -1 if there are no results
+ */
+func (s Status) Uptime() float64 {
+	if len(s.Results) == 0 {
+		return -1
+	}
+	up := 0
+	for _, r := range s.Results {
+		if r.Up {
+			up++
+		}
+	}
+	return float64(up) / float64(len(s.Results))
+}
+
+/**
+ * The most recent result.
+ * @return Result, and false if the probe has not run yet
+ */
+func (s Status) Latest() (Result, bool) {
+	if len(s.Results) == 0 {
+		return Result{}, false
+	}
+	return s.Results[len(s.Results)-1], true
+}
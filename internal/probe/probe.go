@@ -0,0 +1,111 @@
+// Package probe checks whether services are reachable: TCP ports,
+// HTTP endpoints and health-check commands. A Monitor runs a set of
+// probes on an interval and keeps a bounded history of the results.
+package probe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/adamdeleeuw/ssh-portfolio/internal/extproc"
+)
+
+// Limit for a probe with no timeout set
+const DefaultTimeout = 5 * time.Second
+
+// Response bytes read from an HTTP endpoint before it is counted as up
+const maxHTTPBody = 64 << 10
+
+/**
+ * One service check. Exactly one of TCP, HTTP and Command is set.
+ */
+type Probe struct {
+	Name    string
+	TCP     string           // "host:port" that must accept a connection
+	HTTP    string           // URL that must answer with a status below 400
+	Command *extproc.Command // Program that must exit successfully
+	Timeout time.Duration    // Limit for one check (default 5s)
+}
+
+/**
+ * Outcome of one check.
+ */
+type Result struct {
+	At      time.Time
+	Up      bool
+	Latency time.Duration // Time the check took
+	Err     string        // Why the service is down
+}
+
+// Client for HTTP probes; redirects are followed like a browser would
+var httpClient = &http.Client{}
+
+/**
+ * Runs a probe once.
+ * @param ctx - Cancels the check
+ * @param p - Probe to run
+ * @return Result, down with a reason if the check failed
+ */
+func Check(ctx context.Context, p Probe) Result {
+	timeout := p.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx, p, timeout)
+	r := Result{At: start, Up: err == nil, Latency: time.Since(start)}
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = fmt.Errorf("timed out after %s", timeout)
+		}
+		r.Err = err.Error()
+	}
+	return r
+}
+
+/**
+ * Performs the check for the probe's kind.
+ */
+func check(ctx context.Context, p Probe, timeout time.Duration) error {
+	switch {
+	case p.TCP != "":
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "tcp", p.TCP)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+
+	case p.HTTP != "":
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.HTTP, nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("User-Agent", "ssh-portfolio-probe")
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		io.Copy(io.Discard, io.LimitReader(resp.Body, maxHTTPBody))
+		if resp.StatusCode >= 400 {
+			return fmt.Errorf("HTTP %s", resp.Status)
+		}
+		return nil
+
+	case p.Command != nil:
+		cmd := *p.Command
+		cmd.Timeout = timeout
+		_, err := extproc.Run(ctx, cmd)
+		return err
+	}
+	return errors.New("nothing to check")
+}
@@ -0,0 +1,140 @@
+package probe
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adamdeleeuw/ssh-portfolio/internal/extproc"
+)
+
+/**
+ * Tests TCP probes against an open and a closed port.
+ */
+func TestCheck_TCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+
+	if r := Check(context.Background(), Probe{TCP: addr}); !r.Up || r.Err != "" {
+		t.Errorf("Expected open port to be up, got %+v", r)
+	}
+
+	ln.Close()
+	if r := Check(context.Background(), Probe{TCP: addr}); r.Up || r.Err == "" {
+		t.Errorf("Expected closed port to be down, got %+v", r)
+	}
+}
+
+/**
+ * Tests HTTP probes count error statuses and slow responses as down.
+ */
+func TestCheck_HTTP(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/broken":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case "/slow":
+			time.Sleep(200 * time.Millisecond)
+		}
+	}))
+	defer srv.Close()
+
+	if r := Check(context.Background(), Probe{HTTP: srv.URL + "/"}); !r.Up || r.Latency <= 0 {
+		t.Errorf("Expected up with latency, got %+v", r)
+	}
+	if r := Check(context.Background(), Probe{HTTP: srv.URL + "/broken"}); r.Up || !strings.Contains(r.Err, "503") {
+		t.Errorf("Expected 503 to be down, got %+v", r)
+	}
+	r := Check(context.Background(), Probe{HTTP: srv.URL + "/slow", Timeout: 50 * time.Millisecond})
+	if r.Up || !strings.Contains(r.Err, "timed out") {
+		t.Errorf("Expected timeout, got %+v", r)
+	}
+}
+
+/**
+ * Tests command probes follow the program's exit status.
+ */
+func TestCheck_Command(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("requires sh")
+	}
+
+	ok := Probe{Command: &extproc.Command{Args: []string{"sh", "-c", "exit 0"}}}
+	if r := Check(context.Background(), ok); !r.Up {
+		t.Errorf("Expected up, got %+v", r)
+	}
+	fail := Probe{Command: &extproc.Command{Args: []string{"sh", "-c", "echo db unreachable >&2; exit 1"}}}
+	if r := Check(context.Background(), fail); r.Up || !strings.Contains(r.Err, "db unreachable") {
+		t.Errorf("Expected down with stderr, got %+v", r)
+	}
+}
+
+/**
+ * Tests the history is bounded and uptime counts up results.
+ */
+func TestMonitor_History(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	m := NewMonitor([]Probe{{Name: "up", TCP: ln.Addr().String()}, {Name: "down", HTTP: "http://127.0.0.1:1/"}}, time.Hour, 3)
+	for range 5 {
+		m.round()
+	}
+
+	statuses := m.Snapshot()
+	if len(statuses) != 2 || statuses[0].Name != "up" {
+		t.Fatalf("Unexpected statuses %+v", statuses)
+	}
+	if len(statuses[0].Results) != 3 || statuses[0].Uptime() != 1 || statuses[1].Uptime() != 0 {
+		t.Errorf("Expected 3 results at 100%% and 0%%, got %+v", statuses)
+	}
+	if latest, ok := statuses[1].Latest(); !ok || latest.Up {
+		t.Errorf("Expected latest result down, got %+v", latest)
+	}
+	if (Status{}).Uptime() != -1 {
+		t.Error("Expected -1 uptime without results")
+	}
+}
+
+/**
+ * Tests replacing the probes keeps their history, and a stopped
+ * monitor stops probing.
+ */
+func TestMonitor_SetProbesAndStop(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	m := NewMonitor([]Probe{{Name: "old", TCP: ln.Addr().String()}}, 10*time.Millisecond, 5)
+	m.round()
+	m.SetProbes([]Probe{{Name: "new", TCP: ln.Addr().String()}})
+	m.SetProbes(nil)
+	statuses := m.Snapshot()
+	if statuses[0].Name != "new" || len(statuses[0].Results) == 0 {
+		t.Errorf("Expected the new probe with the old history, got %+v", statuses)
+	}
+
+	m.Stop()
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		m.mu.Lock()
+		running := m.running
+		m.mu.Unlock()
+		if !running {
+			return
+		}
+	}
+	t.Error("Expected the monitor to stop")
+}
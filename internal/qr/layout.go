@@ -0,0 +1,294 @@
+package qr
+
+// Format information bits for each level (the standard orders them M, L, H, Q)
+var levelFormatBits = [4]int{Low: 1, Medium: 0, Quartile: 3, High: 2}
+
+/**
+ * Sets a module and records whether it belongs to a function pattern.
+ */
+func (c *Code) set(x, y int, dark bool, function []bool) {
+	c.modules[y*c.Size+x] = dark
+	function[y*c.Size+x] = true
+}
+
+/**
+ * Draws finder, timing and alignment patterns and version information,
+ * and reserves the format information areas.
+ */
+func (c *Code) drawFunctionPatterns(function []bool) {
+	for i := range c.Size {
+		c.set(6, i, i%2 == 0, function)
+		c.set(i, 6, i%2 == 0, function)
+	}
+
+	c.drawFinder(3, 3, function)
+	c.drawFinder(c.Size-4, 3, function)
+	c.drawFinder(3, c.Size-4, function)
+
+	pos := alignmentPositions(c.Version)
+	last := len(pos) - 1
+	for i, x := range pos {
+		for j, y := range pos {
+			// Skip the three corners taken by finders
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					c.set(x+dx, y+dy, max(abs(dx), abs(dy)) != 1, function)
+				}
+			}
+		}
+	}
+
+	c.drawFormatBits(0, function) // Reserve; redrawn once the mask is chosen
+	c.drawVersionBits(function)
+}
+
+/**
+ * Draws a finder pattern and its separator, centered at x, y.
+ */
+func (c *Code) drawFinder(x, y int, function []bool) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || yy < 0 || xx >= c.Size || yy >= c.Size {
+				continue
+			}
+			dist := max(abs(dx), abs(dy))
+			c.set(xx, yy, dist != 2 && dist != 4, function)
+		}
+	}
+}
+
+/**
+ * Lists the centre coordinates of alignment patterns along one axis.
+ */
+func alignmentPositions(version int) []int {
+	if version == 1 {
+		return nil
+	}
+	n := version/7 + 2
+	step := (version*8 + n*3 + 5) / (n*4 - 4) * 2
+	size := version*4 + 17
+
+	pos := make([]int, n)
+	pos[0] = 6
+	for i, p := n-1, size-7; i >= 1; i, p = i-1, p-step {
+		pos[i] = p
+	}
+	return pos
+}
+
+/**
+ * Draws both copies of the format information (level and mask),
+ * protected by a BCH code, plus the always-dark module.
+ */
+func (c *Code) drawFormatBits(mask int, function []bool) {
+	data := levelFormatBits[c.Level]<<3 | mask
+	rem := data
+	for range 10 {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return bits>>i&1 == 1 }
+
+	// Around the top-left finder
+	for i := 0; i <= 5; i++ {
+		c.set(8, i, bit(i), function)
+	}
+	c.set(8, 7, bit(6), function)
+	c.set(8, 8, bit(7), function)
+	c.set(7, 8, bit(8), function)
+	for i := 9; i < 15; i++ {
+		c.set(14-i, 8, bit(i), function)
+	}
+
+	// Split between the other two finders
+	for i := range 8 {
+		c.set(c.Size-1-i, 8, bit(i), function)
+	}
+	for i := 8; i < 15; i++ {
+		c.set(8, c.Size-15+i, bit(i), function)
+	}
+	c.set(8, c.Size-8, true, function)
+}
+
+/**
+ * Draws both copies of the version information (versions 7 and up).
+ */
+func (c *Code) drawVersionBits(function []bool) {
+	if c.Version < 7 {
+		return
+	}
+	rem := c.Version
+	for range 12 {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+	}
+	bits := c.Version<<12 | rem
+
+	for i := range 18 {
+		dark := bits>>i&1 == 1
+		a, b := c.Size-11+i%3, i/3
+		c.set(a, b, dark, function)
+		c.set(b, a, dark, function)
+	}
+}
+
+/**
+ * Places codewords in the zigzag order the standard defines: pairs of
+ * columns from the right, alternating upwards and downwards, skipping
+ * the vertical timing pattern and function modules.
+ */
+func (c *Code) drawCodewords(data []byte, function []bool) {
+	i := 0
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := range c.Size {
+			for j := range 2 {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = c.Size - 1 - vert
+				}
+				if function[y*c.Size+x] || i >= len(data)*8 {
+					continue
+				}
+				c.modules[y*c.Size+x] = data[i>>3]>>(7-i&7)&1 == 1
+				i++
+			}
+		}
+	}
+}
+
+/**
+ * XORs a mask pattern over the data modules. Applying it twice undoes it.
+ */
+func (c *Code) applyMask(mask int, function []bool) {
+	for y := range c.Size {
+		for x := range c.Size {
+			if function[y*c.Size+x] {
+				continue
+			}
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert {
+				c.modules[y*c.Size+x] = !c.modules[y*c.Size+x]
+			}
+		}
+	}
+}
+
+/**
+ * Scores the code with the standard's four penalty rules; the mask
+ * with the lowest score is easiest to scan.
+ */
+func (c *Code) penalty() int {
+	score := 0
+
+	// Runs of five or more, and finder-like 1:1:3:1:1 patterns, in rows and columns
+	line := make([]bool, c.Size)
+	for _, vertical := range []bool{false, true} {
+		for i := range c.Size {
+			for j := range c.Size {
+				if vertical {
+					line[j] = c.Dark(i, j)
+				} else {
+					line[j] = c.Dark(j, i)
+				}
+			}
+			score += linePenalty(line)
+		}
+	}
+
+	// 2x2 blocks of one colour
+	for y := 0; y < c.Size-1; y++ {
+		for x := 0; x < c.Size-1; x++ {
+			d := c.Dark(x, y)
+			if d == c.Dark(x+1, y) && d == c.Dark(x, y+1) && d == c.Dark(x+1, y+1) {
+				score += 3
+			}
+		}
+	}
+
+	// Balance of dark and light
+	dark := 0
+	for _, m := range c.modules {
+		if m {
+			dark++
+		}
+	}
+	total := len(c.modules)
+	k := (abs(dark*20-total*10)+total-1)/total - 1
+	score += k * 10
+
+	return score
+}
+
+// Finder-like pattern with four light modules on one side
+var finderLike = [][]bool{
+	{true, false, true, true, true, false, true, false, false, false, false},
+	{false, false, false, false, true, false, true, true, true, false, true},
+}
+
+/**
+ * Scores one row or column for rules 1 and 3.
+ */
+func linePenalty(line []bool) int {
+	score := 0
+
+	run := 1
+	for i := 1; i <= len(line); i++ {
+		if i < len(line) && line[i] == line[i-1] {
+			run++
+			continue
+		}
+		if run >= 5 {
+			score += 3 + run - 5
+		}
+		run = 1
+	}
+
+	for i := 0; i+len(finderLike[0]) <= len(line); i++ {
+		for _, pattern := range finderLike {
+			match := true
+			for j, want := range pattern {
+				if line[i+j] != want {
+					match = false
+					break
+				}
+			}
+			if match {
+				score += 40
+			}
+		}
+	}
+
+	return score
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,219 @@
+// Package qr encodes text as QR codes (ISO/IEC 18004, byte mode) and
+// draws them with Unicode block characters.
+package qr
+
+import (
+	"errors"
+	"strings"
+)
+
+/**
+ * Error correction level: how much of the code can be damaged
+ * (or covered) and still scan.
+ */
+type Level int
+
+const (
+	Low      Level = iota // ~7% recoverable
+	Medium                // ~15% recoverable
+	Quartile              // ~25% recoverable
+	High                  // ~30% recoverable
+)
+
+// Codes larger than this are rejected; they would not fit a terminal anyway
+const maxVersion = 40
+
+// ErrTooLong is returned when the text does not fit the largest QR code
+var ErrTooLong = errors.New("text is too long for a QR code")
+
+/**
+ * An encoded QR code: a square grid of dark and light modules.
+ */
+type Code struct {
+	Version int    // 1 to 40; the code is 17 + 4*Version modules wide
+	Level   Level  // Error correction level actually used
+	Size    int    // Modules per side
+	modules []bool // Row-major, true = dark
+}
+
+/**
+ * Encodes text in byte mode at the smallest version that fits.
+ * The error correction level is raised above the minimum when that
+ * does not make the code any larger.
+ * @param text - Text to encode (UTF-8)
+ * @param level - Minimum error correction level
+ * @return Encoded code
+ * @return ErrTooLong if the text does not fit version 40
+ */
+func Encode(text string, level Level) (*Code, error) {
+	data := []byte(text)
+
+	version := 0
+	for v := 1; v <= maxVersion; v++ {
+		if dataBits(v, len(data)) <= dataCodewords(v, level)*8 {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, ErrTooLong
+	}
+	for l := level + 1; l <= High; l++ {
+		if dataBits(version, len(data)) <= dataCodewords(version, l)*8 {
+			level = l
+		}
+	}
+
+	c := &Code{Version: version, Level: level, Size: version*4 + 17}
+	c.modules = make([]bool, c.Size*c.Size)
+	function := make([]bool, c.Size*c.Size)
+
+	c.drawFunctionPatterns(function)
+	c.drawCodewords(addErrorCorrection(encodeData(data, version, level), version, level), function)
+
+	// Keep the mask with the lowest penalty
+	best, bestPenalty := 0, -1
+	for mask := range 8 {
+		c.applyMask(mask, function)
+		c.drawFormatBits(mask, function)
+		if p := c.penalty(); bestPenalty < 0 || p < bestPenalty {
+			best, bestPenalty = mask, p
+		}
+		c.applyMask(mask, function) // XOR again to undo
+	}
+	c.applyMask(best, function)
+	c.drawFormatBits(best, function)
+
+	return c, nil
+}
+
+/**
+ * Reports whether the module at column x, row y is dark.
+ * Coordinates outside the code are light (the quiet zone).
+ */
+func (c *Code) Dark(x, y int) bool {
+	if x < 0 || y < 0 || x >= c.Size || y >= c.Size {
+		return false
+	}
+	return c.modules[y*c.Size+x]
+}
+
+/**
+ * Draws the code with half blocks, two module rows per line.
+ * Block characters mark LIGHT modules, so the result must be shown
+ * light-on-dark (e.g. white foreground on a black background); the
+ * terminal background then forms the dark modules.
+ * @param quiet - Light border in modules (the standard asks for 4;
+ *                2 scans reliably on screens)
+ * @return Lines of equal width Size+2*quiet
+ */
+func (c *Code) Lines(quiet int) []string {
+	var lines []string
+	for y := -quiet; y < c.Size+quiet; y += 2 {
+		var b strings.Builder
+		for x := -quiet; x < c.Size+quiet; x++ {
+			top := !c.Dark(x, y)
+			bottom := !c.Dark(x, y+1) && y+1 < c.Size+quiet
+			switch {
+			case top && bottom:
+				b.WriteRune('█')
+			case top:
+				b.WriteRune('▀')
+			case bottom:
+				b.WriteRune('▄')
+			default:
+				b.WriteRune(' ')
+			}
+		}
+		lines = append(lines, b.String())
+	}
+	return lines
+}
+
+/**
+ * Draws the code at twice the size of Lines: each module is two
+ * cells wide and one line tall. Block characters mark light modules,
+ * as with Lines.
+ * @param quiet - Light border in modules
+ * @return Lines of equal width 2*(Size+2*quiet)
+ */
+func (c *Code) LargeLines(quiet int) []string {
+	var lines []string
+	for y := -quiet; y < c.Size+quiet; y++ {
+		var b strings.Builder
+		for x := -quiet; x < c.Size+quiet; x++ {
+			if c.Dark(x, y) {
+				b.WriteString("  ")
+			} else {
+				b.WriteString("██")
+			}
+		}
+		lines = append(lines, b.String())
+	}
+	return lines
+}
+
+/**
+ * Number of bits needed for a byte-mode segment of n bytes:
+ * mode indicator, character count and data.
+ */
+func dataBits(version, n int) int {
+	countBits := 8
+	if version >= 10 {
+		countBits = 16
+	}
+	if n >= 1<<countBits {
+		return 1 << 30 // Count does not fit; never satisfiable
+	}
+	return 4 + countBits + n*8
+}
+
+/**
+ * Builds the data codewords: segment header, data, terminator and padding.
+ */
+func encodeData(data []byte, version int, level Level) []byte {
+	var bits bitBuffer
+	bits.append(0b0100, 4) // Byte mode
+	if version >= 10 {
+		bits.append(len(data), 16)
+	} else {
+		bits.append(len(data), 8)
+	}
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+
+	capacity := dataCodewords(version, level) * 8
+	bits.append(0, min(4, capacity-bits.len()))
+	bits.append(0, (8-bits.len()%8)%8)
+	for pad := 0xEC; bits.len() < capacity; pad ^= 0xEC ^ 0x11 {
+		bits.append(pad, 8)
+	}
+
+	return bits.bytes()
+}
+
+/**
+ * Appends bits most significant first.
+ */
+type bitBuffer []bool
+
+func (b *bitBuffer) append(value, n int) {
+	for i := n - 1; i >= 0; i-- {
+		*b = append(*b, value>>i&1 == 1)
+	}
+}
+
+func (b bitBuffer) len() int {
+	return len(b)
+}
+
+func (b bitBuffer) bytes() []byte {
+	out := make([]byte, (len(b)+7)/8)
+	for i, bit := range b {
+		if bit {
+			out[i/8] |= 0x80 >> (i % 8)
+		}
+	}
+	return out
+}
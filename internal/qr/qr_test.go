@@ -0,0 +1,238 @@
+package qr
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+/**
+ * Tests Reed-Solomon output against the worked example in the
+ * standard's annex ("HELLO WORLD", version 1-M).
+ */
+func TestReedSolomon(t *testing.T) {
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+
+	if got := rsRemainder(data, rsDivisor(10)); !bytes.Equal(got, want) {
+		t.Errorf("rsRemainder = %v, want %v", got, want)
+	}
+}
+
+/**
+ * Tests capacities against the standard's tables for byte mode.
+ */
+func TestCapacity(t *testing.T) {
+	tests := []struct {
+		version int
+		level   Level
+		bytes   int
+	}{
+		{1, Low, 17},
+		{1, High, 7},
+		{10, Medium, 213},
+		{40, Low, 2953},
+	}
+
+	for _, tt := range tests {
+		got := (dataCodewords(tt.version, tt.level)*8 - 4 - 8) / 8
+		if tt.version >= 10 {
+			got = (dataCodewords(tt.version, tt.level)*8 - 4 - 16) / 8
+		}
+		if got != tt.bytes {
+			t.Errorf("version %d level %d holds %d bytes, want %d", tt.version, tt.level, got, tt.bytes)
+		}
+	}
+}
+
+/**
+ * Tests that encoded codes carry valid format information and that the
+ * codewords read back from the grid pass the error correction check
+ * and contain the text.
+ */
+func TestEncode_RoundTrip(t *testing.T) {
+	for _, text := range []string{
+		"https://example.com",
+		"mailto:someone@example.com",
+		strings.Repeat("BEGIN:VCARD vCard with enough text to need several blocks ", 5),
+	} {
+		c, err := Encode(text, Medium)
+		if err != nil {
+			t.Fatalf("Encode(%q) failed: %v", text, err)
+		}
+		if c.Size != c.Version*4+17 {
+			t.Errorf("Unexpected size %d for version %d", c.Size, c.Version)
+		}
+
+		level, mask := readFormat(t, c)
+		if level != c.Level {
+			t.Errorf("Format bits say level %d, code has %d", level, c.Level)
+		}
+
+		if data := readData(c, mask); string(data) != text {
+			t.Errorf("Decoded %q, want %q", data, text)
+		}
+	}
+
+	if _, err := Encode(strings.Repeat("x", 3000), Low); err != ErrTooLong {
+		t.Errorf("Expected ErrTooLong, got %v", err)
+	}
+}
+
+/**
+ * Tests drawing sizes and the quiet zone.
+ */
+func TestLines(t *testing.T) {
+	c, err := Encode("hi", Low)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := c.Lines(2)
+	if len(lines) != (c.Size+4+1)/2 {
+		t.Errorf("Expected %d lines, got %d", (c.Size+5)/2, len(lines))
+	}
+	for _, l := range lines {
+		if n := len([]rune(l)); n != c.Size+4 {
+			t.Fatalf("Expected width %d, got %d", c.Size+4, n)
+		}
+	}
+	if strings.Trim(lines[0], "█") != "" {
+		t.Errorf("Expected a light quiet zone along the top, got %q", lines[0])
+	}
+
+	large := c.LargeLines(2)
+	if len(large) != c.Size+4 || len([]rune(large[0])) != 2*(c.Size+4) {
+		t.Errorf("Expected %d lines of width %d, got %d of %d", c.Size+4, 2*(c.Size+4), len(large), len([]rune(large[0])))
+	}
+}
+
+/**
+ * Reads and checks the format information next to the top-left finder.
+ */
+func readFormat(t *testing.T, c *Code) (Level, int) {
+	t.Helper()
+	var bits int
+	get := func(i int, x, y int) {
+		if c.Dark(x, y) {
+			bits |= 1 << i
+		}
+	}
+	for i := 0; i <= 5; i++ {
+		get(i, 8, i)
+	}
+	get(6, 8, 7)
+	get(7, 8, 8)
+	get(8, 7, 8)
+	for i := 9; i < 15; i++ {
+		get(i, 14-i, 8)
+	}
+
+	bits ^= 0x5412
+	data := bits >> 10
+	rem := data
+	for range 10 {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	if rem&0x3FF != bits&0x3FF {
+		t.Fatalf("Format bits %015b fail the BCH check", bits)
+	}
+
+	for l, f := range levelFormatBits {
+		if f == data>>3 {
+			return Level(l), data & 7
+		}
+	}
+	t.Fatalf("Unknown level bits %d", data>>3)
+	return 0, 0
+}
+
+/**
+ * Reads the codewords back, undoes interleaving and decodes the text.
+ * Returns nil if any block's error correction does not match.
+ */
+func readData(c *Code, mask int) []byte {
+	// Rebuild the function pattern map and unmask a copy of the grid
+	plain := &Code{Version: c.Version, Level: c.Level, Size: c.Size, modules: make([]bool, len(c.modules))}
+	function := make([]bool, len(c.modules))
+	plain.drawFunctionPatterns(function)
+	copy(plain.modules, c.modules)
+	plain.applyMask(mask, function)
+
+	var bits bitBuffer
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := range c.Size {
+			for j := range 2 {
+				x, y := right-j, vert
+				if (right+1)&2 == 0 {
+					y = c.Size - 1 - vert
+				}
+				if !function[y*c.Size+x] {
+					bits = append(bits, plain.Dark(x, y))
+				}
+			}
+		}
+	}
+	codewords := bits.bytes()[:rawDataModules(c.Version)/8]
+
+	// Deinterleave
+	numBlocks := eccBlocks[c.Level][c.Version]
+	eccLen := eccPerBlock[c.Level][c.Version]
+	raw := len(codewords)
+	numShort := numBlocks - raw%numBlocks
+	shortLen := raw / numBlocks
+
+	blocks := make([][]byte, numBlocks)
+	k := 0
+	for i := 0; i < shortLen+1; i++ {
+		for j := range blocks {
+			if i == shortLen-eccLen && j < numShort {
+				continue
+			}
+			blocks[j] = append(blocks[j], codewords[k])
+			k++
+		}
+	}
+
+	var data []byte
+	divisor := rsDivisor(eccLen)
+	for _, b := range blocks {
+		n := len(b) - eccLen
+		if !bytes.Equal(rsRemainder(b[:n], divisor), b[n:]) {
+			return nil
+		}
+		data = append(data, b[:n]...)
+	}
+
+	// Parse the byte-mode segment header
+	var stream bitBuffer
+	for _, b := range data {
+		stream.append(int(b), 8)
+	}
+	read := func(n int) int {
+		v := 0
+		for range n {
+			v <<= 1
+			if stream[0] {
+				v |= 1
+			}
+			stream = stream[1:]
+		}
+		return v
+	}
+	if read(4) != 0b0100 {
+		return nil
+	}
+	countBits := 8
+	if c.Version >= 10 {
+		countBits = 16
+	}
+	text := make([]byte, read(countBits))
+	for i := range text {
+		text[i] = byte(read(8))
+	}
+	return text
+}
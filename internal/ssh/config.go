@@ -2,16 +2,32 @@ package ssh
 
 import (
 	"os"
+	"path/filepath"
 	"strconv"
+	"time"
 )
 
 /**
  * Server configuration loaded from environment variables.
  */
 type Config struct {
-	Port         int
-	HostKeyPath  string
-	MaxPerMinute int // Rate limit: connections per minute per IP
+	Port          int
+	HostKeyPath   string
+	MaxPerMinute  int      // Rate limit: connections per minute per IP
+	AdminKeys     string   // authorized_keys file whose keys may preview drafts (optional)
+	ContentDir    string   // Directory of markdown content
+	HistoryPath   string   // JSON file recording what each returning visitor last saw
+	CommandTabs   bool     // Allow tabs.json to run programs, scan repositories or probe services from the server
+	MaxPrograms   int      // Interactive programs running at once across all sessions
+	ActivityRepos []string // Paths or globs outside the content directory activity tabs may scan
+	KeymapFile    string   // JSON file overriding key bindings (optional)
+
+	// Optional git content source; replaces ContentDir when GitRepo is set
+	GitRepo     string        // Bare repository or working tree path
+	GitRef      string        // Branch or tag to serve
+	GitPath     string        // Content directory inside the repository
+	GitInterval time.Duration // How often to check the ref for new commits
+	GitCacheDir string        // Writable directory for exported commits
 }
 
 /**
@@ -38,9 +54,65 @@ func LoadConfig() *Config {
 		}
 	}
 
+	contentDir := os.Getenv("CONTENT_DIR")
+	if contentDir == "" {
+		contentDir = "./content"
+	}
+
+	gitRef := os.Getenv("CONTENT_GIT_REF")
+	if gitRef == "" {
+		gitRef = "main"
+	}
+
+	gitInterval := time.Minute
+	if i := os.Getenv("CONTENT_GIT_INTERVAL"); i != "" {
+		if parsed, err := time.ParseDuration(i); err == nil && parsed > 0 {
+			gitInterval = parsed
+		}
+	}
+
+	// Exports live next to the host key by default, since that
+	// directory is the one volume that is writable in the container
+	gitCacheDir := os.Getenv("CONTENT_GIT_CACHE")
+	if gitCacheDir == "" {
+		gitCacheDir = filepath.Join(filepath.Dir(hostKeyPath), "content-cache")
+	}
+
+	historyPath := os.Getenv("HISTORY_PATH")
+	if historyPath == "" {
+		historyPath = filepath.Join(filepath.Dir(hostKeyPath), "history.json")
+	}
+
+	commandTabs, _ := strconv.ParseBool(os.Getenv("COMMAND_TABS"))
+
+	// Like PATH: entries separated by the OS list separator
+	var activityRepos []string
+	if r := os.Getenv("ACTIVITY_REPOS"); r != "" {
+		activityRepos = filepath.SplitList(r)
+	}
+
+	maxPrograms := 20
+	if m := os.Getenv("MAX_PROGRAMS"); m != "" {
+		if parsed, err := strconv.Atoi(m); err == nil && parsed > 0 {
+			maxPrograms = parsed
+		}
+	}
+
 	return &Config{
-		Port:         port,
-		HostKeyPath:  hostKeyPath,
-		MaxPerMinute: maxPerMinute,
+		Port:          port,
+		HostKeyPath:   hostKeyPath,
+		MaxPerMinute:  maxPerMinute,
+		AdminKeys:     os.Getenv("ADMIN_KEYS"),
+		ContentDir:    contentDir,
+		HistoryPath:   historyPath,
+		CommandTabs:   commandTabs,
+		MaxPrograms:   maxPrograms,
+		ActivityRepos: activityRepos,
+		KeymapFile:    os.Getenv("KEYMAP_FILE"),
+		GitRepo:       os.Getenv("CONTENT_GIT_REPO"),
+		GitRef:        gitRef,
+		GitPath:       os.Getenv("CONTENT_GIT_PATH"),
+		GitInterval:   gitInterval,
+		GitCacheDir:   gitCacheDir,
 	}
 }
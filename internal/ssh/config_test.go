@@ -13,9 +13,11 @@ func TestLoadConfig(t *testing.T) {
 	os.Setenv("PORT", "3000")
 
 	os.Setenv("RATE_LIMIT", "10")
+	os.Setenv("ACTIVITY_REPOS", "/srv/git/*:/home/me/code")
 	defer func() {
 		os.Unsetenv("PORT")
 		os.Unsetenv("RATE_LIMIT")
+		os.Unsetenv("ACTIVITY_REPOS")
 	}()
 
 	cfg := LoadConfig()
@@ -27,6 +29,10 @@ func TestLoadConfig(t *testing.T) {
 	if cfg.MaxPerMinute != 10 {
 		t.Errorf("Expected rate limit 10, got %d", cfg.MaxPerMinute)
 	}
+
+	if len(cfg.ActivityRepos) != 2 || cfg.ActivityRepos[1] != "/home/me/code" {
+		t.Errorf("Expected two allowed repository patterns, got %v", cfg.ActivityRepos)
+	}
 }
 
 /**
@@ -47,4 +53,50 @@ func TestLoadConfig_Defaults(t *testing.T) {
 	if cfg.MaxPerMinute != 60 {
 		t.Errorf("Expected default rate limit 60, got %d", cfg.MaxPerMinute)
 	}
+
+	if cfg.ContentDir != "./content" {
+		t.Errorf("Expected default content dir ./content, got %s", cfg.ContentDir)
+	}
+
+	if cfg.HistoryPath != "data/history.json" {
+		t.Errorf("Expected history next to host key, got %s", cfg.HistoryPath)
+	}
+
+	if cfg.CommandTabs {
+		t.Error("Expected command tabs to be disabled by default")
+	}
+
+	if cfg.MaxPrograms != 20 {
+		t.Errorf("Expected default program limit 20, got %d", cfg.MaxPrograms)
+	}
+
+	if cfg.GitRepo != "" || cfg.GitRef != "main" {
+		t.Errorf("Expected git source disabled with ref main, got %q@%q", cfg.GitRepo, cfg.GitRef)
+	}
+}
+
+/**
+ * Tests git content source configuration.
+ */
+func TestLoadConfig_GitContent(t *testing.T) {
+	os.Setenv("CONTENT_GIT_REPO", "/srv/content.git")
+	os.Setenv("CONTENT_GIT_INTERVAL", "30s")
+	os.Setenv("HOST_KEY_PATH", "/data/key")
+	defer func() {
+		os.Unsetenv("CONTENT_GIT_REPO")
+		os.Unsetenv("CONTENT_GIT_INTERVAL")
+		os.Unsetenv("HOST_KEY_PATH")
+	}()
+
+	cfg := LoadConfig()
+
+	if cfg.GitRepo != "/srv/content.git" {
+		t.Errorf("Expected git repo /srv/content.git, got %s", cfg.GitRepo)
+	}
+	if cfg.GitInterval.Seconds() != 30 {
+		t.Errorf("Expected 30s interval, got %v", cfg.GitInterval)
+	}
+	if cfg.GitCacheDir != "/data/content-cache" {
+		t.Errorf("Expected cache next to host key, got %s", cfg.GitCacheDir)
+	}
 }
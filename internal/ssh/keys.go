@@ -1,9 +1,12 @@
 package ssh
 
 import (
+	"bufio"
+	"bytes"
 	"crypto/ed25519"
 	"crypto/rand"
 	"encoding/pem"
+	"fmt"
 	"os"
 	"path/filepath"
 
@@ -70,3 +73,56 @@ func LoadOrGenerateHostKey(path string) (ssh.Signer, error) {
 
 	return signer, nil
 }
+
+/**
+ * Loads public keys from an authorized_keys style file.
+ * Blank lines and comments are skipped.
+ * @param path - File path; empty means no keys
+ * @return Parsed public keys
+ * @return error if the file cannot be read or a line is malformed
+ */
+func LoadAuthorizedKeys(path string) ([]ssh.PublicKey, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []ssh.PublicKey
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 || text[0] == '#' {
+			continue
+		}
+
+		key, _, _, _, err := gossh.ParseAuthorizedKey(text)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, scanner.Err()
+}
+
+/**
+ * Reports whether a key is in the given set.
+ * @param key - Key offered by the client (may be nil)
+ * @param keys - Allowed keys
+ * @return true if key matches one of keys
+ */
+func containsKey(keys []ssh.PublicKey, key ssh.PublicKey) bool {
+	if key == nil {
+		return false
+	}
+	for _, k := range keys {
+		if ssh.KeysEqual(k, key) {
+			return true
+		}
+	}
+	return false
+}
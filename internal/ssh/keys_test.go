@@ -4,6 +4,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	gossh "golang.org/x/crypto/ssh"
 )
 
 /**
@@ -39,3 +41,45 @@ func TestLoadOrGenerateHostKey(t *testing.T) {
 		t.Error("Loaded key doesn't match generated key")
 	}
 }
+
+/**
+ * Tests parsing an authorized_keys file and matching keys against it.
+ */
+func TestLoadAuthorizedKeys(t *testing.T) {
+	tempDir := t.TempDir()
+
+	signer, err := LoadOrGenerateHostKey(filepath.Join(tempDir, "admin_key"))
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	other, err := LoadOrGenerateHostKey(filepath.Join(tempDir, "other_key"))
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	path := filepath.Join(tempDir, "authorized_keys")
+	data := "# admins\n\n" + string(gossh.MarshalAuthorizedKey(signer.PublicKey()))
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatalf("Failed to write authorized_keys: %v", err)
+	}
+
+	keys, err := LoadAuthorizedKeys(path)
+	if err != nil {
+		t.Fatalf("LoadAuthorizedKeys failed: %v", err)
+	}
+	if len(keys) != 1 {
+		t.Fatalf("Expected 1 key, got %d", len(keys))
+	}
+
+	if !containsKey(keys, signer.PublicKey()) {
+		t.Error("Admin key should match")
+	}
+	if containsKey(keys, other.PublicKey()) || containsKey(keys, nil) {
+		t.Error("Other keys should not match")
+	}
+
+	// No file configured means no admins
+	if keys, err := LoadAuthorizedKeys(""); err != nil || keys != nil {
+		t.Errorf("Expected no keys for empty path, got %v (err %v)", keys, err)
+	}
+}
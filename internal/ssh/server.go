@@ -1,16 +1,31 @@
 package ssh
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"net"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/adamdeleeuw/ssh-portfolio/internal/content"
+	"github.com/adamdeleeuw/ssh-portfolio/internal/extproc"
+	"github.com/adamdeleeuw/ssh-portfolio/internal/history"
+	"github.com/adamdeleeuw/ssh-portfolio/internal/termimg"
 	"github.com/adamdeleeuw/ssh-portfolio/internal/tui"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/log"
 	"github.com/gliderlabs/ssh"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+var (
+	serverStart  = time.Now() // Exposed to content templates as uptime
+	visitorCount atomic.Int64 // Sessions served since start
+
+	openVisits atomic.Pointer[history.Store] // Saved by Shutdown (nil = no history)
 )
 
 /**
@@ -26,6 +41,37 @@ func StartServer(cfg *Config) error {
 		return fmt.Errorf("failed to load host key: %w", err)
 	}
 
+	// Keys allowed to preview drafts and scheduled content
+	adminKeys, err := LoadAuthorizedKeys(cfg.AdminKeys)
+	if err != nil {
+		return fmt.Errorf("failed to load admin keys: %w", err)
+	}
+
+	// Programs declared in tabs.json only run when the operator opts in
+	content.EnableCommands(cfg.CommandTabs)
+	content.AllowRepos(cfg.ActivityRepos)
+	extproc.SetMaxProcesses(cfg.MaxPrograms)
+
+	// Key bindings, replacing the built-in schemes' defaults
+	if cfg.KeymapFile != "" {
+		if err := tui.LoadKeymaps(cfg.KeymapFile); err != nil {
+			return fmt.Errorf("failed to load keymap: %w", err)
+		}
+	}
+
+	// Resolve where content comes from
+	source, err := newContentSource(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to load content: %w", err)
+	}
+
+	// Without history the server still works; visitors just see no badges
+	visits, err := history.Open(cfg.HistoryPath)
+	if err != nil {
+		log.Warn("Visit history disabled", "path", cfg.HistoryPath, "error", err)
+	}
+	openVisits.Store(visits)
+
 	// Create rate limiter
 	rateLimiter := NewRateLimiter(cfg.MaxPerMinute)
 
@@ -40,12 +86,11 @@ func StartServer(cfg *Config) error {
 
 	// Configure SSH server
 	server := &ssh.Server{
-		Addr:             fmt.Sprintf(":%d", cfg.Port),
-		Handler:          createSessionHandler(),
-		PublicKeyHandler: nil,
-		IdleTimeout:      5 * time.Minute,
+		Addr:        fmt.Sprintf(":%d", cfg.Port),
+		Handler:     createSessionHandler(source, adminKeys, visits),
+		IdleTimeout: 5 * time.Minute,
 	}
-
+	setupAuth(server)
 	server.AddHostKey(signer)
 
 	// Connection callback for rate limiting
@@ -70,12 +115,76 @@ func StartServer(cfg *Config) error {
 	return server.ListenAndServe()
 }
 
+// Context key of the public key a client proved it holds
+type contextKey string
+
+const verifiedKeyContextKey contextKey = "verified-public-key"
+
+/**
+ * Saves state the server writes lazily, before the process exits.
+ * @effects Writes pending visit history to HISTORY_PATH
+ */
+func Shutdown() {
+	if visits := openVisits.Load(); visits != nil {
+		if err := visits.Flush(); err != nil {
+			log.Error("Failed to save visit history", "error", err)
+		}
+	}
+}
+
+/**
+ * Lets everyone log in without a password. Any public key is accepted
+ * so sessions can be matched against admin keys and visit history;
+ * clients without keys fall through to an empty keyboard-interactive
+ * exchange.
+ *
+ * The key gliderlabs/ssh reports for a session is the last one offered,
+ * which a client can do without holding its private key. Only keys
+ * whose signature checked out are recorded for verifiedKey.
+ * @param server - Server to configure
+ */
+func setupAuth(server *ssh.Server) {
+	server.PublicKeyHandler = func(ssh.Context, ssh.PublicKey) bool {
+		return true
+	}
+	server.KeyboardInteractiveHandler = func(ctx ssh.Context, _ gossh.KeyboardInteractiveChallenge) bool {
+		ctx.SetValue(ssh.ContextKeyPublicKey, nil)
+		ctx.SetValue(verifiedKeyContextKey, nil)
+		return true
+	}
+	server.ServerConfigCallback = func(ctx ssh.Context) *gossh.ServerConfig {
+		return &gossh.ServerConfig{
+			VerifiedPublicKeyCallback: func(_ gossh.ConnMetadata, key gossh.PublicKey, perms *gossh.Permissions, _ string) (*gossh.Permissions, error) {
+				ctx.SetValue(verifiedKeyContextKey, key)
+				return perms, nil
+			},
+		}
+	}
+}
+
+/**
+ * Returns the public key a client authenticated with, if it signed
+ * with it. Keys that were only offered are ignored.
+ * @param ctx - Connection context
+ * @return Verified key, or nil for keyboard-interactive logins
+ */
+func verifiedKey(ctx ssh.Context) ssh.PublicKey {
+	key, _ := ctx.Value(verifiedKeyContextKey).(gossh.PublicKey)
+	return key
+}
+
 /**
  * Creates the SSH session handler that manages each connection.
+ * @param source - Where sessions load content from
+ * @param adminKeys - Keys whose sessions may preview unpublished content
+ * @param visits - Per-visitor history for change badges (nil to disable)
  * @return SSH Handler function
  */
-func createSessionHandler() ssh.Handler {
+func createSessionHandler(source *contentSource, adminKeys []ssh.PublicKey, visits *history.Store) ssh.Handler {
 	return func(sess ssh.Session) {
+		// Only a key the client signed with identifies it
+		key := verifiedKey(sess.Context())
+
 		// Handle PTY requests
 		ptyReq, winCh, isPty := sess.Pty()
 		if !isPty {
@@ -86,13 +195,31 @@ func createSessionHandler() ssh.Handler {
 
 		log.Info("Session started",
 			"user", sess.User(),
+			"admin", containsKey(adminKeys, key),
 			"term", ptyReq.Term,
 			"width", ptyReq.Window.Width,
 			"height", ptyReq.Window.Height,
 		)
 
-		// Load content tabs
-		tabs, err := content.LoadTabs("./content")
+		// Load content tabs, expanding per-session template values
+		contentSess := content.Session{
+			User:        sess.User(),
+			Visitors:    int(visitorCount.Add(1)),
+			ServerStart: serverStart,
+			Admin:       containsKey(adminKeys, key),
+		}
+
+		// Links are clickable where the terminal supports OSC 8, and
+		// footnotes elsewhere; HYPERLINKS=1 or 0 overrides the guess
+		contentSess.Hyperlinks = content.SupportsHyperlinks(ptyReq.Term, sess.Environ())
+		if v := envValue(sess.Environ(), "HYPERLINKS"); v != "" {
+			contentSess.Hyperlinks = v == "1"
+		}
+		// The session keeps reading the export it started with, even
+		// after a newer commit is picked up
+		contentDir, release := source.Acquire()
+		defer release()
+		tabs, err := content.LoadSessionTabs(contentDir, contentSess)
 		if err != nil {
 			io.WriteString(sess, fmt.Sprintf("Error loading content: %v\n", err))
 			sess.Exit(1)
@@ -102,10 +229,57 @@ func createSessionHandler() ssh.Handler {
 		// Create TUI model
 		sessionID := fmt.Sprintf("%s-%d", sess.User(), time.Now().Unix())
 		model := tui.NewModel(tabs, sessionID)
+		model.SetContentVersion(source.Version().String())
+		if content.Degraded(contentDir) {
+			model.SetNotice("Showing built-in content")
+		}
+
+		// Draw images with the best protocol the client supports
+		model.SetImageProtocol(termimg.Detect(ptyReq.Term, sess.Environ()))
+
+		// Make URLs the TUI draws clickable too
+		model.SetHyperlinks(contentSess.Hyperlinks)
+
+		// The mouse is off so terminals keep selecting text;
+		// ssh -o SetEnv=MOUSE=1 starts with it on
+		model.SetMouse(envValue(sess.Environ(), "MOUSE") == "1")
+
+		// Visitors can pick a key scheme with ssh -o SetEnv=KEYMAP=emacs
+		if name := envValue(sess.Environ(), "KEYMAP"); name != "" {
+			if err := model.SetKeymap(name); err != nil {
+				log.Debug("Ignoring requested keymap", "error", err)
+			}
+		}
+		if splash, err := content.LoadImage(contentDir, "splash.png"); err == nil {
+			model.SetSplashImage(splash)
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			log.Warn("Failed to load splash image", "error", err)
+		}
+
+		// Returning visitors (by key fingerprint) see what changed since last time
+		if key != nil && visits != nil {
+			changes, since, err := visits.Visit(gossh.FingerprintSHA256(key), tabs, time.Now())
+			if err != nil {
+				log.Warn("Failed to save visit history", "error", err)
+			}
+			model.SetChanges(changes, since)
+		}
 
 		// Set initial window dimensions before starting program
 		model.SetSize(ptyReq.Window.Width, ptyReq.Window.Height)
 
+		// Reload when a scheduled page is due to appear or disappear
+		model.SetReloader(func() ([]tui.Tab, time.Time, error) {
+			tabs, err := content.LoadSessionTabs(contentDir, contentSess)
+			return tabs, content.NextChange(contentDir, time.Now()), err
+		}, content.NextChange(contentDir, time.Now()))
+
+		// Re-render pages when the visitor switches theme with :theme
+		model.SetThemeLoader(func(style string) ([]tui.Tab, error) {
+			contentSess.Theme = style
+			return content.LoadSessionTabs(contentDir, contentSess)
+		})
+
 		// Create Bubble Tea program with custom input/output
 		p := tea.NewProgram(
 			model,
@@ -125,10 +299,103 @@ func createSessionHandler() ssh.Handler {
 		}()
 
 		// Run the program (blocks until quit)
-		if _, err := p.Run(); err != nil {
+		final, err := p.Run()
+		if err != nil {
 			log.Error("TUI error", "error", err)
 		}
 
+		// Stop programs behind command tabs
+		if m, ok := final.(tui.Model); ok {
+			m.Close()
+		} else {
+			model.Close()
+		}
+
 		log.Info("Session ended", "user", sess.User())
 	}
 }
+
+/**
+ * Directory new sessions load content from: either a fixed path
+ * or the latest export of a git repository.
+ */
+type contentSource struct {
+	dir string
+	git *content.GitSource
+}
+
+/**
+ * Creates the content source described by the configuration and,
+ * for git sources, starts polling the ref for new commits.
+ * @param cfg - Server configuration
+ * @return Content source
+ * @return error if the git repository cannot be exported
+ */
+func newContentSource(cfg *Config) (*contentSource, error) {
+	if cfg.GitRepo == "" {
+		if content.Degraded(cfg.ContentDir) {
+			log.Warn("Content directory missing, empty or unreadable, serving built-in content", "contentDir", cfg.ContentDir)
+		}
+		return &contentSource{dir: cfg.ContentDir}, nil
+	}
+
+	src, err := content.NewGitSource(cfg.GitRepo, cfg.GitRef, cfg.GitPath, cfg.GitCacheDir)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("Serving content from git", "repo", cfg.GitRepo, "ref", cfg.GitRef, "version", src.Version())
+
+	// Start polling goroutine
+	go func() {
+		ticker := time.NewTicker(cfg.GitInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			changed, err := src.Sync()
+			if err != nil {
+				log.Error("Content sync failed", "error", err)
+			} else if changed {
+				log.Info("Content updated", "version", src.Version())
+			}
+		}
+	}()
+
+	return &contentSource{git: src}, nil
+}
+
+/**
+ * Returns the directory to load content from right now, kept on disk
+ * until released.
+ * @return Content directory
+ * @return Function releasing it
+ */
+func (c *contentSource) Acquire() (string, func()) {
+	if c.git != nil {
+		return c.git.Acquire()
+	}
+	return c.dir, func() {}
+}
+
+/**
+ * Returns the commit being served, or the zero Version for plain directories.
+ */
+func (c *contentSource) Version() content.Version {
+	if c.git != nil {
+		return c.git.Version()
+	}
+	return content.Version{}
+}
+
+/**
+ * Looks up a variable in a client's environment.
+ * @param environ - "KEY=value" entries
+ * @param key - Variable name
+ * @return Value, or "" if the client did not send it
+ */
+func envValue(environ []string, key string) string {
+	for _, kv := range environ {
+		if k, v, ok := strings.Cut(kv, "="); ok && k == key {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,141 @@
+package ssh
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gliderlabs/ssh"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// Connection context that only keeps values, for calling auth handlers
+type fakeContext struct {
+	context.Context
+	sync.Mutex
+	values map[any]any
+}
+
+func newFakeContext() *fakeContext {
+	return &fakeContext{Context: context.Background(), values: map[any]any{}}
+}
+
+func (c *fakeContext) Value(key any) any             { return c.values[key] }
+func (c *fakeContext) SetValue(key, value any)       { c.values[key] = value }
+func (c *fakeContext) User() string                  { return "visitor" }
+func (c *fakeContext) SessionID() string             { return "" }
+func (c *fakeContext) ClientVersion() string         { return "" }
+func (c *fakeContext) ServerVersion() string         { return "" }
+func (c *fakeContext) RemoteAddr() net.Addr          { return nil }
+func (c *fakeContext) LocalAddr() net.Addr           { return nil }
+func (c *fakeContext) Permissions() *ssh.Permissions { return nil }
+
+/**
+ * Generates a key pair for a test client.
+ */
+func testSigner(t *testing.T) gossh.Signer {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := gossh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer
+}
+
+/**
+ * Tests offering an admin's public key without signing, then logging
+ * in with keyboard-interactive, doesn't identify the client as them.
+ */
+func TestSetupAuth_OfferedKey(t *testing.T) {
+	admin := testSigner(t).PublicKey()
+	server := &ssh.Server{}
+	setupAuth(server)
+	ctx := newFakeContext()
+
+	// gliderlabs/ssh records every key offered before asking the handler
+	ctx.SetValue(ssh.ContextKeyPublicKey, admin)
+	if !server.PublicKeyHandler(ctx, admin) {
+		t.Fatal("Expected any key to be accepted")
+	}
+	if !server.KeyboardInteractiveHandler(ctx, nil) {
+		t.Fatal("Expected keyboard-interactive logins to be accepted")
+	}
+	if key := verifiedKey(ctx); key != nil {
+		t.Errorf("Expected no verified key, got %s", gossh.FingerprintSHA256(key))
+	}
+	if ctx.Value(ssh.ContextKeyPublicKey) != nil {
+		t.Error("Expected the offered key to be forgotten")
+	}
+	if containsKey([]ssh.PublicKey{admin}, verifiedKey(ctx)) {
+		t.Error("Expected the client not to be an admin")
+	}
+
+	// A signature proves the key
+	config := server.ServerConfigCallback(ctx)
+	if _, err := config.VerifiedPublicKeyCallback(nil, admin, nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	if key := verifiedKey(ctx); key == nil || !ssh.KeysEqual(key, admin) {
+		t.Error("Expected the signed key to be verified")
+	}
+}
+
+/**
+ * Tests sessions see the key a client signed with, and none for
+ * keyboard-interactive logins.
+ */
+func TestSetupAuth_Sessions(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &ssh.Server{Handler: func(sess ssh.Session) {
+		fingerprint := "none"
+		if key := verifiedKey(sess.Context()); key != nil {
+			fingerprint = gossh.FingerprintSHA256(key)
+		}
+		io.WriteString(sess, fingerprint)
+	}}
+	setupAuth(server)
+	server.AddHostKey(testSigner(t))
+	go server.Serve(listener)
+	defer server.Close()
+
+	login := func(auth gossh.AuthMethod) string {
+		client, err := gossh.Dial("tcp", listener.Addr().String(), &gossh.ClientConfig{
+			User:            "visitor",
+			Auth:            []gossh.AuthMethod{auth},
+			HostKeyCallback: gossh.InsecureIgnoreHostKey(),
+			Timeout:         5 * time.Second,
+		})
+		if err != nil {
+			t.Fatalf("Failed to log in: %v", err)
+		}
+		defer client.Close()
+		sess, err := client.NewSession()
+		if err != nil {
+			t.Fatal(err)
+		}
+		out, _ := sess.Output("")
+		return string(out)
+	}
+
+	signer := testSigner(t)
+	if got := login(gossh.PublicKeys(signer)); got != gossh.FingerprintSHA256(signer.PublicKey()) {
+		t.Errorf("Expected the signed key, got %q", got)
+	}
+	none := gossh.KeyboardInteractive(func(string, string, []string, []bool) ([]string, error) {
+		return nil, nil
+	})
+	if got := login(none); got != "none" {
+		t.Errorf("Expected no key for keyboard-interactive, got %q", got)
+	}
+}
@@ -0,0 +1,153 @@
+package termimg
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+	"strings"
+)
+
+// Kitty limits each graphics command's payload to 4096 bytes
+const kittyChunk = 4096
+
+/**
+ * Encodes an image as Kitty graphics commands that transmit it as PNG
+ * and place it at the cursor over cols x rows cells. The cursor is not
+ * moved (C=1) and the terminal is asked not to reply (q=2), since
+ * replies would arrive as keyboard input. Re-sending the same image
+ * id and placement id moves the existing placement instead of adding one.
+ * @param id - Image id
+ * @param img - Image to send
+ * @param cols - Width in cells
+ * @param rows - Height in cells
+ * @return Escape sequences
+ */
+func renderKitty(id uint32, img image.Image, cols, rows int) string {
+	var buf bytes.Buffer
+	png.Encode(&buf, img)
+	data := base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	var out strings.Builder
+	for i := 0; i < len(data); i += kittyChunk {
+		chunk := data[i:min(i+kittyChunk, len(data))]
+		more := 0
+		if i+kittyChunk < len(data) {
+			more = 1
+		}
+
+		if i == 0 {
+			fmt.Fprintf(&out, "\x1b_Ga=T,f=100,q=2,C=1,i=%d,p=1,c=%d,r=%d,m=%d;%s\x1b\\", id, cols, rows, more, chunk)
+		} else {
+			fmt.Fprintf(&out, "\x1b_Gm=%d;%s\x1b\\", more, chunk)
+		}
+	}
+	return out.String()
+}
+
+/**
+ * Removes every Kitty image placement on screen. Text does not cover
+ * Kitty images, so callers clear before redrawing a scrolled view.
+ * frame must differ whenever the view changes: a line-diffing renderer
+ * would otherwise skip re-sending an identical line.
+ * @param frame - Any number identifying the current view
+ * @return Escape sequences
+ */
+func ClearKitty(frame uint32) string {
+	// Deleting a nonexistent image id is a no-op that makes the line unique
+	return fmt.Sprintf("\x1b_Ga=d,d=a,q=2\x1b\\\x1b_Ga=d,d=i,i=%d,q=2\x1b\\", frame&^(1<<31))
+}
+
+/**
+ * Encodes an image as DEC sixel graphics using a 6x6x6 colour cube,
+ * wrapped in cursor save/restore since terminals move the cursor
+ * below a sixel image.
+ * @param img - Image to draw, at its final pixel size
+ * @return Escape sequences
+ */
+func renderSixel(img *image.RGBA) string {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	// Quantize every pixel to the cube once
+	idx := make([]int, w*h)
+	used := make(map[int]bool)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := img.RGBAAt(x, y)
+			i := cubeLevel(c.R)*36 + cubeLevel(c.G)*6 + cubeLevel(c.B)
+			idx[y*w+x] = i
+			used[i] = true
+		}
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "\x1b7\x1bP0;1;0q\"1;1;%d;%d", w, h)
+	for i := range 216 {
+		if used[i] {
+			// Palette entries are RGB percentages
+			fmt.Fprintf(&out, "#%d;2;%d;%d;%d", i, i/36*20, i/6%6*20, i%6*20)
+		}
+	}
+
+	for y0 := 0; y0 < h; y0 += 6 {
+		// Colours present in this band of six rows
+		var colours []int
+		seen := make(map[int]bool)
+		for y := y0; y < min(y0+6, h); y++ {
+			for x := 0; x < w; x++ {
+				if i := idx[y*w+x]; !seen[i] {
+					seen[i] = true
+					colours = append(colours, i)
+				}
+			}
+		}
+
+		for n, c := range colours {
+			if n > 0 {
+				out.WriteByte('$') // Back to the start of the band
+			}
+			fmt.Fprintf(&out, "#%d", c)
+
+			// Run-length encode the sixel characters
+			var prev byte
+			run := 0
+			flush := func() {
+				switch {
+				case run > 3:
+					fmt.Fprintf(&out, "!%d%c", run, prev)
+				case run > 0:
+					out.WriteString(strings.Repeat(string(prev), run))
+				}
+			}
+			for x := 0; x < w; x++ {
+				var bits byte
+				for k := 0; k < 6 && y0+k < h; k++ {
+					if idx[(y0+k)*w+x] == c {
+						bits |= 1 << k
+					}
+				}
+				ch := 63 + bits
+				if ch == prev {
+					run++
+					continue
+				}
+				flush()
+				prev, run = ch, 1
+			}
+			flush()
+		}
+		out.WriteByte('-') // Next band
+	}
+
+	out.WriteString("\x1b\\\x1b8")
+	return out.String()
+}
+
+/**
+ * Maps a 0-255 channel to one of six cube levels.
+ */
+func cubeLevel(v uint8) int {
+	return (int(v)*5 + 127) / 255
+}
@@ -0,0 +1,62 @@
+package termimg
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+/**
+ * Resizes an image by averaging the source pixels under each target
+ * pixel (nearest pixel when enlarging), blending transparency onto bg.
+ * @param img - Source image
+ * @param w - Target width in pixels
+ * @param h - Target height in pixels
+ * @param bg - Background for transparent pixels
+ * @return Opaque image of exactly w x h pixels
+ */
+func scale(img image.Image, w, h int, bg color.Color) *image.RGBA {
+	b := img.Bounds()
+	src := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(src, src.Bounds(), img, b.Min, draw.Src)
+
+	br, bgG, bb, _ := bg.RGBA()
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	sw, sh := b.Dx(), b.Dy()
+
+	for y := 0; y < h; y++ {
+		y0 := y * sh / h
+		y1 := max((y+1)*sh/h, y0+1)
+
+		for x := 0; x < w; x++ {
+			x0 := x * sw / w
+			x1 := max((x+1)*sw/w, x0+1)
+
+			// Sum premultiplied channels over the box
+			var r, g, bl, a, n uint32
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					r += uint32(p[0])
+					g += uint32(p[1])
+					bl += uint32(p[2])
+					a += uint32(p[3])
+					n++
+				}
+			}
+			r, g, bl, a = r/n, g/n, bl/n, a/n
+
+			// Composite over the background: c + bg * (1 - alpha)
+			inv := 255 - a
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8(r + (br>>8)*inv/255),
+				G: uint8(g + (bgG>>8)*inv/255),
+				B: uint8(bl + (bb>>8)*inv/255),
+				A: 255,
+			})
+		}
+	}
+
+	return dst
+}
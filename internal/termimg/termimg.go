@@ -0,0 +1,217 @@
+package termimg
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"image"
+	"image/color"
+	_ "image/gif" // Register decoders for Decode
+	_ "image/jpeg"
+	_ "image/png"
+	"strings"
+	"sync"
+)
+
+/**
+ * How images are drawn in the terminal.
+ */
+type Protocol int
+
+const (
+	Blocks  Protocol = iota // Unicode half blocks, two pixels per cell; works everywhere
+	Braille                 // Unicode braille, 2x4 dots per cell; one colour per cell
+	Kitty                   // Kitty graphics protocol
+	Sixel                   // DEC sixel graphics
+)
+
+// Limits that keep a hostile or oversized file from exhausting memory
+const (
+	MaxFileSize = 10 << 20 // Bytes
+	maxPixels   = 4096 * 4096
+)
+
+// Pixel size assumed for one cell when drawing real graphics.
+// Kitty scales to the cell box itself; sixel is drawn at this size.
+const (
+	cellWidth  = 8
+	cellHeight = 16
+)
+
+/**
+ * Returns the protocol name, e.g. "kitty".
+ */
+func (p Protocol) String() string {
+	switch p {
+	case Braille:
+		return "braille"
+	case Kitty:
+		return "kitty"
+	case Sixel:
+		return "sixel"
+	}
+	return "blocks"
+}
+
+/**
+ * Reports whether the protocol draws pixels rather than text.
+ * Graphics occupy reserved blank rows and must be redrawn by the caller.
+ */
+func (p Protocol) Graphics() bool {
+	return p == Kitty || p == Sixel
+}
+
+/**
+ * Picks the best protocol for a client from what it tells us about itself.
+ * Over SSH only TERM is reliably forwarded, so detection is by TERM
+ * with a few environment hints for clients that send them.
+ * @param term - TERM from the PTY request
+ * @param environ - Client environment ("KEY=value" entries)
+ * @return Kitty, Sixel, or Blocks when unsure
+ */
+func Detect(term string, environ []string) Protocol {
+	term = strings.ToLower(term)
+	switch {
+	case strings.Contains(term, "kitty"), strings.Contains(term, "ghostty"):
+		return Kitty
+	case strings.Contains(term, "sixel"), strings.HasPrefix(term, "foot"),
+		strings.HasPrefix(term, "mlterm"), strings.HasPrefix(term, "yaft"),
+		strings.HasPrefix(term, "contour"):
+		return Sixel
+	}
+
+	for _, kv := range environ {
+		key, value, _ := strings.Cut(kv, "=")
+		switch {
+		case key == "KITTY_WINDOW_ID", key == "TERM_PROGRAM" && value == "ghostty":
+			return Kitty
+		case key == "TERM_PROGRAM" && value == "WezTerm":
+			return Sixel
+		}
+	}
+	return Blocks
+}
+
+/**
+ * Decodes a PNG, JPEG or GIF image, refusing oversized input.
+ * @param data - Encoded image
+ * @return Decoded image
+ * @return error if the format is unknown, the data is corrupt or too large
+ */
+func Decode(data []byte) (image.Image, error) {
+	if len(data) > MaxFileSize {
+		return nil, fmt.Errorf("image is larger than %d MB", MaxFileSize>>20)
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxPixels {
+		return nil, fmt.Errorf("image is %dx%d; the limit is %d pixels", cfg.Width, cfg.Height, maxPixels)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, err
+}
+
+/**
+ * Rendering parameters.
+ */
+type Options struct {
+	Cols       int         // Maximum width in cells
+	Protocol   Protocol    // Output encoding
+	Background color.Color // Colour transparent pixels are blended onto (nil = black)
+}
+
+/**
+ * Works out the cell size an image is drawn at: as wide as allowed,
+ * but never wider than the image has pixels for.
+ * @param img - Source image
+ * @param opts - Rendering options
+ * @return Width and height in cells (at least 1x1)
+ */
+func Size(img image.Image, opts Options) (cols, rows int) {
+	b := img.Bounds()
+	w, h := max(b.Dx(), 1), max(b.Dy(), 1)
+
+	// Pixels one cell can show across
+	perCell := 1
+	switch opts.Protocol {
+	case Braille:
+		perCell = 2
+	case Kitty, Sixel:
+		perCell = cellWidth
+	}
+	cols = max(min(opts.Cols, (w+perCell-1)/perCell), 1)
+
+	// Cells are twice as tall as they are wide
+	rows = max((h*cols+2*w-1)/(2*w), 1)
+	return cols, rows
+}
+
+// Rendered output keyed by image, size and protocol. Shared by all
+// sessions; cleared wholesale when it grows past maxCacheEntries.
+var cache = struct {
+	sync.Mutex
+	entries map[string]string
+}{entries: make(map[string]string)}
+
+const maxCacheEntries = 256
+
+/**
+ * Renders an image for the terminal. Text protocols return Size() rows
+ * of text; graphics protocols return a single escape sequence that draws
+ * the image at the cursor without moving it, covering Size() rows.
+ * Results are cached per key, width and protocol.
+ * @param key - Stable identifier of the image contents (e.g. a hash)
+ * @param img - Source image
+ * @param opts - Rendering options
+ * @return Rendered image
+ */
+func Render(key string, img image.Image, opts Options) string {
+	cols, rows := Size(img, opts)
+	cacheKey := fmt.Sprintf("%s/%d/%s", key, cols, opts.Protocol)
+
+	cache.Lock()
+	out, ok := cache.entries[cacheKey]
+	cache.Unlock()
+	if ok {
+		return out
+	}
+
+	bg := opts.Background
+	if bg == nil {
+		bg = color.Black
+	}
+
+	switch opts.Protocol {
+	case Braille:
+		out = renderBraille(scale(img, cols*2, rows*4, bg), bg)
+	case Kitty:
+		out = renderKitty(imageID(key), scale(img, cols*cellWidth, rows*cellHeight, bg), cols, rows)
+	case Sixel:
+		out = renderSixel(scale(img, cols*cellWidth, rows*cellHeight, bg))
+	default:
+		out = renderBlocks(scale(img, cols, rows*2, bg))
+	}
+
+	cache.Lock()
+	if len(cache.entries) >= maxCacheEntries {
+		cache.entries = make(map[string]string)
+	}
+	cache.entries[cacheKey] = out
+	cache.Unlock()
+
+	return out
+}
+
+/**
+ * Derives a Kitty image id from the cache key. The high bit is set so
+ * ids never collide with the small numbers used by ClearKitty.
+ */
+func imageID(key string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return h.Sum32() | 1<<31
+}
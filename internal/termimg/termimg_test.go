@@ -0,0 +1,156 @@
+package termimg
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+/**
+ * Builds a w x h image, left half red and right half blue.
+ */
+func testImage(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := color.RGBA{R: 255, A: 255}
+			if x >= w/2 {
+				c = color.RGBA{B: 255, A: 255}
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+/**
+ * Tests protocol detection from TERM and the client environment.
+ */
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		term    string
+		environ []string
+		want    Protocol
+	}{
+		{"xterm-kitty", nil, Kitty},
+		{"xterm-ghostty", nil, Kitty},
+		{"foot", nil, Sixel},
+		{"xterm-256color", []string{"TERM_PROGRAM=WezTerm"}, Sixel},
+		{"xterm-256color", []string{"KITTY_WINDOW_ID=1"}, Kitty},
+		{"xterm-256color", nil, Blocks},
+		{"", nil, Blocks},
+	}
+
+	for _, tt := range tests {
+		if got := Detect(tt.term, tt.environ); got != tt.want {
+			t.Errorf("Detect(%q, %v) = %s, want %s", tt.term, tt.environ, got, tt.want)
+		}
+	}
+}
+
+/**
+ * Tests that images are scaled to the allowed width but never enlarged.
+ */
+func TestSize(t *testing.T) {
+	img := testImage(200, 100)
+
+	tests := []struct {
+		opts       Options
+		cols, rows int
+	}{
+		{Options{Cols: 80, Protocol: Blocks}, 80, 20},
+		{Options{Cols: 300, Protocol: Blocks}, 200, 50},
+		{Options{Cols: 80, Protocol: Braille}, 80, 20},
+		{Options{Cols: 80, Protocol: Kitty}, 25, 7},
+	}
+
+	for _, tt := range tests {
+		cols, rows := Size(img, tt.opts)
+		if cols != tt.cols || rows != tt.rows {
+			t.Errorf("Size(%+v) = %dx%d, want %dx%d", tt.opts, cols, rows, tt.cols, tt.rows)
+		}
+	}
+}
+
+/**
+ * Tests that text protocols produce exactly the rows and columns Size reports.
+ */
+func TestRender_Text(t *testing.T) {
+	img := testImage(40, 40)
+
+	for _, p := range []Protocol{Blocks, Braille} {
+		opts := Options{Cols: 10, Protocol: p}
+		cols, rows := Size(img, opts)
+		out := Render("test-text", img, opts)
+
+		lines := strings.Split(out, "\n")
+		if len(lines) != rows {
+			t.Errorf("%s: expected %d rows, got %d", p, rows, len(lines))
+		}
+		for _, line := range lines {
+			if w := ansi.StringWidth(line); w != cols {
+				t.Errorf("%s: expected width %d, got %d", p, cols, w)
+			}
+		}
+	}
+
+	// Left half is red, right half blue
+	blocks := Render("test-text", img, Options{Cols: 10})
+	if !strings.Contains(blocks, "38;2;255;0;0") || !strings.Contains(blocks, "38;2;0;0;255") {
+		t.Errorf("Expected red and blue half blocks, got %q", blocks)
+	}
+}
+
+/**
+ * Tests the framing of Kitty and sixel output.
+ */
+func TestRender_Graphics(t *testing.T) {
+	img := testImage(64, 64)
+
+	kitty := Render("test-graphics", img, Options{Cols: 8, Protocol: Kitty})
+	if !strings.HasPrefix(kitty, "\x1b_Ga=T,f=100,q=2,C=1,") || !strings.HasSuffix(kitty, "\x1b\\") {
+		t.Errorf("Unexpected kitty framing: %.60q", kitty)
+	}
+	if !strings.Contains(kitty, ",c=8,r=4,") {
+		t.Errorf("Expected placement over 8x4 cells, got %.80q", kitty)
+	}
+
+	sixel := Render("test-graphics", img, Options{Cols: 8, Protocol: Sixel})
+	if !strings.HasPrefix(sixel, "\x1b7\x1bP0;1;0q\"1;1;64;64") || !strings.HasSuffix(sixel, "\x1b\\\x1b8") {
+		t.Errorf("Unexpected sixel framing: %.60q", sixel)
+	}
+	if ansi.StringWidth(sixel) != 0 || ansi.StringWidth(kitty) != 0 {
+		t.Error("Expected graphics sequences to take no columns")
+	}
+}
+
+/**
+ * Tests that oversized and corrupt input is rejected.
+ */
+func TestDecode(t *testing.T) {
+	var buf bytes.Buffer
+	png.Encode(&buf, testImage(4, 2))
+
+	img, err := Decode(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if img.Bounds().Dx() != 4 || img.Bounds().Dy() != 2 {
+		t.Errorf("Expected 4x2 image, got %v", img.Bounds())
+	}
+
+	if _, err := Decode([]byte("not an image")); err == nil {
+		t.Error("Expected error for corrupt data")
+	}
+
+	buf.Reset()
+	png.Encode(&buf, image.NewGray(image.Rect(0, 0, 5000, 5000)))
+	if _, err := Decode(buf.Bytes()); err == nil {
+		t.Error("Expected error for oversized image")
+	}
+}
@@ -0,0 +1,117 @@
+package termimg
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+)
+
+/**
+ * Draws an image with upper half blocks: the foreground colour is the
+ * top pixel of each cell and the background colour the bottom pixel.
+ * @param img - Image exactly cols wide and 2*rows tall
+ * @return One line per cell row, each ending in an SGR reset
+ */
+func renderBlocks(img *image.RGBA) string {
+	b := img.Bounds()
+	var out strings.Builder
+
+	for y := 0; y < b.Dy(); y += 2 {
+		var fg, bg color.RGBA
+		for x := 0; x < b.Dx(); x++ {
+			top := img.RGBAAt(x, y)
+			bottom := img.RGBAAt(x, min(y+1, b.Dy()-1))
+
+			// Only emit colours when they change from the previous cell
+			if x == 0 || top != fg {
+				fmt.Fprintf(&out, "\x1b[38;2;%d;%d;%dm", top.R, top.G, top.B)
+				fg = top
+			}
+			if x == 0 || bottom != bg {
+				fmt.Fprintf(&out, "\x1b[48;2;%d;%d;%dm", bottom.R, bottom.G, bottom.B)
+				bg = bottom
+			}
+			out.WriteRune('▀')
+		}
+		out.WriteString("\x1b[0m")
+		if y+2 < b.Dy() {
+			out.WriteByte('\n')
+		}
+	}
+
+	return out.String()
+}
+
+// Bit for each dot of a braille cell, indexed [y][x]
+var brailleBits = [4][2]rune{
+	{0x01, 0x08},
+	{0x02, 0x10},
+	{0x04, 0x20},
+	{0x40, 0x80},
+}
+
+/**
+ * Draws an image with braille dots. A dot is raised where the pixel
+ * stands out from the background more than the image does on average;
+ * each cell takes the mean colour of its raised dots.
+ * @param img - Image exactly 2*cols wide and 4*rows tall
+ * @param bg - Background the image was blended onto
+ * @return One line per cell row, each ending in an SGR reset
+ */
+func renderBraille(img *image.RGBA, bg color.Color) string {
+	b := img.Bounds()
+	bgLum := luminance(color.RGBAModel.Convert(bg).(color.RGBA))
+
+	// Threshold: mean distance from the background
+	var total float64
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			total += abs(luminance(img.RGBAAt(x, y)) - bgLum)
+		}
+	}
+	threshold := total / float64(b.Dx()*b.Dy())
+
+	var out strings.Builder
+	for cy := 0; cy < b.Dy(); cy += 4 {
+		for cx := 0; cx < b.Dx(); cx += 2 {
+			var bits rune
+			var r, g, bl, n int
+			for dy := 0; dy < 4; dy++ {
+				for dx := 0; dx < 2; dx++ {
+					p := img.RGBAAt(cx+dx, cy+dy)
+					if abs(luminance(p)-bgLum) > threshold {
+						bits |= brailleBits[dy][dx]
+						r, g, bl, n = r+int(p.R), g+int(p.G), bl+int(p.B), n+1
+					}
+				}
+			}
+
+			if n == 0 {
+				out.WriteByte(' ')
+				continue
+			}
+			fmt.Fprintf(&out, "\x1b[38;2;%d;%d;%dm%c", r/n, g/n, bl/n, 0x2800+bits)
+		}
+		out.WriteString("\x1b[0m")
+		if cy+4 < b.Dy() {
+			out.WriteByte('\n')
+		}
+	}
+
+	return out.String()
+}
+
+/**
+ * Perceived brightness of a colour, 0-255.
+ */
+func luminance(c color.RGBA) float64 {
+	return 0.2126*float64(c.R) + 0.7152*float64(c.G) + 0.0722*float64(c.B)
+}
+
+func abs(f float64) float64 {
+	if f < 0 {
+		return -f
+	}
+	return f
+}
@@ -0,0 +1,202 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Number of posts shown per page of the blog list
+const postsPerPage = 5
+
+// Lines renderPost shows above a post's content
+const postHeaderLines = 2
+
+/**
+ * A dated blog post.
+ */
+type Post struct {
+	Slug        string
+	Title       string
+	Date        time.Time
+	Tags        []string
+	Summary     string
+	ReadingTime time.Duration
+	Markdown    string            // Source after template expansion, for non-TUI outputs
+	Source      string            // Markdown before template expansion; used to detect changes
+	Content     string            // Rendered ANSI content for the detail view
+	File        string            // Content file the post was built from, for internal links
+	Headings    []Heading         // Headings in Content, for the outline and { and }
+	Links       []Link            // Links in Content, in order, for keyboard focus
+	Images      map[string]Image  // Images in Content, keyed by placeholder line
+	QRCodes     map[string]QRCode // QR codes in Content, keyed by placeholder line
+}
+
+/**
+ * Reports whether the active tab is the blog.
+ */
+func (m Model) onBlogTab() bool {
+	return m.activeTab >= 0 && m.activeTab < len(m.tabs) && len(m.tabs[m.activeTab].Posts) > 0
+}
+
+/**
+ * Collects the distinct tags across all posts, sorted.
+ */
+func postTags(posts []Post) []string {
+	lists := make([][]string, len(posts))
+	for i, p := range posts {
+		lists[i] = p.Tags
+	}
+	return uniqueTags(lists)
+}
+
+/**
+ * Returns the tag currently used as blog filter, or "" for all.
+ */
+func (m Model) currentBlogTag() string {
+	tags := postTags(m.tabs[m.activeTab].Posts)
+	if m.blogTag <= 0 || m.blogTag > len(tags) {
+		return ""
+	}
+	return tags[m.blogTag-1]
+}
+
+/**
+ * Returns the posts matching the current tag filter, newest first.
+ */
+func (m Model) visiblePosts() []Post {
+	tag := m.currentBlogTag()
+
+	var out []Post
+	for _, p := range m.tabs[m.activeTab].Posts {
+		if tag == "" || containsTag(p.Tags, tag) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+/**
+ * Handles actions in the blog list: moving the selection, paging,
+ * filtering by tag and opening a post. Inside a post, keys scroll
+ * and back returns to the list.
+ * @param action - Action from the keymap
+ * @param count - Count typed before it (0 = none)
+ * @return true if the action was consumed
+ */
+func (m *Model) updateBlog(action keyAction, count int) bool {
+	if m.blogOpen {
+		return false
+	}
+
+	posts := m.visiblePosts()
+	times := max(count, 1)
+	switch action {
+	case actionDown:
+		m.blogCursor = max(min(m.blogCursor+times, len(posts)-1), 0)
+	case actionUp:
+		m.blogCursor = max(m.blogCursor-times, 0)
+	case actionNextPage, actionPageDown:
+		m.blogCursor = max(min((m.blogCursor/postsPerPage+times)*postsPerPage, len(posts)-1), 0)
+	case actionPrevPage, actionPageUp:
+		m.blogCursor = max((m.blogCursor/postsPerPage-times)*postsPerPage, 0)
+	case actionFilterNext, actionFilterPrev:
+		count := len(postTags(m.tabs[m.activeTab].Posts)) + 1
+		delta := 1
+		if action == actionFilterPrev {
+			delta = -1
+		}
+		m.blogTag = ((m.blogTag+delta)%count + count) % count
+		m.blogCursor = 0
+	case actionOpen:
+		if len(posts) == 0 {
+			return true
+		}
+		m.blogOpen = true
+	default:
+		return false
+	}
+
+	m.updateViewportContent()
+	return true
+}
+
+/**
+ * Renders the blog tab: either the paginated list or the open post.
+ * @return Styled blog content
+ */
+func (m Model) renderBlog() string {
+	if post, ok := m.openPost(); ok {
+		return m.renderPost(post)
+	}
+
+	posts := m.visiblePosts()
+	pages := max((len(posts)+postsPerPage-1)/postsPerPage, 1)
+	page := m.blogCursor / postsPerPage
+
+	filter := "all"
+	if tag := m.currentBlogTag(); tag != "" {
+		filter = tag
+	}
+
+	var b strings.Builder
+	b.WriteString(m.styles.projectHeader.Render(fmt.Sprintf("Blog (%d)  •  page %d/%d  •  tag: %s", len(posts), page+1, pages, filter)))
+	b.WriteString("\n\n")
+
+	end := min((page+1)*postsPerPage, len(posts))
+	for i := page * postsPerPage; i < end; i++ {
+		p := posts[i]
+
+		marker, style := "  ", m.styles.postTitle
+		if i == m.blogCursor {
+			marker, style = "▸ ", m.styles.postSelected
+		}
+
+		b.WriteString(style.Render(marker + p.Title))
+		b.WriteString("\n")
+		b.WriteString(m.styles.projectMeta.Render("  " + postMeta(p)))
+		b.WriteString("\n")
+		if p.Summary != "" {
+			b.WriteString(m.styles.postSummary.Width(m.viewport.Width - 2).Render(p.Summary))
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+/**
+ * Renders a single post with a metadata header.
+ */
+func (m Model) renderPost(p Post) string {
+	var b strings.Builder
+	b.WriteString(m.styles.postSelected.Render("  " + p.Title))
+	b.WriteString("\n")
+	b.WriteString(m.styles.projectMeta.Render("  " + postMeta(p)))
+	b.WriteString("\n")
+	b.WriteString(p.Content)
+	return b.String()
+}
+
+/**
+ * Returns the post being read, if the detail view is open.
+ */
+func (m Model) openPost() (Post, bool) {
+	posts := m.visiblePosts()
+	if m.blogOpen && m.blogCursor < len(posts) {
+		return posts[m.blogCursor], true
+	}
+	return Post{}, false
+}
+
+/**
+ * Formats the date, reading time and tags line of a post.
+ */
+func postMeta(p Post) string {
+	parts := []string{p.Date.Format("Jan 2, 2006"), fmt.Sprintf("%d min read", int(p.ReadingTime.Minutes()))}
+	if len(p.Tags) > 0 {
+		parts = append(parts, "#"+strings.Join(p.Tags, " #"))
+	}
+	return strings.Join(parts, " • ")
+}
@@ -0,0 +1,86 @@
+package tui
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+/**
+ * Builds a blog tab with n posts, every other one tagged "go".
+ */
+func testBlogModel(n int) Model {
+	var posts []Post
+	for i := 0; i < n; i++ {
+		p := Post{Title: fmt.Sprintf("Post %d", i), Date: time.Date(2026, 1, n-i, 0, 0, 0, 0, time.UTC)}
+		if i%2 == 0 {
+			p.Tags = []string{"go"}
+		}
+		posts = append(posts, p)
+	}
+
+	m := NewModel([]Tab{{Name: "Blog", Posts: posts}}, "test")
+	m.showSplash = false
+	return m
+}
+
+/**
+ * Sends a sequence of keys to the model.
+ */
+func pressKeys(m Model, keys ...tea.KeyMsg) Model {
+	for _, k := range keys {
+		updated, _ := m.Update(k)
+		m = updated.(Model)
+	}
+	return m
+}
+
+var (
+	keyJ     = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}}
+	keyNext  = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{']'}}
+	keyF     = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}}
+	keyEnter = tea.KeyMsg{Type: tea.KeyEnter}
+	keyEsc   = tea.KeyMsg{Type: tea.KeyEsc}
+)
+
+/**
+ * Tests selection, pagination and opening a post.
+ */
+func TestUpdate_BlogNavigation(t *testing.T) {
+	m := pressKeys(testBlogModel(7), keyJ, keyNext, keyNext)
+	if m.blogCursor != postsPerPage {
+		t.Errorf("Expected cursor on first post of page 2, got %d", m.blogCursor)
+	}
+
+	// Paging past the end stays on the last post
+	m = pressKeys(m, keyNext, keyNext, keyNext, keyNext)
+	if m.blogCursor != 6 {
+		t.Errorf("Expected cursor on last post, got %d", m.blogCursor)
+	}
+
+	m = pressKeys(m, keyEnter)
+	if !m.blogOpen {
+		t.Fatal("Expected post to open")
+	}
+
+	m = pressKeys(m, keyEsc)
+	if m.blogOpen {
+		t.Error("Expected esc to return to the list")
+	}
+}
+
+/**
+ * Tests tag filtering resets the selection.
+ */
+func TestUpdate_BlogTagFilter(t *testing.T) {
+	m := pressKeys(testBlogModel(4), keyJ, keyF)
+
+	if m.currentBlogTag() != "go" {
+		t.Fatalf("Expected tag filter 'go', got %q", m.currentBlogTag())
+	}
+	if len(m.visiblePosts()) != 2 || m.blogCursor != 0 {
+		t.Errorf("Expected 2 posts with cursor reset, got %d (cursor %d)", len(m.visiblePosts()), m.blogCursor)
+	}
+}
@@ -0,0 +1,147 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+/**
+ * What changed on a tab since the visitor's previous session.
+ */
+type TabChange struct {
+	Tab     string   // Tab name
+	New     bool     // Tab did not exist last time
+	Added   []string // Headings of sections that are new
+	Removed []string // Headings of sections that are gone
+	Updated []string // Headings of sections whose text changed
+
+	Details map[string][]DiffLine // Lines added and removed, by section heading
+}
+
+/**
+ * One line added to or removed from a section.
+ */
+type DiffLine struct {
+	Op   byte   // '+' for added, '-' for removed
+	Text string // Markdown line
+}
+
+// Diff lines shown per section in "What's new"; the rest are counted
+const maxDiffLines = 6
+
+/**
+ * Records what changed since the visitor's previous session.
+ * Changed tabs get a badge until they are opened, and the
+ * "What's new" view lists the sections involved.
+ * @param changes - Changed tabs in display order (empty for none)
+ * @param since - Start of the previous session
+ */
+func (m *Model) SetChanges(changes []TabChange, since time.Time) {
+	m.changes = changes
+	m.changesSince = since
+	m.seenTabs = make(map[string]bool)
+	if m.activeTab < len(m.tabs) {
+		m.seenTabs[m.tabs[m.activeTab].Name] = true
+	}
+}
+
+/**
+ * Reports whether a tab should carry the "changed" badge.
+ */
+func (m Model) tabChanged(name string) bool {
+	if m.seenTabs[name] {
+		return false
+	}
+	for _, c := range m.changes {
+		if c.Tab == name {
+			return true
+		}
+	}
+	return false
+}
+
+/**
+ * Opens or closes the "What's new" view.
+ * @effects Closes any other overlay; does nothing without changes
+ */
+func (m *Model) toggleWhatsNew() {
+	if len(m.changes) == 0 {
+		return
+	}
+	open := !m.whatsNew
+	m.closeOverlays()
+	m.whatsNew = open
+	m.updateViewportContent()
+}
+
+/**
+ * Renders the list of changes since the previous session.
+ * @return Styled summary, one block per changed tab
+ */
+func (m Model) renderWhatsNew() string {
+	var b strings.Builder
+
+	title := "What's new since your last visit"
+	if !m.changesSince.IsZero() {
+		title += " on " + m.changesSince.Format("Jan 2, 2006")
+	}
+	b.WriteString(m.styles.projectTitle.Render(title) + "\n\n")
+
+	for _, c := range m.changes {
+		b.WriteString(m.styles.postTitle.Render(c.Tab))
+		if c.New {
+			b.WriteString(" " + m.styles.changeAdded.Render("(new tab)"))
+		}
+		b.WriteString("\n")
+
+		for _, h := range c.Added {
+			b.WriteString(m.styles.changeAdded.Render("  + "+sectionName(h, c.Tab)) + "\n")
+			b.WriteString(m.renderDiff(c.Details[h]))
+		}
+		for _, h := range c.Updated {
+			b.WriteString(m.styles.changeUpdated.Render("  ~ "+sectionName(h, c.Tab)) + "\n")
+			b.WriteString(m.renderDiff(c.Details[h]))
+		}
+		for _, h := range c.Removed {
+			b.WriteString(m.styles.changeRemoved.Render("  - "+sectionName(h, c.Tab)) + "\n")
+			b.WriteString(m.renderDiff(c.Details[h]))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString(m.styles.projectMeta.Render(fmt.Sprintf("%d tab(s) changed • %s to close", len(m.changes), m.keyChoice(actionWhatsNew, actionBack))))
+	return b.String()
+}
+
+/**
+ * Renders the lines added to and removed from a section, indented
+ * under its heading, up to maxDiffLines.
+ * @param lines - Changed lines (nil when the old text is unknown)
+ * @return Styled lines, each ending in a newline
+ */
+func (m Model) renderDiff(lines []DiffLine) string {
+	var b strings.Builder
+	for i, l := range lines {
+		if i == maxDiffLines {
+			b.WriteString(m.styles.projectMeta.Render(fmt.Sprintf("      … %d more line(s)", len(lines)-i)) + "\n")
+			break
+		}
+		style := m.styles.changeAdded
+		if l.Op == '-' {
+			style = m.styles.changeRemoved
+		}
+		b.WriteString(style.Render("      "+string(l.Op)+" "+l.Text) + "\n")
+	}
+	return b.String()
+}
+
+/**
+ * Labels a section; text before the first heading is named after the tab.
+ */
+func sectionName(heading, tab string) string {
+	if heading == "" {
+		return tab + " introduction"
+	}
+	return heading
+}
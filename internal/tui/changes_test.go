@@ -0,0 +1,64 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+/**
+ * Tests change badges and the "What's new" view.
+ */
+func TestUpdate_WhatsNew(t *testing.T) {
+	m := NewModel([]Tab{{Name: "Welcome"}, {Name: "About"}, {Name: "Future"}}, "test")
+	m.showSplash = false
+	m.SetSize(120, 40)
+	m.SetChanges([]TabChange{
+		{Tab: "Welcome", Updated: []string{"Hello"}},
+		{Tab: "About", Added: []string{"Experience"}, Removed: []string{"Old job"}, Details: map[string][]DiffLine{
+			"Experience": {{Op: '+', Text: "Built things"}},
+			"Old job":    {{Op: '-', Text: "Made coffee"}},
+		}},
+	}, time.Date(2026, 2, 14, 0, 0, 0, 0, time.UTC))
+
+	// The active tab counts as seen; others keep their badge until opened
+	if m.tabChanged("Welcome") || !m.tabChanged("About") || m.tabChanged("Future") {
+		t.Errorf("Unexpected badges: welcome=%v about=%v future=%v",
+			m.tabChanged("Welcome"), m.tabChanged("About"), m.tabChanged("Future"))
+	}
+
+	keyW := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}}
+	m = pressKeys(m, keyW)
+	if !m.whatsNew {
+		t.Fatal("Expected w to open the what's new view")
+	}
+	view := m.viewport.View()
+	for _, want := range []string{"Feb 14, 2026", "+ Experience", "- Old job", "~ Hello", "+ Built things", "- Made coffee"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected what's new view to contain %q", want)
+		}
+	}
+
+	// Switching tabs closes the view and clears the badge
+	m = pressKeys(m, tea.KeyMsg{Type: tea.KeyTab})
+	if m.whatsNew {
+		t.Error("Expected tab switch to close the what's new view")
+	}
+	if m.tabChanged("About") {
+		t.Error("Expected badge cleared after opening the tab")
+	}
+}
+
+/**
+ * Tests that w does nothing without changes.
+ */
+func TestUpdate_WhatsNewEmpty(t *testing.T) {
+	m := NewModel([]Tab{{Name: "Welcome"}}, "test")
+	m.showSplash = false
+	m = pressKeys(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'w'}})
+	if m.whatsNew {
+		t.Error("Expected no what's new view without changes")
+	}
+}
@@ -0,0 +1,186 @@
+package tui
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Most text sent in one clipboard request; terminals drop larger ones
+const maxClipboard = 64 << 10
+
+// How long the confirmation of a copy stays up
+const toastDuration = 2 * time.Second
+
+// What ":copy" can copy besides the page
+var copyTargets = []string{"email", "phone", "url"}
+
+func init() {
+	registerCommand(command{
+		name:  "copy",
+		usage: "<email|phone|url|page>",
+		help:  "Copy contact details or the page text to your clipboard",
+		complete: func(Model) []string {
+			return append(append([]string(nil), copyTargets...), "page")
+		},
+		run: func(m *Model, arg string) (string, tea.Cmd, error) {
+			text, err := m.copyText(arg)
+			if err != nil {
+				return "", nil, err
+			}
+			what := text
+			if arg == "page" {
+				what = "the page"
+			}
+			if len(text) > maxClipboard {
+				return "", nil, fmt.Errorf("%s is too long to copy", what)
+			}
+			return "", m.copyToClipboard(text, what), nil
+		},
+	})
+}
+
+/**
+ * Message sent when a toast has been up for toastDuration.
+ */
+type toastTimeoutMsg struct{ seq int }
+
+/**
+ * Sends text to the visitor's clipboard with OSC 52 on the next frame
+ * and confirms it with a toast.
+ * @param text - Text to copy
+ * @param what - What was copied, for the toast and errors
+ * @return Command that hides the toast
+ * @effects Shows an error in the status area if the text is too long
+ */
+func (m *Model) copyToClipboard(text, what string) tea.Cmd {
+	if len(text) > maxClipboard {
+		m.message, m.messageErr = what+" is too long to copy", true
+		return nil
+	}
+	m.clipboard = ansi.SetSystemClipboard(text)
+	return m.showToast("Copied " + what)
+}
+
+/**
+ * Shows a short confirmation above the stats bar for toastDuration.
+ * @return Command that hides it
+ */
+func (m *Model) showToast(text string) tea.Cmd {
+	m.toastSeq++
+	m.toast = text
+	seq := m.toastSeq
+	return tea.Tick(toastDuration, func(time.Time) tea.Msg {
+		return toastTimeoutMsg{seq: seq}
+	})
+}
+
+/**
+ * Renders the toast right-aligned on the line above the stats bar.
+ * @return Line, or "" when no toast is up
+ */
+func (m Model) renderToast() string {
+	if m.toast == "" {
+		return ""
+	}
+	toast := m.styles.toast.Render("✓ " + ansi.Truncate(m.toast, max(m.width-8, 10), "…"))
+	return strings.Repeat(" ", max(m.width-ansi.StringWidth(toast)-1, 0)) + toast
+}
+
+/**
+ * Finds the text for a ":copy" target. Contact details come from the
+ * current tab's contact card and QR codes, then from any other tab's.
+ * @param target - "email", "phone", "url" or "page"
+ * @return Text to copy
+ * @return error if the target is unknown or not found
+ */
+func (m Model) copyText(target string) (string, error) {
+	switch target {
+	case "":
+		return "", fmt.Errorf("usage: :copy <email|phone|url|page>")
+	case "page":
+		return plainText(m.content), nil
+	}
+	if !slices.Contains(copyTargets, target) {
+		return "", fmt.Errorf("cannot copy %s", target)
+	}
+
+	order := make([]int, 0, len(m.tabs))
+	if m.activeTab >= 0 && m.activeTab < len(m.tabs) {
+		order = append(order, m.activeTab)
+	}
+	for i := range m.tabs {
+		if i != m.activeTab {
+			order = append(order, i)
+		}
+	}
+
+	for _, i := range order {
+		codes := make([]QRCode, 0, len(m.tabs[i].QRCodes)+1)
+		if c := m.tabs[i].Contact; c != nil {
+			codes = append(codes, *c)
+		}
+		for _, c := range m.tabs[i].QRCodes {
+			codes = append(codes, c)
+		}
+		for _, c := range codes {
+			for _, line := range strings.Split(c.Text, "\n") {
+				if kind, value := contactKind(strings.TrimSpace(line)); kind == target {
+					return value, nil
+				}
+			}
+		}
+	}
+	return "", fmt.Errorf("no %s to copy", target)
+}
+
+/**
+ * Recognises a contact detail in a line of a QR code's text.
+ * @return "email", "phone" or "url" and the value, or "" if it is none
+ */
+func contactKind(s string) (string, string) {
+	if v, ok := strings.CutPrefix(s, "mailto:"); ok {
+		return "email", v
+	}
+	if v, ok := strings.CutPrefix(s, "tel:"); ok {
+		return "phone", v
+	}
+	if addr, err := mail.ParseAddress(s); err == nil && addr.Address == s {
+		return "email", s
+	}
+	if u, err := url.Parse(s); err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" {
+		return "url", s
+	}
+
+	digits := 0
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			digits++
+		case strings.ContainsRune("+-(). ", r):
+		default:
+			return "", ""
+		}
+	}
+	if digits >= 7 {
+		return "phone", s
+	}
+	return "", ""
+}
+
+/**
+ * Strips styling and trailing padding from rendered content.
+ */
+func plainText(content string) string {
+	lines := strings.Split(ansi.Strip(content), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n") + "\n"
+}
@@ -0,0 +1,427 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Commands (and searches) kept in a session's history
+const maxCommandHistory = 50
+
+/**
+ * A ":" command. Files register the commands for their own features
+ * with registerCommand.
+ */
+type command struct {
+	name     string
+	aliases  []string
+	usage    string                 // Argument placeholder for help, e.g. "<tab>"
+	help     string                 // One-line description
+	complete func(m Model) []string // Arguments offered for completion (nil = none)
+
+	// Runs the command with the rest of the line as its argument.
+	// Returns a message for the status area and an optional command.
+	run func(m *Model, arg string) (string, tea.Cmd, error)
+}
+
+// Registered commands, in registration order
+var commands []command
+
+/**
+ * Adds a ":" command. Must be called from an init function.
+ * @param c - Command; its name and aliases must be unique
+ */
+func registerCommand(c command) {
+	commands = append(commands, c)
+}
+
+/**
+ * Finds a command by name, alias or unambiguous prefix of its name.
+ * @return Command, and an error if there is no single match
+ */
+func findCommand(name string) (command, error) {
+	var matches []command
+	for _, c := range commands {
+		if c.name == name || slices.Contains(c.aliases, name) {
+			return c, nil
+		}
+		if strings.HasPrefix(c.name, name) {
+			matches = append(matches, c)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return command{}, fmt.Errorf("unknown command: %s", name)
+	case 1:
+		return matches[0], nil
+	}
+	return command{}, fmt.Errorf("ambiguous command: %s", name)
+}
+
+/**
+ * A boolean setting changed with ":set name", ":set noname",
+ * ":set name!" and read with ":set name?".
+ */
+type option struct {
+	name string
+	help string
+	get  func(m Model) bool
+	set  func(m *Model, on bool)
+	cmd  func(m Model) tea.Cmd // Run after a change, e.g. to tell the terminal (optional)
+}
+
+// Registered options, in registration order
+var options []option
+
+/**
+ * Adds an option for ":set". Must be called from an init function.
+ */
+func registerOption(o option) {
+	options = append(options, o)
+}
+
+func init() {
+	registerCommand(command{
+		name:    "quit",
+		aliases: []string{"q", "q!", "qa", "x"},
+		help:    "Leave the portfolio",
+		run: func(m *Model, _ string) (string, tea.Cmd, error) {
+			return "", tea.Quit, nil
+		},
+	})
+
+	registerCommand(command{
+		name:  "help",
+		usage: "[command]",
+		help:  "List commands, or describe one",
+		complete: func(Model) []string {
+			var names []string
+			for _, c := range commands {
+				names = append(names, c.name)
+			}
+			return names
+		},
+		run: func(m *Model, arg string) (string, tea.Cmd, error) {
+			if arg == "" {
+				var names []string
+				for _, c := range commands {
+					names = append(names, c.name)
+				}
+				return "Commands: " + strings.Join(names, ", ") + "  •  Tab completes, ↑/↓ history", nil, nil
+			}
+			c, err := findCommand(arg)
+			if err != nil {
+				return "", nil, err
+			}
+			return strings.TrimSpace(":"+c.name+" "+c.usage) + " — " + c.help, nil, nil
+		},
+	})
+
+	registerCommand(command{
+		name:  "set",
+		usage: "<option>",
+		help:  "Change a setting: name, noname, name! or name?",
+		complete: func(Model) []string {
+			var names []string
+			for _, o := range options {
+				names = append(names, o.name, "no"+o.name)
+			}
+			return names
+		},
+		run: runSet,
+	})
+
+	registerOption(option{
+		name: "wrap",
+		help: "Wrap long lines to the window instead of cutting them off",
+		get:  func(m Model) bool { return m.wrap },
+		set: func(m *Model, on bool) {
+			m.wrap = on
+			m.refreshView()
+		},
+	})
+}
+
+/**
+ * Runs ":set": shows every option without an argument, otherwise
+ * sets, clears, toggles or shows one.
+ */
+func runSet(m *Model, arg string) (string, tea.Cmd, error) {
+	show := func(o option) string {
+		if o.get(*m) {
+			return o.name
+		}
+		return "no" + o.name
+	}
+
+	if arg == "" {
+		var states []string
+		for _, o := range options {
+			states = append(states, show(o))
+		}
+		return strings.Join(states, "  "), nil, nil
+	}
+
+	name, on := arg, true
+	toggle := strings.HasSuffix(name, "!")
+	query := strings.HasSuffix(name, "?")
+	name = strings.TrimRight(name, "!?")
+	if strings.HasPrefix(name, "no") && !toggle && !query {
+		name, on = strings.TrimPrefix(name, "no"), false
+	}
+
+	for _, o := range options {
+		if o.name != name {
+			continue
+		}
+		switch {
+		case query:
+			return show(o), nil, nil
+		case toggle:
+			o.set(m, !o.get(*m))
+		default:
+			o.set(m, on)
+		}
+		var cmd tea.Cmd
+		if o.cmd != nil {
+			cmd = o.cmd(*m)
+		}
+		return show(o), cmd, nil
+	}
+	return "", nil, fmt.Errorf("unknown option: %s", name)
+}
+
+/**
+ * Creates the text input used for ":" commands.
+ */
+func newCmdline() textinput.Model {
+	in := textinput.New()
+	in.Prompt = ""
+	in.ShowSuggestions = true
+	in.CharLimit = 256
+	in.Cursor.SetMode(cursor.CursorStatic) // No blink timer over SSH
+	// Up and down walk the history; completions cycle with ctrl+n/ctrl+p
+	in.KeyMap.NextSuggestion = key.NewBinding(key.WithKeys("ctrl+n"))
+	in.KeyMap.PrevSuggestion = key.NewBinding(key.WithKeys("ctrl+p"))
+	return in
+}
+
+/**
+ * What the line at the bottom is asking for.
+ */
+type prompt int
+
+const (
+	promptCommand    prompt = iota // ":" command
+	promptSearch                   // "/" search down the page
+	promptSearchBack               // "?" search up the page
+	promptSearchAll                // Search every tab
+)
+
+/**
+ * Text shown before what the visitor types.
+ */
+func (p prompt) String() string {
+	switch p {
+	case promptSearch:
+		return "/"
+	case promptSearchBack:
+		return "?"
+	case promptSearchAll:
+		return "Search all tabs: "
+	}
+	return ":"
+}
+
+/**
+ * Opens the command line with completions for the current view.
+ * @return Command from focusing the input
+ */
+func (m *Model) openCmdline() tea.Cmd {
+	return m.openPrompt(promptCommand)
+}
+
+/**
+ * Opens the line at the bottom for a command or a search.
+ * @param p - What the line is for
+ * @return Command from focusing the input
+ */
+func (m *Model) openPrompt(p prompt) tea.Cmd {
+	m.keys.reset()
+	m.cmdlineOpen = true
+	m.cmdPrompt = p
+	m.cmdHistoryPos = len(*m.promptHistory())
+	m.cmdline.SetValue("")
+	m.cmdline.Width = max(m.width-3-len(p.String()), 1) // Stats bar padding, prompt and the cursor
+	m.cmdline.SetSuggestions(nil)
+	if p == promptCommand {
+		m.cmdline.SetSuggestions(m.commandSuggestions())
+	}
+	return m.cmdline.Focus()
+}
+
+/**
+ * Returns the history of the open prompt: commands or searches.
+ */
+func (m *Model) promptHistory() *[]string {
+	if m.cmdPrompt == promptCommand {
+		return &m.cmdHistory
+	}
+	return &m.searchHistory
+}
+
+/**
+ * Lists every command line the completion can offer: each command
+ * name, and each name followed by each of its arguments.
+ */
+func (m Model) commandSuggestions() []string {
+	var out []string
+	for _, c := range commands {
+		out = append(out, c.name)
+		if c.complete == nil {
+			continue
+		}
+		args := c.complete(m)
+		sort.Strings(args)
+		for _, a := range args {
+			out = append(out, c.name+" "+a)
+		}
+	}
+	return out
+}
+
+/**
+ * Handles keys while the command line is open.
+ * @param msg - Key press
+ * @return Command from the text input or the command run
+ * @effects Edits, runs or closes the command line
+ */
+func (m *Model) updateCmdline(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		m.closeCmdline()
+		return nil
+
+	case "backspace":
+		if m.cmdline.Value() == "" {
+			m.closeCmdline()
+			return nil
+		}
+
+	case "enter":
+		line := m.cmdline.Value()
+		if m.cmdPrompt == promptCommand {
+			line = strings.TrimSpace(line)
+		}
+		m.closeCmdline()
+		if line == "" && m.cmdPrompt != promptCommand {
+			line = m.search // An empty search repeats the last one
+		}
+		if line == "" {
+			return nil
+		}
+		history := m.promptHistory()
+		if len(*history) == 0 || (*history)[len(*history)-1] != line {
+			*history = append(*history, line)
+			if len(*history) > maxCommandHistory {
+				*history = (*history)[1:]
+			}
+		}
+		switch m.cmdPrompt {
+		case promptSearch, promptSearchBack:
+			m.startSearch(line, m.cmdPrompt == promptSearchBack)
+		case promptSearchAll:
+			if err := m.searchAll(line); err != nil {
+				m.message, m.messageErr = err.Error(), true
+			}
+		default:
+			return m.runCommandLine(line)
+		}
+		return nil
+
+	case "up":
+		history := *m.promptHistory()
+		if m.cmdHistoryPos > 0 {
+			m.cmdHistoryPos--
+			m.cmdline.SetValue(history[m.cmdHistoryPos])
+			m.cmdline.CursorEnd()
+		}
+		return nil
+
+	case "down":
+		history := *m.promptHistory()
+		if m.cmdHistoryPos < len(history) {
+			m.cmdHistoryPos++
+			value := ""
+			if m.cmdHistoryPos < len(history) {
+				value = history[m.cmdHistoryPos]
+			}
+			m.cmdline.SetValue(value)
+			m.cmdline.CursorEnd()
+		}
+		return nil
+	}
+
+	var cmd tea.Cmd
+	m.cmdline, cmd = m.cmdline.Update(msg)
+	return cmd
+}
+
+/**
+ * Closes the command line without running anything.
+ */
+func (m *Model) closeCmdline() {
+	m.cmdlineOpen = false
+	m.cmdline.Blur()
+}
+
+/**
+ * Parses and runs a command line. A bare number goes to that line.
+ * @param line - Text typed after ":"
+ * @return Command from the command run
+ * @effects Shows the command's message or error in the status area
+ */
+func (m *Model) runCommandLine(line string) tea.Cmd {
+	line = strings.TrimPrefix(line, ":")
+	if n, err := strconv.Atoi(line); err == nil {
+		m.gotoLine(n - 1)
+		return nil
+	}
+
+	name, arg, _ := strings.Cut(line, " ")
+	c, err := findCommand(name)
+	if err == nil {
+		var msg string
+		var cmd tea.Cmd
+		msg, cmd, err = c.run(m, strings.TrimSpace(arg))
+		if err == nil {
+			m.message, m.messageErr = msg, false
+			return cmd
+		}
+	}
+	m.message, m.messageErr = err.Error(), true
+	return nil
+}
+
+/**
+ * Renders the command line, or the last command's message, for the
+ * status area.
+ * @return Status text, or "" to show the usual stats
+ */
+func (m Model) renderCmdline() string {
+	switch {
+	case m.cmdlineOpen:
+		return m.styles.commandPrompt.Render(m.cmdPrompt.String()) + m.cmdline.View()
+	case m.message != "" && m.messageErr:
+		return m.styles.commandError.Render("E: " + m.message)
+	}
+	return m.message
+}
@@ -0,0 +1,153 @@
+package tui
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+/**
+ * Types ":", the line and enter.
+ */
+func runCommand(m Model, line string) Model {
+	return pressKeys(m,
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{':'}},
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(line)},
+		keyEnter,
+	)
+}
+
+/**
+ * Tests :tab, :goto, line numbers and errors for unknown commands.
+ */
+func TestCmdline_Navigation(t *testing.T) {
+	m := runCommand(motionModel(), "tab thr")
+	if m.activeTab != 2 {
+		t.Errorf("Expected :tab thr to open Three, got tab %d", m.activeTab)
+	}
+	m = runCommand(m, "tab 1")
+	if m.activeTab != 0 {
+		t.Errorf("Expected :tab 1 to open Long, got tab %d", m.activeTab)
+	}
+
+	m = runCommand(m, "goto section 3")
+	if !strings.Contains(m.viewport.View(), "Section 3") || m.viewport.YOffset == 0 {
+		t.Errorf("Expected :goto to scroll to Section 3, got offset %d", m.viewport.YOffset)
+	}
+
+	m = runCommand(m, "12")
+	if m.viewport.YOffset != 11 {
+		t.Errorf("Expected :12 to go to line 12, got offset %d", m.viewport.YOffset)
+	}
+
+	m = runCommand(m, "frobnicate")
+	if !m.messageErr || !strings.Contains(m.renderStatsBar(), "unknown command: frobnicate") {
+		t.Errorf("Expected an unknown command error, got %q", m.renderStatsBar())
+	}
+	m = typeKeys(m, "j")
+	if m.message != "" {
+		t.Errorf("Expected the error to clear on the next key, got %q", m.message)
+	}
+}
+
+/**
+ * Tests history, escape and completion on the command line.
+ */
+func TestCmdline_HistoryAndCompletion(t *testing.T) {
+	m := runCommand(motionModel(), "tab two")
+	m = runCommand(m, "set wrap")
+
+	m = typeKeys(m, ":")
+	m = pressKeys(m, tea.KeyMsg{Type: tea.KeyUp}, tea.KeyMsg{Type: tea.KeyUp})
+	if m.cmdline.Value() != "tab two" {
+		t.Errorf("Expected up twice to recall \"tab two\", got %q", m.cmdline.Value())
+	}
+	m = pressKeys(m, tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyDown})
+	if m.cmdline.Value() != "" {
+		t.Errorf("Expected down past the newest entry to clear the line, got %q", m.cmdline.Value())
+	}
+
+	m = pressKeys(m, keyEsc)
+	if m.cmdlineOpen {
+		t.Error("Expected esc to close the command line")
+	}
+
+	m = typeKeys(m, ": the")
+	m = pressKeys(m, tea.KeyMsg{Type: tea.KeyTab})
+	if m.cmdline.Value() != "theme" {
+		t.Errorf("Expected tab to complete \"theme\", got %q", m.cmdline.Value())
+	}
+}
+
+/**
+ * Tests :set, :theme and the theme loader.
+ */
+func TestCmdline_Settings(t *testing.T) {
+	m := motionModel()
+	m.tabs[1].Content = strings.Repeat("word ", 40)
+
+	m = runCommand(m, "tab two")
+	if got := strings.Count(m.content, "\n"); got != 0 {
+		t.Fatalf("Expected one long line without wrap, got %d breaks", got)
+	}
+	m = runCommand(m, "set wrap")
+	if !m.wrap || strings.Count(m.content, "\n") == 0 {
+		t.Error("Expected :set wrap to wrap the long line")
+	}
+	m = runCommand(m, "set wrap!")
+	if m.wrap || m.message != "nowrap" {
+		t.Errorf("Expected :set wrap! to toggle wrap off, got %v %q", m.wrap, m.message)
+	}
+
+	var loaded string
+	m.SetThemeLoader(func(style string) ([]Tab, error) {
+		loaded = style
+		return []Tab{{Name: "Two", Content: "light page"}}, nil
+	})
+	m = runCommand(m, "theme light")
+	if m.theme.Name != "light" || loaded != "light" {
+		t.Errorf("Expected the light theme and pages, got %q and %q", m.theme.Name, loaded)
+	}
+	if m.content != "light page" {
+		t.Errorf("Expected re-rendered pages, got %q", m.content)
+	}
+
+	m = runCommand(m, "theme neon")
+	if !m.messageErr || m.theme.Name != "light" {
+		t.Errorf("Expected an error for an unknown theme, got %q", m.message)
+	}
+}
+
+/**
+ * Tests :copy finds contact details and sends them with OSC 52.
+ */
+func TestCmdline_Copy(t *testing.T) {
+	m := NewModel([]Tab{
+		{Name: "About", Content: "about"},
+		{Name: "Contact", Content: "contact", Contact: &QRCode{
+			Text: "Ada Lovelace\nada@example.com\n+44 20 7946 0958\nhttps://example.com",
+		}},
+	}, "test")
+	m.showSplash = false
+	m.SetSize(80, 30)
+
+	for target, want := range map[string]string{
+		"email": "ada@example.com",
+		"phone": "+44 20 7946 0958",
+		"url":   "https://example.com",
+		"page":  "about\n",
+	} {
+		got := runCommand(m, "copy "+target)
+		osc := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(want)) + "\a"
+		if !strings.Contains(got.View(), osc) {
+			t.Errorf("%s: expected OSC 52 for %q in the view", target, want)
+		}
+	}
+
+	m = runCommand(m, "copy fax")
+	if !m.messageErr || m.clipboard != "" {
+		t.Errorf("Expected an error for an unknown target, got %q", m.message)
+	}
+}
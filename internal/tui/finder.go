@@ -0,0 +1,332 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+/**
+ * A place the finder can jump to: a page, or a heading, project or
+ * post on one.
+ */
+type finderEntry struct {
+	kind    string // "page", "heading", "project" or "post"
+	title   string // Heading, project, post or tab name
+	tab     int    // Tab index
+	heading int    // Index in the tab's Headings (-1 = none)
+	post    int    // Index in the tab's Posts (-1 = none)
+}
+
+/**
+ * An entry matching what the visitor typed.
+ */
+type finderHit struct {
+	entry     int   // Index in finderEntries
+	score     int   // Higher ranks first
+	positions []int // Matched runes in the entry's text
+}
+
+/**
+ * Text an entry is matched against: its title, then the tab it is on.
+ */
+func (m Model) finderText(e finderEntry) string {
+	if e.kind == "page" {
+		return e.title
+	}
+	return e.title + " " + m.tabs[e.tab].Name
+}
+
+/**
+ * Lists every page, heading, project and post, in tab order.
+ */
+func (m Model) buildFinderIndex() []finderEntry {
+	var entries []finderEntry
+	for i, tab := range m.tabs {
+		entries = append(entries, finderEntry{kind: "page", title: tab.Name, tab: i, heading: -1, post: -1})
+		for j, h := range tab.Headings {
+			entries = append(entries, finderEntry{kind: "heading", title: h.Title, tab: i, heading: j, post: -1})
+		}
+		for _, p := range tab.Projects {
+			entries = append(entries, finderEntry{kind: "project", title: p.Name, tab: i, heading: -1, post: -1})
+		}
+		for j, p := range tab.Posts {
+			entries = append(entries, finderEntry{kind: "post", title: p.Title, tab: i, heading: -1, post: j})
+		}
+	}
+	return entries
+}
+
+/**
+ * Scores text against a fuzzy pattern. The letters of each
+ * space-separated word of the pattern must appear in order, not
+ * necessarily together, ignoring case; runs of matched letters and
+ * matches at the start of words score higher.
+ * @param text - Entry text
+ * @param pattern - What the visitor typed
+ * @return Score, matched rune positions, and false if it does not match
+ */
+func fuzzyMatch(text, pattern string) (int, []int, bool) {
+	runes := []rune(strings.ToLower(text))
+	orig := []rune(text)
+	if len(runes) != len(orig) {
+		runes = []rune(text) // Lower-casing changed the length; match as is
+	}
+
+	score := 0
+	var positions []int
+	for _, word := range strings.Fields(strings.ToLower(pattern)) {
+		s, pos, ok := fuzzyWord(runes, orig, []rune(word))
+		if !ok {
+			return 0, nil, false
+		}
+		score += s
+		positions = append(positions, pos...)
+	}
+	sort.Ints(positions)
+	return score, positions, true
+}
+
+/**
+ * Finds the best-scoring occurrence of one pattern word, trying each
+ * place its first letter appears.
+ */
+func fuzzyWord(runes, orig, word []rune) (int, []int, bool) {
+	best, found := 0, false
+	var bestPos []int
+	for start := range runes {
+		if runes[start] != word[0] {
+			continue
+		}
+		score, pos, ok := 0, make([]int, 0, len(word)), true
+		i := start
+		for _, r := range word {
+			for i < len(runes) && runes[i] != r {
+				i++
+			}
+			if i == len(runes) {
+				ok = false
+				break
+			}
+			score += 16
+			if i == 0 || !unicode.IsLetter(orig[i-1]) && !unicode.IsDigit(orig[i-1]) ||
+				unicode.IsUpper(orig[i]) && unicode.IsLower(orig[i-1]) {
+				score += 10 // Start of a word
+			}
+			if len(pos) > 0 {
+				if gap := i - pos[len(pos)-1] - 1; gap == 0 {
+					score += 12
+				} else {
+					score -= min(gap, 8)
+				}
+			}
+			pos = append(pos, i)
+			i++
+		}
+		if !ok {
+			break // Later starts cannot match either
+		}
+		if !found || score > best {
+			best, bestPos, found = score, pos, true
+		}
+	}
+	return best, bestPos, found
+}
+
+/**
+ * Ranks the index against the finder's input: best score first, then
+ * shorter text, then index order. An empty input lists everything.
+ */
+func (m *Model) rankFinder() {
+	pattern := m.finder.Value()
+	m.finderHits = m.finderHits[:0]
+	for i, e := range m.finderEntries {
+		score, pos, ok := fuzzyMatch(m.finderText(e), pattern)
+		if ok {
+			m.finderHits = append(m.finderHits, finderHit{entry: i, score: score, positions: pos})
+		}
+	}
+	sort.SliceStable(m.finderHits, func(a, b int) bool {
+		ha, hb := m.finderHits[a], m.finderHits[b]
+		if ha.score != hb.score {
+			return ha.score > hb.score
+		}
+		return len(m.finderText(m.finderEntries[ha.entry])) < len(m.finderText(m.finderEntries[hb.entry]))
+	})
+	m.finderCursor = 0
+}
+
+/**
+ * Opens the finder over the current tab.
+ * @param query - Initial input
+ * @return Command from focusing the input
+ */
+func (m *Model) openFinder(query string) tea.Cmd {
+	m.keys.reset()
+	m.closeOverlays()
+	m.finder = textinput.New()
+	m.finder.Prompt = ""
+	m.finder.CharLimit = 64
+	m.finder.Cursor.SetMode(cursor.CursorStatic)
+	m.finder.Width = max(m.viewport.Width-20, 10) // Room for the "Find:" label and the count
+	m.finder.SetValue(query)
+	m.finderEntries = m.buildFinderIndex()
+	m.rankFinder()
+	m.showFinder = true
+	m.updateViewportContent()
+	return m.finder.Focus()
+}
+
+/**
+ * Handles keys while the finder is open: typing filters, arrows (or
+ * ctrl+n/ctrl+p) select and enter jumps.
+ * @param msg - Key press
+ * @return Command from the text input
+ */
+func (m *Model) updateFinder(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		m.showFinder = false
+		m.updateViewportContent()
+		return nil
+	case "enter":
+		if len(m.finderHits) > 0 {
+			m.jumpToEntry(m.finderEntries[m.finderHits[m.finderCursor].entry])
+		}
+		return nil
+	case "down", "ctrl+n", "ctrl+j", "tab":
+		m.finderCursor = min(m.finderCursor+1, max(len(m.finderHits)-1, 0))
+	case "up", "ctrl+p", "ctrl+k", "shift+tab":
+		m.finderCursor = max(m.finderCursor-1, 0)
+	default:
+		before := m.finder.Value()
+		var cmd tea.Cmd
+		m.finder, cmd = m.finder.Update(msg)
+		if m.finder.Value() != before {
+			m.rankFinder()
+		}
+		m.showFinderSelection()
+		return cmd
+	}
+	m.showFinderSelection()
+	return nil
+}
+
+/**
+ * Redraws the finder, scrolled so the selected entry is in view.
+ */
+func (m *Model) showFinderSelection() {
+	m.updateViewportContent()
+	// One line per entry below the input and a blank line
+	line := 2 + m.finderCursor
+	m.viewport.SetYOffset(max(line+1-m.viewport.Height, 0))
+	m.cursorLine = line
+}
+
+/**
+ * Renders the finder: the input, then the ranked entries.
+ * @return Styled finder for the viewport
+ */
+func (m Model) renderFinder() string {
+	var b strings.Builder
+	count := m.styles.projectMeta.Render(fmt.Sprintf("  %d/%d", len(m.finderHits), len(m.finderEntries)))
+	b.WriteString(m.styles.projectHeader.Render("Find: ") + m.finder.View() + count + "\n\n")
+
+	if len(m.finderHits) == 0 {
+		b.WriteString(m.styles.projectMeta.Render("  Nothing matches") + "\n")
+	}
+	for i, hit := range m.finderHits {
+		e := m.finderEntries[hit.entry]
+		marker, title := "  ", m.styles.postTitle
+		if i == m.finderCursor {
+			marker, title = "▸ ", m.styles.postSelected
+		}
+
+		text := []rune(m.finderText(e))
+		matched := make(map[int]bool, len(hit.positions))
+		for _, p := range hit.positions {
+			matched[p] = true
+		}
+		hitStyle := title.Underline(true)
+		split := len([]rune(e.title))
+
+		b.WriteString(title.Render(marker))
+		b.WriteString(styleRunes(text[:split], matched, 0, title, hitStyle))
+		if split < len(text) {
+			b.WriteString(m.styles.projectMeta.Render("  "))
+			b.WriteString(styleRunes(text[split+1:], matched, split+1, m.styles.projectMeta, m.styles.projectMeta.Underline(true)))
+		}
+		b.WriteString(m.styles.projectMeta.Render("  · "+e.kind) + "\n")
+	}
+	return b.String()
+}
+
+/**
+ * Styles runs of runes, using hit for those at matched positions.
+ * @param offset - Position of runes[0] in the matched text
+ */
+func styleRunes(runes []rune, matched map[int]bool, offset int, base, hit lipgloss.Style) string {
+	var b strings.Builder
+	for i := 0; i < len(runes); {
+		j := i
+		for j < len(runes) && matched[offset+j] == matched[offset+i] {
+			j++
+		}
+		style := base
+		if matched[offset+i] {
+			style = hit
+		}
+		b.WriteString(style.Render(string(runes[i:j])))
+		i = j
+	}
+	return b.String()
+}
+
+/**
+ * Opens the tab (and post) of an entry and moves to it.
+ * @param e - Entry chosen in the finder
+ */
+func (m *Model) jumpToEntry(e finderEntry) {
+	m.closeOverlays()
+	m.activeTab = e.tab
+	m.blogOpen = false
+	if e.post >= 0 {
+		m.blogOpen, m.blogTag, m.blogCursor = true, 0, e.post
+	}
+	if e.kind == "project" {
+		m.projectTag = 0 // The project may be hidden by a tech filter
+	}
+	m.updateViewportContent()
+
+	switch e.kind {
+	case "heading":
+		if line := m.headingRows[e.heading]; line >= 0 {
+			m.gotoLine(line)
+		}
+	case "project":
+		for i, line := range strings.Split(m.content, "\n") {
+			if strings.Contains(ansi.Strip(line), e.title) {
+				m.gotoLine(i - 1) // Keep the card's top border in view
+				break
+			}
+		}
+	}
+}
+
+func init() {
+	registerCommand(command{
+		name:  "find",
+		usage: "[text]",
+		help:  "Find a page, heading, project or post",
+		run: func(m *Model, arg string) (string, tea.Cmd, error) {
+			return "", m.openFinder(arg), nil
+		},
+	})
+}
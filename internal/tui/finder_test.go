@@ -0,0 +1,92 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+/**
+ * Tests fuzzy matching and its ranking of word starts and runs.
+ */
+func TestFuzzyMatch(t *testing.T) {
+	if _, _, ok := fuzzyMatch("About", "xyz"); ok {
+		t.Error("Expected no match")
+	}
+	if _, pos, ok := fuzzyMatch("ssh-portfolio Projects", "spf"); !ok || len(pos) != 3 || pos[0] != 0 {
+		t.Errorf("Expected spf to match ssh-portfolio, got %v", pos)
+	}
+	if _, _, ok := fuzzyMatch("Experience About", "about exp"); !ok {
+		t.Error("Expected words to match in any order")
+	}
+
+	run, _, _ := fuzzyMatch("Experience About", "exp")
+	gap, _, _ := fuzzyMatch("Example Projects", "exp")
+	if run <= gap {
+		t.Errorf("Expected a run of letters to rank higher, got %d <= %d", run, gap)
+	}
+	start, _, _ := fuzzyMatch("Go tools", "to")
+	inner, _, _ := fuzzyMatch("Photos", "to")
+	if start <= inner {
+		t.Errorf("Expected a word start to rank higher, got %d <= %d", start, inner)
+	}
+}
+
+/**
+ * Tests the finder ranks as the visitor types and jumps to headings,
+ * projects and posts.
+ */
+func TestFinder(t *testing.T) {
+	m := motionModel()
+	var projects []Project
+	for _, name := range []string{"Weather station", "Chess engine", "ssh-portfolio", "Tiny compiler"} {
+		projects = append(projects, Project{Name: name, Summary: strings.Repeat("Summary text. ", 20)})
+	}
+	m.tabs = append(m.tabs,
+		Tab{Name: "Projects", Projects: projects},
+		Tab{Name: "Blog", Posts: []Post{{Title: "First post"}, {Title: "Hello SSH"}}},
+	)
+	ctrlP := tea.KeyMsg{Type: tea.KeyCtrlP}
+
+	m = pressKeys(m, ctrlP, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("sshport")})
+	if !m.showFinder || len(m.finderHits) == 0 {
+		t.Fatal("Expected the finder to open with hits")
+	}
+	if e := m.finderEntries[m.finderHits[0].entry]; e.title != "ssh-portfolio" {
+		t.Errorf("Expected ssh-portfolio ranked first, got %q", e.title)
+	}
+	if !strings.Contains(ansi.Strip(m.viewport.View()), "▸ ssh-portfolio  Projects  · project") {
+		t.Errorf("Expected the selected entry, got %q", ansi.Strip(m.viewport.View()))
+	}
+
+	m = pressKeys(m, keyEnter)
+	if m.showFinder || m.activeTab != 3 || m.viewport.YOffset == 0 {
+		t.Fatalf("Expected Projects scrolled to the card, got tab %d offset %d", m.activeTab, m.viewport.YOffset)
+	}
+	if top := strings.SplitN(ansi.Strip(m.viewport.View()), "\n", 4)[:3]; !strings.Contains(strings.Join(top, "\n"), "ssh-portfolio") {
+		t.Errorf("Expected the card at the top, got %q", top)
+	}
+
+	m = pressKeys(m, ctrlP, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("sec 3")}, keyEnter)
+	if m.activeTab != 0 || !strings.HasPrefix(ansi.Strip(m.viewport.View()), "## Section 3") {
+		t.Errorf("Expected Section 3 at the top, got tab %d", m.activeTab)
+	}
+
+	// Arrows move the selection; Esc leaves the tab as it was
+	m = pressKeys(m, ctrlP, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("sec")}, tea.KeyMsg{Type: tea.KeyDown})
+	if m.finderCursor != 1 {
+		t.Errorf("Expected the second hit selected, got %d", m.finderCursor)
+	}
+	m = pressKeys(m, keyEsc)
+	if m.showFinder || m.activeTab != 0 {
+		t.Errorf("Expected Esc to close the finder, got tab %d", m.activeTab)
+	}
+
+	m = runCommand(m, "find hello")
+	m = pressKeys(m, keyEnter)
+	if post, ok := m.openPost(); !ok || post.Title != "Hello SSH" {
+		t.Errorf("Expected :find to open the post, got %q", post.Title)
+	}
+}
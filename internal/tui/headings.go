@@ -0,0 +1,102 @@
+package tui
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+/**
+ * A heading in a tab's markdown.
+ */
+type Heading struct {
+	Level int    // 1 for "#", 2 for "##", ...
+	Title string // Text without markdown
+	Line  int    // Line in the rendered content, counting from 1 (0 = not located)
+}
+
+/**
+ * Finds the rendered line of each heading, for content pipelines to
+ * record after rendering. Headings are matched in order by the start
+ * of their text, so repeated titles resolve to successive lines.
+ * @param content - Rendered content
+ * @param headings - Headings of the markdown it was rendered from
+ * @return Copies of the headings with Line set where found
+ */
+func LocateHeadings(content string, headings []Heading) []Heading {
+	out := make([]Heading, len(headings))
+	rendered := strings.Split(content, "\n")
+	next := 0
+
+	for j, h := range headings {
+		out[j] = h
+		out[j].Line = 0
+		for i := next; i < len(rendered); i++ {
+			text := strings.TrimLeft(strings.TrimSpace(ansi.Strip(rendered[i])), "# ")
+			if text != "" && strings.HasPrefix(text, h.Title) {
+				out[j].Line = i + 1
+				next = i + 1
+				break
+			}
+		}
+	}
+	return out
+}
+
+/**
+ * Where each line of a page's source content starts after a layout
+ * step that may turn one line into several (wrapping, images, QR
+ * codes). nil means every line stays where it was.
+ */
+type lineMap []int
+
+/**
+ * Finds the viewport line of each heading of a page.
+ * @param source - Content the headings belong to (Tab.Content, Post.Content)
+ * @param headings - The page's headings; located in source if the
+ *                   content pipeline did not
+ * @param offset - Lines shown above source
+ * @param maps - Layout steps applied to the page, in order
+ * @return Line of each heading, -1 where it was not found
+ */
+func headingRows(source string, headings []Heading, offset int, maps ...lineMap) []int {
+	if slices.ContainsFunc(headings, func(h Heading) bool { return h.Line == 0 }) {
+		headings = LocateHeadings(source, headings)
+	}
+
+	rows := make([]int, len(headings))
+	for i, h := range headings {
+		rows[i] = -1
+		if h.Line == 0 {
+			continue
+		}
+		row := h.Line - 1 + offset
+		for _, lm := range maps {
+			if lm != nil && row < len(lm) {
+				row = lm[row]
+			}
+		}
+		rows[i] = row
+	}
+	return rows
+}
+
+/**
+ * Finds the first line of each paragraph: non-blank lines that follow
+ * a blank line or start the content.
+ * @param content - Rendered content
+ * @return Line numbers in increasing order
+ */
+func paragraphLines(content string) []int {
+	var lines []int
+	blank := true
+	for i, line := range strings.Split(content, "\n") {
+		empty := strings.TrimSpace(ansi.Strip(line)) == ""
+		if !empty && blank {
+			lines = append(lines, i)
+		}
+		blank = empty
+	}
+	return lines
+}
@@ -0,0 +1,250 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Actions in the key help, by section
+var keyHelpSections = []struct {
+	title   string
+	actions []keyAction
+}{
+	{"Scrolling", []keyAction{
+		actionDown, actionUp, actionHalfDown, actionHalfUp, actionPageDown, actionPageUp,
+		actionTop, actionBottom, actionNextHeading, actionPrevHeading, actionCenter, actionOutline,
+	}},
+	{"Tabs", []keyAction{actionNextTab, actionPrevTab}},
+	{"Links", []keyAction{actionNextLink, actionPrevLink}},
+	{"Copying", []keyAction{actionVisual, actionYank}},
+	{"Search", []keyAction{actionSearch, actionSearchBack, actionSearchNext, actionSearchPrev, actionSearchAll, actionFind}},
+	{"Pages", []keyAction{
+		actionOpen, actionBack, actionNextPage, actionPrevPage,
+		actionFilterNext, actionFilterPrev, actionSort, actionWhatsNew, actionContact,
+	}},
+	{"General", []keyAction{actionCommand, actionMouse, actionHelp, actionQuit}},
+}
+
+/**
+ * Opens or closes the key help.
+ * @effects Closes any other overlay
+ */
+func (m *Model) toggleKeyHelp() {
+	open := !m.showKeys
+	m.closeOverlays()
+	m.showKeys = open
+	m.updateViewportContent()
+}
+
+/**
+ * Renders every binding of the session's keymap, by section.
+ * @return Styled help for the viewport
+ */
+func (m Model) renderKeyHelp() string {
+	km := m.keys.keymap
+	var b strings.Builder
+
+	b.WriteString(m.styles.projectTitle.Render("Keys ("+km.name+")") + "\n")
+	if km.counts {
+		b.WriteString(m.styles.projectMeta.Render("Counts repeat motions: 5j scrolls 5 lines, 12G goes to line 12, 3gt opens tab 3. A number on its own opens that tab.") + "\n")
+	} else {
+		b.WriteString(m.styles.projectMeta.Render("1-9 open that tab.") + "\n")
+	}
+
+	// Width of the key column, from the longest list of keys
+	width := 0
+	for _, section := range keyHelpSections {
+		for _, a := range section.actions {
+			width = max(width, ansi.StringWidth(m.keyList(a, ", ")))
+		}
+	}
+
+	for _, section := range keyHelpSections {
+		var rows []string
+		for _, a := range section.actions {
+			keys := m.keyList(a, ", ")
+			if keys == "" {
+				continue
+			}
+			pad := strings.Repeat(" ", width-ansi.StringWidth(keys))
+			rows = append(rows, "  "+m.styles.pendingKeys.Render(keys)+pad+"  "+actionInfo[a].help)
+		}
+		if len(rows) == 0 {
+			continue
+		}
+		b.WriteString("\n" + m.styles.postTitle.Render(section.title) + "\n")
+		b.WriteString(strings.Join(rows, "\n") + "\n")
+	}
+
+	b.WriteString("\n" + m.styles.projectMeta.Render(fmt.Sprintf(
+		"Ctrl+C always quits • :keymap switches between vim, emacs and arrows • %s to close",
+		m.keyChoice(actionHelp, actionBack))))
+	return b.String()
+}
+
+/**
+ * Lists every key bound to an action.
+ * @param sep - Separator between keys
+ * @return Labels, or "" if the action is unbound
+ */
+func (m Model) keyList(action keyAction, sep string) string {
+	var labels []string
+	for _, seq := range m.keys.keymap.bindings[action] {
+		labels = append(labels, keyLabel(seq))
+	}
+	return strings.Join(labels, sep)
+}
+
+/**
+ * Labels the first key of each bound action.
+ */
+func (m Model) firstKeys(actions ...keyAction) []string {
+	var labels []string
+	for _, a := range actions {
+		if l := m.keys.keymap.label(a); l != "" {
+			labels = append(labels, l)
+		}
+	}
+	return labels
+}
+
+/**
+ * Names the first key of each action as alternatives, e.g. "w or Esc".
+ */
+func (m Model) keyChoice(actions ...keyAction) string {
+	return strings.Join(m.firstKeys(actions...), " or ")
+}
+
+/**
+ * Builds one help bar entry from the first key of each action, e.g.
+ * "j/k: scroll".
+ * @return Entry, or "" if none of the actions is bound
+ */
+func (m Model) helpItem(what string, actions ...keyAction) string {
+	labels := m.firstKeys(actions...)
+	if len(labels) == 0 {
+		return ""
+	}
+	return strings.Join(labels, "/") + ": " + what
+}
+
+/**
+ * Lists the help bar entries for the current view, most important
+ * first; the bar shows as many as fit.
+ */
+func (m Model) helpItems() []string {
+	var items []string
+	if len(m.changes) > 0 && !m.whatsNew {
+		items = append(items, m.helpItem("what's new", actionWhatsNew))
+	}
+	if _, ok := m.contactCode(); ok && !m.showContact {
+		items = append(items, m.helpItem("contact QR", actionContact))
+	}
+
+	blogList := false
+	switch {
+	case m.visual:
+		items = append(items,
+			m.helpItem("extend", actionDown, actionUp),
+			m.helpItem("copy", actionYank),
+			m.helpItem("cancel", actionBack))
+	case m.outlineFocus && m.outlineVisible():
+		items = append(items,
+			m.helpItem("select heading", actionDown, actionUp),
+			m.helpItem("go", actionOpen),
+			m.helpItem("leave outline", actionBack))
+	case m.showKeys:
+		items = append(items, m.helpItem("close", actionHelp, actionBack))
+	case m.whatsNew:
+		items = append(items, m.helpItem("close", actionWhatsNew, actionBack))
+	case m.showContact:
+		items = append(items, m.helpItem("close", actionContact, actionBack))
+	case m.showLink:
+		items = append(items, m.helpItem("copy", actionYank), "o: QR code", m.helpItem("close", actionBack))
+	case m.linkFocus >= 0:
+		items = append(items,
+			m.helpItem("follow link", actionOpen),
+			m.helpItem("copy URL", actionYank),
+			m.helpItem("links", actionNextLink, actionPrevLink),
+			m.helpItem("unfocus", actionBack))
+	case m.showResults:
+		items = append(items,
+			m.helpItem("select", actionDown, actionUp),
+			m.helpItem("go to match", actionOpen),
+			m.helpItem("close", actionBack))
+	case m.onProjectsTab():
+		items = append(items,
+			m.helpItem("filter tech", actionFilterNext, actionFilterPrev),
+			m.helpItem("sort by date", actionSort))
+	case m.onBlogTab() && m.blogOpen:
+		items = append(items, m.helpItem("back to posts", actionBack))
+	case m.onBlogTab():
+		blogList = true
+		items = append(items,
+			m.helpItem("select", actionDown, actionUp),
+			m.helpItem("read", actionOpen),
+			m.helpItem("page", actionPrevPage, actionNextPage),
+			m.helpItem("filter tag", actionFilterNext, actionFilterPrev))
+	}
+
+	if len(m.currentHeadings()) > 0 && !m.outlineFocus {
+		items = append(items, m.helpItem("outline", actionOutline))
+	}
+	if len(m.links) > 0 && m.linkFocus < 0 {
+		items = append(items, m.helpItem("links", actionNextLink, actionPrevLink))
+	}
+	if m.highlight && len(m.matches) > 0 {
+		items = append(items, m.helpItem("next/prev match", actionSearchNext, actionSearchPrev))
+	}
+	items = append(items, m.helpItem("tabs", actionPrevTab, actionNextTab))
+	if !blogList && !m.outlineFocus {
+		items = append(items, m.helpItem("scroll", actionDown, actionUp))
+	}
+	return append(items,
+		m.helpItem("keys", actionHelp),
+		m.helpItem("quit", actionQuit),
+		m.helpItem("commands", actionCommand),
+		m.helpItem("search", actionSearch),
+		m.helpItem("find", actionFind),
+		m.helpItem("top/bottom", actionTop, actionBottom))
+}
+
+/**
+ * Joins help bar entries, dropping those that do not fit.
+ * @param items - Entries, most important first ("" entries are skipped)
+ * @param width - Available columns (0 = no limit)
+ */
+func joinHelp(items []string, width int) string {
+	const sep = "  •  "
+	var out string
+	for _, item := range items {
+		if item == "" {
+			continue
+		}
+		next := item
+		if out != "" {
+			next = out + sep + item
+		}
+		if width > 0 && ansi.StringWidth(next) > width {
+			continue
+		}
+		out = next
+	}
+	return out
+}
+
+func init() {
+	registerOption(option{
+		name: "helpbar",
+		help: "Show the key hints under the status bar",
+		get:  func(m Model) bool { return m.showHelp },
+		set: func(m *Model, on bool) {
+			m.showHelp = on
+			offset := m.viewport.YOffset
+			m.SetSize(m.width, m.height)
+			m.viewport.SetYOffset(offset)
+		},
+	})
+}
@@ -0,0 +1,170 @@
+package tui
+
+import (
+	"image"
+	"strings"
+
+	"github.com/adamdeleeuw/ssh-portfolio/internal/termimg"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+/**
+ * An image referenced from content, drawn when the page is shown
+ * so it can be scaled to the current viewport.
+ */
+type Image struct {
+	Key     string      // Stable identifier of the image data, for caching
+	Alt     string      // Alt text, shown as a caption
+	Img     image.Image // Decoded image
+	Braille bool        // Prefer braille over half blocks (line art, logos)
+}
+
+/**
+ * A graphics image positioned in the viewport content. The escape
+ * sequence is only sent while all of its rows are visible.
+ */
+type placedImage struct {
+	line int    // Content line of the image's top row
+	rows int    // Rows reserved below (and including) line
+	seq  string // Kitty or sixel sequence
+}
+
+/**
+ * Sets how images are drawn for this session.
+ * @param protocol - Detected terminal graphics support
+ */
+func (m *Model) SetImageProtocol(protocol termimg.Protocol) {
+	m.imageProtocol = protocol
+}
+
+/**
+ * Sets an image shown on the splash screen in place of the ASCII logo.
+ * @param img - Splash image
+ */
+func (m *Model) SetSplashImage(img Image) {
+	m.splashImage = &img
+}
+
+/**
+ * Renders one image for the current session.
+ * @param img - Image to draw
+ * @param maxCols - Maximum width in cells
+ * @return Rendered image (see termimg.Render) and its size in cells
+ */
+func (m Model) drawImage(img Image, maxCols int) (string, int, int) {
+	opts := termimg.Options{Cols: maxCols, Protocol: m.imageProtocol, Background: lipgloss.Color(m.theme.Background)}
+	if img.Braille && !opts.Protocol.Graphics() {
+		opts.Protocol = termimg.Braille
+	}
+
+	cols, rows := termimg.Size(img.Img, opts)
+	return termimg.Render(img.Key, img.Img, opts), cols, rows
+}
+
+/**
+ * Replaces image placeholder lines in rendered content with images
+ * scaled to the viewport. Text images are inlined; graphics images get
+ * a caption line plus blank rows and are drawn over them by View.
+ * @param content - Rendered page
+ * @param images - Images keyed by placeholder
+ * @return Content with images laid out, graphics to draw, and where
+ *         each line moved
+ */
+func (m Model) layoutImages(content string, images map[string]Image) (string, []placedImage, lineMap) {
+	if len(images) == 0 {
+		return content, nil, nil
+	}
+
+	var out []string
+	var placed []placedImage
+	cols := m.viewport.Width - 4
+	lines := strings.Split(content, "\n")
+	rows := make(lineMap, len(lines))
+
+	for i, line := range lines {
+		rows[i] = len(out)
+		img, ok := images[strings.TrimSpace(ansi.Strip(line))]
+		if !ok {
+			out = append(out, line)
+			continue
+		}
+
+		art, _, rows := m.drawImage(img, cols)
+		caption := "  " + m.styles.imageCaption.Render("▣ "+img.Alt)
+
+		if m.imageProtocol.Graphics() {
+			placed = append(placed, placedImage{line: len(out), rows: rows, seq: art})
+			out = append(out, caption)
+			for i := 1; i < rows; i++ {
+				out = append(out, "")
+			}
+			continue
+		}
+
+		for _, l := range strings.Split(art, "\n") {
+			out = append(out, "  "+l)
+		}
+		if img.Alt != "" {
+			out = append(out, caption)
+		}
+	}
+
+	return strings.Join(out, "\n"), placed, rows
+}
+
+/**
+ * Draws graphics images over the rendered viewport. Images only partly
+ * in view are left out, leaving their caption and blank rows.
+ * @param view - Output of viewport.View
+ * @return View with graphics sequences attached to image rows
+ */
+func (m Model) overlayImages(view string) string {
+	if !m.imageProtocol.Graphics() {
+		return view
+	}
+
+	lines := strings.Split(view, "\n")
+	for _, p := range m.placed {
+		row := p.line - m.viewport.YOffset
+		if row < 0 || row+p.rows > len(lines) {
+			continue
+		}
+		// Step over the page margin, draw, step back
+		lines[row] = "\x1b[2C" + p.seq + "\x1b[2D" + lines[row]
+	}
+
+	// Kitty images sit above text, so wipe old placements on every new view
+	if m.imageProtocol == termimg.Kitty && len(lines) > 0 {
+		frame := uint32(m.viewGeneration)<<16 ^ uint32(m.viewport.YOffset)
+		lines[0] = termimg.ClearKitty(frame) + lines[0]
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+/**
+ * Renders the splash image centered for the splash screen.
+ * @return Lines of the image area
+ */
+func (m Model) renderSplashImage() string {
+	art, cols, rows := m.drawImage(*m.splashImage, min(m.width-4, 60))
+	pad := max((m.width-cols)/2, 0)
+
+	if m.imageProtocol.Graphics() {
+		// Reserve the rows and draw over them from the first one
+		lines := make([]string, rows)
+		lines[0] = art
+		if pad > 0 {
+			lines[0] = ansi.CUF(pad) + art + ansi.CUB(pad)
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	margin := strings.Repeat(" ", pad)
+	lines := strings.Split(art, "\n")
+	for i := range lines {
+		lines[i] = margin + lines[i]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,77 @@
+package tui
+
+import (
+	"image"
+	"strings"
+	"testing"
+
+	"github.com/adamdeleeuw/ssh-portfolio/internal/termimg"
+	"github.com/charmbracelet/x/ansi"
+)
+
+/**
+ * Builds a model showing one tab with an image between two paragraphs,
+ * followed by enough blank lines to scroll.
+ */
+func testImageModel(protocol termimg.Protocol) Model {
+	img := Image{Key: "test-" + protocol.String(), Alt: "Screenshot", Img: image.NewGray(image.Rect(0, 0, 32, 32))}
+	tab := Tab{
+		Name:    "Projects",
+		Content: "  Before\n  " + "sshportfolioimage0" + "\n  After" + strings.Repeat("\n", 60),
+		Images:  map[string]Image{"sshportfolioimage0": img},
+	}
+
+	m := NewModel([]Tab{tab}, "test")
+	m.showSplash = false
+	m.SetImageProtocol(protocol)
+	m.SetSize(80, 40)
+	return m
+}
+
+/**
+ * Tests text images are inlined in place of their placeholder.
+ */
+func TestLayoutImages_Text(t *testing.T) {
+	m := testImageModel(termimg.Blocks)
+	view := ansi.Strip(m.viewport.View())
+
+	if strings.Contains(view, "sshportfolioimage") {
+		t.Error("Expected placeholder to be replaced")
+	}
+	if !strings.Contains(view, "▀") || !strings.Contains(view, "▣ Screenshot") {
+		t.Errorf("Expected half blocks and caption, got %q", view)
+	}
+	if len(m.placed) != 0 {
+		t.Error("Expected no graphics placements for text images")
+	}
+}
+
+/**
+ * Tests graphics images reserve rows and are only drawn when fully visible.
+ */
+func TestLayoutImages_Graphics(t *testing.T) {
+	m := testImageModel(termimg.Kitty)
+
+	if len(m.placed) != 1 {
+		t.Fatalf("Expected 1 placed image, got %d", len(m.placed))
+	}
+	p := m.placed[0]
+	if p.rows < 2 {
+		t.Fatalf("Expected image to reserve several rows, got %d", p.rows)
+	}
+
+	view := m.overlayImages(m.viewport.View())
+	if !strings.Contains(view, p.seq) {
+		t.Error("Expected image drawn while fully visible")
+	}
+
+	// Scroll so the top row is out of view
+	m.viewport.SetYOffset(p.line + 1)
+	view = m.overlayImages(m.viewport.View())
+	if strings.Contains(view, p.seq) {
+		t.Error("Expected partly visible image to be left out")
+	}
+	if !strings.Contains(view, termimg.ClearKitty(0)[:5]) {
+		t.Error("Expected kitty placements to be cleared")
+	}
+}
@@ -0,0 +1,456 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+/**
+ * Key bindings of one scheme. Each action has zero or more key
+ * sequences; keys within a sequence are space-separated, e.g. "g g".
+ */
+type keymap struct {
+	name     string
+	counts   bool // Digits start a count (5j, 12G); otherwise 1-9 open that tab
+	bindings map[keyAction][]string
+
+	sequences map[string]keyAction // Complete sequences
+	prefixes  map[string]bool      // Unfinished sequences, e.g. "g"
+}
+
+// Name and description of each bindable action, used by keymap files
+// and the key help
+var actionInfo = map[keyAction]struct{ name, help string }{
+	actionDown:        {"down", "Scroll down a line (next post in the blog list)"},
+	actionUp:          {"up", "Scroll up a line (previous post in the blog list)"},
+	actionHalfDown:    {"half-page-down", "Scroll half a page down"},
+	actionHalfUp:      {"half-page-up", "Scroll half a page up"},
+	actionPageDown:    {"page-down", "Scroll a page down"},
+	actionPageUp:      {"page-up", "Scroll a page up"},
+	actionTop:         {"top", "Go to the top"},
+	actionBottom:      {"bottom", "Go to the bottom"},
+	actionNextHeading: {"next-heading", "Next heading, or paragraph on pages without headings"},
+	actionPrevHeading: {"prev-heading", "Previous heading or paragraph"},
+	actionCenter:      {"center", "Centre the line the last jump landed on"},
+	actionNextTab:     {"next-tab", "Next tab"},
+	actionPrevTab:     {"prev-tab", "Previous tab"},
+	actionOpen:        {"open", "Read the selected post, or follow the focused link"},
+	actionBack:        {"back", "Close an overlay or return to the post list"},
+	actionNextPage:    {"next-page", "Next page of posts"},
+	actionPrevPage:    {"prev-page", "Previous page of posts"},
+	actionFilterNext:  {"filter-next", "Next tech or tag filter"},
+	actionFilterPrev:  {"filter-prev", "Previous tech or tag filter"},
+	actionSort:        {"sort", "Sort projects oldest or newest first"},
+	actionWhatsNew:    {"whats-new", "What's new since your last visit"},
+	actionContact:     {"contact", "Contact QR code"},
+	actionCommand:     {"command", "Open the command line"},
+	actionSearch:      {"search", "Search the page"},
+	actionSearchBack:  {"search-back", "Search the page upwards"},
+	actionSearchNext:  {"search-next", "Next match"},
+	actionSearchPrev:  {"search-prev", "Previous match"},
+	actionSearchAll:   {"search-all", "Search every tab and list the matches"},
+	actionFind:        {"find", "Find a page, heading, project or post by name"},
+	actionOutline:     {"outline", "Show the outline and move into it; again to hide it"},
+	actionNextLink:    {"next-link", "Focus the next link (next tab on pages without links)"},
+	actionPrevLink:    {"prev-link", "Focus the previous link (previous tab on pages without links)"},
+	actionVisual:      {"visual", "Select lines to copy; again to stop"},
+	actionYank:        {"yank", "Copy the selected lines, or the focused link's URL"},
+	actionMouse:       {"mouse", "Turn the mouse on or off (off lets your terminal select text)"},
+	actionHelp:        {"help", "Show or hide this help"},
+	actionQuit:        {"quit", "Quit"},
+}
+
+/**
+ * Finds an action by its keymap file name.
+ * @return Action, and false if there is none by that name
+ */
+func actionByName(name string) (keyAction, bool) {
+	for a, info := range actionInfo {
+		if info.name == name {
+			return a, true
+		}
+	}
+	return actionNone, false
+}
+
+// Built-in schemes; the first is the default
+var builtinKeymaps = []*keymap{
+	{
+		name:   "vim",
+		counts: true,
+		bindings: map[keyAction][]string{
+			actionDown:        {"j", "down"},
+			actionUp:          {"k", "up"},
+			actionHalfDown:    {"ctrl+d", "d"},
+			actionHalfUp:      {"ctrl+u", "u"},
+			actionPageDown:    {"ctrl+f", "pgdown"},
+			actionPageUp:      {"ctrl+b", "pgup"},
+			actionTop:         {"g g", "home"},
+			actionBottom:      {"G", "end"},
+			actionNextHeading: {"}"},
+			actionPrevHeading: {"{"},
+			actionCenter:      {"z z"},
+			actionNextTab:     {"l", "right", "g t"},
+			actionPrevTab:     {"h", "left", "g T"},
+			actionOpen:        {"enter"},
+			actionBack:        {"esc", "backspace"},
+			actionNextPage:    {"] ]"},
+			actionPrevPage:    {"[ ["},
+			actionFilterNext:  {"f"},
+			actionFilterPrev:  {"F"},
+			actionSort:        {"s"},
+			actionWhatsNew:    {"w"},
+			actionContact:     {"c"},
+			actionCommand:     {":"},
+			actionSearch:      {"/"},
+			actionSearchBack:  {"?"},
+			actionSearchNext:  {"n"},
+			actionSearchPrev:  {"N"},
+			actionSearchAll:   {"g /"},
+			actionFind:        {"ctrl+p"},
+			actionOutline:     {"o"},
+			actionNextLink:    {"tab", "] l"},
+			actionPrevLink:    {"shift+tab", "[ l"},
+			actionVisual:      {"v", "V"},
+			actionYank:        {"y"},
+			actionMouse:       {"m"},
+			actionHelp:        {"f1"},
+			actionQuit:        {"q"},
+		},
+	},
+	{
+		name: "emacs",
+		bindings: map[keyAction][]string{
+			actionDown:        {"ctrl+n", "down"},
+			actionUp:          {"ctrl+p", "up"},
+			actionPageDown:    {"ctrl+v", "pgdown"},
+			actionPageUp:      {"alt+v", "pgup"},
+			actionTop:         {"alt+<", "home"},
+			actionBottom:      {"alt+>", "end"},
+			actionNextHeading: {"alt+}"},
+			actionPrevHeading: {"alt+{"},
+			actionCenter:      {"ctrl+l"},
+			actionNextTab:     {"ctrl+x right"},
+			actionPrevTab:     {"ctrl+x left"},
+			actionOpen:        {"enter"},
+			actionBack:        {"ctrl+g", "esc"},
+			actionNextPage:    {"n"},
+			actionPrevPage:    {"p"},
+			actionFilterNext:  {"f"},
+			actionFilterPrev:  {"F"},
+			actionSort:        {"s"},
+			actionWhatsNew:    {"w"},
+			actionContact:     {"c"},
+			actionCommand:     {"alt+x"},
+			actionSearch:      {"ctrl+s"},
+			actionSearchBack:  {"ctrl+r"},
+			actionSearchNext:  {"alt+n"},
+			actionSearchPrev:  {"alt+p"},
+			actionSearchAll:   {"alt+s"},
+			actionFind:        {"ctrl+x b"},
+			actionOutline:     {"alt+o"},
+			actionNextLink:    {"tab"},
+			actionPrevLink:    {"shift+tab"},
+			actionVisual:      {"ctrl+@"},
+			actionYank:        {"alt+w"},
+			actionMouse:       {"alt+m"},
+			actionHelp:        {"?", "f1"},
+			actionQuit:        {"ctrl+x ctrl+c", "q"},
+		},
+	},
+	{
+		name: "arrows",
+		bindings: map[keyAction][]string{
+			actionDown:        {"down"},
+			actionUp:          {"up"},
+			actionPageDown:    {"pgdown", " "},
+			actionPageUp:      {"pgup"},
+			actionTop:         {"home"},
+			actionBottom:      {"end"},
+			actionNextHeading: {"ctrl+down"},
+			actionPrevHeading: {"ctrl+up"},
+			actionNextTab:     {"right"},
+			actionPrevTab:     {"left"},
+			actionOpen:        {"enter"},
+			actionBack:        {"esc", "backspace"},
+			actionFilterNext:  {"f"},
+			actionFilterPrev:  {"F"},
+			actionSort:        {"s"},
+			actionWhatsNew:    {"w"},
+			actionContact:     {"c"},
+			actionCommand:     {":"},
+			actionSearch:      {"/"},
+			actionSearchNext:  {"n", "f3"},
+			actionSearchPrev:  {"N"},
+			actionSearchAll:   {"ctrl+f"},
+			actionFind:        {"ctrl+p"},
+			actionOutline:     {"o"},
+			actionNextLink:    {"tab"},
+			actionPrevLink:    {"shift+tab"},
+			actionVisual:      {"v"},
+			actionYank:        {"y"},
+			actionMouse:       {"m"},
+			actionHelp:        {"?", "f1"},
+			actionQuit:        {"q"},
+		},
+	},
+}
+
+func init() {
+	for _, km := range builtinKeymaps {
+		if err := km.compile(); err != nil {
+			panic(fmt.Sprintf("keymap %s: %v", km.name, err))
+		}
+	}
+	activeKeymaps.Store(&keymapSet{keymaps: builtinKeymaps, initial: builtinKeymaps[0]})
+}
+
+/**
+ * Builds the sequence lookup of a keymap and checks its bindings.
+ * @return error if a sequence is empty, bound twice, starts a longer
+ *         sequence or begins with a digit
+ */
+func (km *keymap) compile() error {
+	km.sequences = make(map[string]keyAction)
+	km.prefixes = make(map[string]bool)
+
+	for action := actionDown; action <= actionQuit; action++ {
+		for _, seq := range km.bindings[action] {
+			keys := strings.Split(seq, " ")
+			if seq == " " {
+				keys = []string{" "} // The space bar on its own
+			}
+			if slices.Contains(keys, "") {
+				return fmt.Errorf("%s: invalid key sequence %q", actionInfo[action].name, seq)
+			}
+			if k := keys[0]; len(k) == 1 && k[0] >= '0' && k[0] <= '9' {
+				return fmt.Errorf("%s: %q starts with a digit, which is reserved for counts and tab numbers", actionInfo[action].name, seq)
+			}
+			if other, ok := km.sequences[seq]; ok && other != action {
+				return fmt.Errorf("%q is bound to both %s and %s", seq, actionInfo[other].name, actionInfo[action].name)
+			}
+			km.sequences[seq] = action
+			for i := 1; i < len(keys); i++ {
+				km.prefixes[strings.Join(keys[:i], " ")] = true
+			}
+		}
+	}
+
+	seqs := make([]string, 0, len(km.sequences))
+	for seq := range km.sequences {
+		seqs = append(seqs, seq)
+	}
+	sort.Strings(seqs)
+	for _, seq := range seqs {
+		if km.prefixes[seq] {
+			return fmt.Errorf("%q (%s) also starts a longer sequence", seq, actionInfo[km.sequences[seq]].name)
+		}
+	}
+	return nil
+}
+
+/**
+ * Keymaps sessions can choose from, and the one they start with.
+ */
+type keymapSet struct {
+	keymaps []*keymap
+	initial *keymap
+}
+
+// Current keymaps; replaced as a whole by LoadKeymaps
+var activeKeymaps atomic.Pointer[keymapSet]
+
+/**
+ * Finds a keymap by name.
+ * @return Keymap, and false if there is none by that name
+ */
+func keymapByName(name string) (*keymap, bool) {
+	for _, km := range activeKeymaps.Load().keymaps {
+		if km.name == name {
+			return km, true
+		}
+	}
+	return nil, false
+}
+
+/**
+ * Keymap file: the default scheme and per-scheme overrides, e.g.
+ * {"default": "emacs", "keymaps": {"vim": {"quit": ["q", "Z Z"]}}}.
+ */
+type keymapFile struct {
+	Default string                         `json:"default,omitempty"`
+	Keymaps map[string]map[string][]string `json:"keymaps,omitempty"` // Scheme → action → key sequences
+}
+
+/**
+ * Replaces the built-in keymaps with those of a keymap file. Listed
+ * actions replace the scheme's bindings for that action; an empty list
+ * unbinds it. Must be called before sessions start.
+ * @param path - JSON keymap file
+ * @return error if the file cannot be read or has invalid bindings
+ */
+func LoadKeymaps(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read keymap file: %w", err)
+	}
+	set, err := parseKeymaps(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	activeKeymaps.Store(set)
+	return nil
+}
+
+/**
+ * Applies a keymap file to copies of the built-in keymaps.
+ * @param data - JSON keymap file
+ * @return Keymaps for sessions
+ * @return error naming the first invalid scheme, action or binding
+ */
+func parseKeymaps(data []byte) (*keymapSet, error) {
+	var file keymapFile
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&file); err != nil {
+		return nil, fmt.Errorf("invalid keymap file: %w", err)
+	}
+
+	set := &keymapSet{}
+	for _, builtin := range builtinKeymaps {
+		km := &keymap{name: builtin.name, counts: builtin.counts, bindings: make(map[keyAction][]string)}
+		for action, seqs := range builtin.bindings {
+			km.bindings[action] = seqs
+		}
+		set.keymaps = append(set.keymaps, km)
+	}
+
+	names := make([]string, 0, len(file.Keymaps))
+	for name := range file.Keymaps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		i := slices.IndexFunc(set.keymaps, func(km *keymap) bool { return km.name == name })
+		if i < 0 {
+			return nil, fmt.Errorf("unknown keymap %q (want vim, emacs or arrows)", name)
+		}
+		for actionName, seqs := range file.Keymaps[name] {
+			action, ok := actionByName(actionName)
+			if !ok {
+				return nil, fmt.Errorf("%s: unknown action %q", name, actionName)
+			}
+			set.keymaps[i].bindings[action] = seqs
+		}
+	}
+
+	for _, km := range set.keymaps {
+		if err := km.compile(); err != nil {
+			return nil, fmt.Errorf("%s: %w", km.name, err)
+		}
+	}
+
+	set.initial = set.keymaps[0]
+	if file.Default != "" {
+		i := slices.IndexFunc(set.keymaps, func(km *keymap) bool { return km.name == file.Default })
+		if i < 0 {
+			return nil, fmt.Errorf("unknown default keymap %q", file.Default)
+		}
+		set.initial = set.keymaps[i]
+	}
+	return set, nil
+}
+
+/**
+ * Chooses the session's key scheme.
+ * @param name - "vim", "emacs" or "arrows"
+ * @return error if there is no keymap by that name
+ */
+func (m *Model) SetKeymap(name string) error {
+	km, ok := keymapByName(name)
+	if !ok {
+		return fmt.Errorf("unknown keymap: %s", name)
+	}
+	m.keys = keyParser{keymap: km}
+	if m.showKeys {
+		m.refreshView()
+	}
+	return nil
+}
+
+func init() {
+	registerCommand(command{
+		name:  "keymap",
+		usage: "[name]",
+		help:  "Switch key scheme (vim, emacs, arrows), or show the current one",
+		complete: func(Model) []string {
+			var names []string
+			for _, km := range activeKeymaps.Load().keymaps {
+				names = append(names, km.name)
+			}
+			return names
+		},
+		run: func(m *Model, arg string) (string, tea.Cmd, error) {
+			if arg != "" {
+				if err := m.SetKeymap(arg); err != nil {
+					return "", nil, err
+				}
+			}
+			return "keymap " + m.keys.keymap.name, nil, nil
+		},
+	})
+}
+
+// Display names of keys whose tea names are not what a visitor types
+var keyNames = map[string]string{
+	"tab": "Tab", "shift+tab": "Shift+Tab", "enter": "Enter", "esc": "Esc",
+	"backspace": "Backspace", "pgdown": "PgDn", "pgup": "PgUp", "home": "Home",
+	"end": "End", "up": "↑", "down": "↓", "left": "←", "right": "→", " ": "Space",
+	"f1": "F1", "f3": "F3", "ctrl+@": "Ctrl+Space",
+}
+
+/**
+ * Formats a key sequence for help text: "g g" as "gg", "ctrl+x left"
+ * as "Ctrl+X ←".
+ */
+func keyLabel(seq string) string {
+	if seq == " " {
+		return keyNames[seq]
+	}
+	keys := strings.Split(seq, " ")
+	short := true
+	for i, k := range keys {
+		if name, ok := keyNames[k]; ok {
+			keys[i] = name
+		} else if mod, key, ok := strings.Cut(k, "+"); ok && key != "" {
+			if name, ok := keyNames[key]; ok {
+				key = name
+			}
+			keys[i] = strings.ToUpper(mod[:1]) + mod[1:] + "+" + strings.ToUpper(key)
+		}
+		short = short && len([]rune(keys[i])) == 1
+	}
+	if short {
+		return strings.Join(keys, "")
+	}
+	return strings.Join(keys, " ")
+}
+
+/**
+ * Formats the first key bound to an action.
+ * @return Label, or "" if the action is unbound
+ */
+func (km *keymap) label(action keyAction) string {
+	if seqs := km.bindings[action]; len(seqs) > 0 {
+		return keyLabel(seqs[0])
+	}
+	return ""
+}
@@ -0,0 +1,116 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+/**
+ * Tests keymap files override bindings and choose the default scheme.
+ */
+func TestParseKeymaps(t *testing.T) {
+	set, err := parseKeymaps([]byte(`{"default": "emacs", "keymaps": {"vim": {"quit": ["Z Z"], "center": []}}}`))
+	if err != nil {
+		t.Fatalf("Failed to parse keymaps: %v", err)
+	}
+	if set.initial.name != "emacs" {
+		t.Errorf("Expected emacs by default, got %s", set.initial.name)
+	}
+
+	vim := set.keymaps[0]
+	if vim.sequences["Z Z"] != actionQuit || vim.sequences["q"] != actionNone || !vim.prefixes["Z"] {
+		t.Errorf("Expected ZZ to replace q, got %v", vim.bindings[actionQuit])
+	}
+	if _, ok := vim.sequences["z z"]; ok {
+		t.Error("Expected an empty list to unbind zz")
+	}
+	if builtinKeymaps[0].sequences["q"] != actionQuit {
+		t.Error("Expected the built-in vim keymap to be unchanged")
+	}
+
+	for _, tt := range []struct{ file, want string }{
+		{`{"keymaps": {"nano": {}}}`, "unknown keymap"},
+		{`{"keymaps": {"vim": {"jump": ["x"]}}}`, "unknown action"},
+		{`{"keymaps": {"vim": {"quit": ["j"]}}}`, `"j" is bound to both down and quit`},
+		{`{"keymaps": {"vim": {"quit": ["g"]}}}`, "also starts a longer sequence"},
+		{`{"keymaps": {"vim": {"quit": ["1 q"]}}}`, "starts with a digit"},
+		{`{"default": "nano"}`, "unknown default keymap"},
+		{`{"presets": {}}`, "unknown field"},
+	} {
+		if _, err := parseKeymaps([]byte(tt.file)); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.file, tt.want, err)
+		}
+	}
+}
+
+/**
+ * Tests the emacs scheme's sequences, tab digits and generated help.
+ */
+func TestKeymap_Emacs(t *testing.T) {
+	m := motionModel()
+	if err := m.SetKeymap("emacs"); err != nil {
+		t.Fatal(err)
+	}
+
+	m = pressKeys(m, tea.KeyMsg{Type: tea.KeyCtrlN}, tea.KeyMsg{Type: tea.KeyCtrlN})
+	if m.viewport.YOffset != 2 {
+		t.Errorf("Expected Ctrl+N twice to scroll 2 lines, got %d", m.viewport.YOffset)
+	}
+
+	m = pressKeys(m, tea.KeyMsg{Type: tea.KeyCtrlX})
+	if m.keys.pending() != "Ctrl+X" {
+		t.Errorf("Expected Ctrl+X to wait for the next key, got %q", m.keys.pending())
+	}
+	m = pressKeys(m, tea.KeyMsg{Type: tea.KeyRight})
+	if m.activeTab != 1 {
+		t.Errorf("Expected Ctrl+X → to go to the next tab, got %d", m.activeTab)
+	}
+
+	m = typeKeys(m, "3")
+	if m.activeTab != 2 || m.keys.pending() != "" {
+		t.Errorf("Expected 3 to open tab 3 at once, got tab %d", m.activeTab)
+	}
+
+	if help := m.renderHelpBar(); !strings.Contains(help, "Ctrl+N/Ctrl+P: scroll") || strings.Contains(help, "j/k") {
+		t.Errorf("Expected emacs keys in the help bar, got %q", help)
+	}
+	m = typeKeys(m, "?")
+	if !strings.Contains(m.content, "Keys (emacs)") || !strings.Contains(m.content, "Alt+X") {
+		t.Error("Expected the key help to list the emacs bindings")
+	}
+
+	if err := m.SetKeymap("nano"); err == nil {
+		t.Error("Expected an error for an unknown keymap")
+	}
+}
+
+/**
+ * Tests the help bar drops entries that do not fit.
+ */
+func TestJoinHelp(t *testing.T) {
+	items := []string{"a: one", "", "b: two", "c: a much longer entry", "d: four"}
+	if got := joinHelp(items, 30); got != "a: one  •  b: two  •  d: four" {
+		t.Errorf("Unexpected help bar %q", got)
+	}
+}
+
+/**
+ * Tests key sequences are shown the way visitors type them.
+ */
+func TestKeyLabel(t *testing.T) {
+	for seq, want := range map[string]string{
+		"g g":           "gg",
+		"ctrl+x right":  "Ctrl+X →",
+		"shift+tab":     "Shift+Tab",
+		"alt+<":         "Alt+<",
+		"ctrl+x ctrl+c": "Ctrl+X Ctrl+C",
+		" ":             "Space",
+		"pgdown":        "PgDn",
+	} {
+		if got := keyLabel(seq); got != want {
+			t.Errorf("%q: expected %q, got %q", seq, want, got)
+		}
+	}
+}
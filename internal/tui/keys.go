@@ -0,0 +1,550 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// How long a partial key sequence ("g", "5") waits for its next key
+const keyTimeout = time.Second
+
+// Largest count accepted before a motion
+const maxCount = 9999
+
+/**
+ * What a key sequence asks for. The keys bound to each action come
+ * from the session's keymap.
+ */
+type keyAction int
+
+const (
+	actionNone        keyAction = iota // Not bound; the key is ignored
+	actionPending                      // Part of a sequence; wait for the next key
+	actionDown                         // Scroll down a line; next post in the blog list
+	actionUp                           // Scroll up a line; previous post in the blog list
+	actionHalfDown                     // Scroll half a page down
+	actionHalfUp                       // Scroll half a page up
+	actionPageDown                     // Scroll a page down; next page of the blog list
+	actionPageUp                       // Scroll a page up; previous page of the blog list
+	actionTop                          // Go to the top; with a count, to line N
+	actionBottom                       // Go to the bottom; with a count, to line N
+	actionNextTab                      // Next tab; with a count, tab N
+	actionPrevTab                      // Previous tab
+	actionJumpTab                      // Open tab N: a lone count, or a digit without counts
+	actionNextHeading                  // Next heading (or paragraph)
+	actionPrevHeading                  // Previous heading (or paragraph)
+	actionCenter                       // Centre the line the last jump landed on
+	actionNextPage                     // Next page of the blog list
+	actionPrevPage                     // Previous page of the blog list
+	actionOpen                         // Read the selected post
+	actionBack                         // Close an overlay or the open post
+	actionFilterNext                   // Next tech or tag filter
+	actionFilterPrev                   // Previous tech or tag filter
+	actionSort                         // Toggle the projects sort order
+	actionWhatsNew                     // Toggle "What's new"
+	actionContact                      // Toggle the contact QR code
+	actionCommand                      // Open the ":" command line
+	actionSearch                       // Search down the page
+	actionSearchBack                   // Search up the page
+	actionSearchNext                   // Next match of the last search
+	actionSearchPrev                   // Previous match of the last search
+	actionSearchAll                    // Search every tab
+	actionFind                         // Open the fuzzy finder
+	actionOutline                      // Show, focus or hide the outline
+	actionNextLink                     // Focus the next link; next tab on pages without links
+	actionPrevLink                     // Focus the previous link; previous tab on pages without links
+	actionVisual                       // Start or stop selecting lines
+	actionYank                         // Copy the selected lines or the focused link's URL
+	actionMouse                        // Turn mouse reporting on or off
+	actionHelp                         // Toggle the key help
+	actionQuit                         // Leave the portfolio
+)
+
+/**
+ * Key sequence parser: an optional count (in keymaps with counts),
+ * then the keys of a binding, e.g. "5", "g", "g" for 5gg.
+ */
+type keyParser struct {
+	keymap *keymap // Bindings (nil = the built-in vim keymap)
+	count  int     // Count typed so far (0 = none)
+	prefix string  // Keys of an unfinished sequence, space-separated
+	seq    int     // Bumped on every key so stale timeouts are ignored
+}
+
+/**
+ * Message sent when a partial sequence has waited keyTimeout.
+ */
+type keyTimeoutMsg struct{ seq int }
+
+/**
+ * Feeds one key to the parser.
+ * @param key - Key name, as tea.KeyMsg.String
+ * @return Action, and the count typed before it (0 if none)
+ */
+func (p *keyParser) feed(key string) (keyAction, int) {
+	km := p.keymap
+	if km == nil {
+		km = builtinKeymaps[0]
+	}
+	p.seq++
+
+	if p.prefix == "" && len(key) == 1 && key[0] >= '0' && key[0] <= '9' {
+		switch {
+		case km.counts && (key != "0" || p.count > 0):
+			p.count = min(p.count*10+int(key[0]-'0'), maxCount)
+			return actionPending, 0
+		case !km.counts && key != "0":
+			return actionJumpTab, int(key[0] - '0')
+		}
+	}
+
+	seq := key
+	if p.prefix != "" {
+		seq = p.prefix + " " + key
+	}
+	if action, ok := km.sequences[seq]; ok {
+		count := p.count
+		p.reset()
+		return action, count
+	}
+	if km.prefixes[seq] {
+		p.prefix = seq
+		return actionPending, 0
+	}
+
+	if p.prefix != "" {
+		// Not a sequence after all: drop it and read the key afresh
+		p.reset()
+		return p.feed(key)
+	}
+	p.reset()
+	return actionNone, 0
+}
+
+/**
+ * Ends a partial sequence that has waited too long. A count on its own
+ * jumps to that tab; anything else is dropped.
+ * @param seq - Sequence number from the timeout message
+ * @return Action and count, or actionNone if the timeout is stale
+ */
+func (p *keyParser) timeout(seq int) (keyAction, int) {
+	if seq != p.seq || p.pending() == "" {
+		return actionNone, 0
+	}
+	count, prefix := p.count, p.prefix
+	p.reset()
+	if prefix == "" {
+		return actionJumpTab, count
+	}
+	return actionNone, 0
+}
+
+/**
+ * Clears any partial sequence.
+ */
+func (p *keyParser) reset() {
+	p.count, p.prefix = 0, ""
+}
+
+/**
+ * The keys of a partial sequence, for the help bar.
+ * @return Typed keys, or "" when no sequence is pending
+ */
+func (p keyParser) pending() string {
+	s := ""
+	if p.prefix != "" {
+		s = keyLabel(p.prefix)
+	}
+	if p.count > 0 {
+		s = strconv.Itoa(p.count) + s
+	}
+	return s
+}
+
+/**
+ * Starts the timer that ends the current partial sequence.
+ */
+func (p keyParser) wait() tea.Cmd {
+	seq := p.seq
+	return tea.Tick(keyTimeout, func(time.Time) tea.Msg {
+		return keyTimeoutMsg{seq: seq}
+	})
+}
+
+/**
+ * Feeds a key to the keymap and runs the action it completes.
+ * @param msg - Key press
+ * @return Command from the action, or the sequence timer
+ * @effects Scrolls, switches tab, opens overlays, ...
+ */
+func (m *Model) updateKeys(msg tea.KeyMsg) tea.Cmd {
+	action, count := m.keys.feed(msg.String())
+	switch action {
+	case actionNone:
+		return nil
+	case actionPending:
+		return m.keys.wait()
+	}
+	return m.runAction(action, count)
+}
+
+/**
+ * Runs an action, letting the blog and projects tabs take the ones
+ * they use before falling back to motions.
+ * @param action - Action from the keymap
+ * @param count - Count typed before it (0 = none)
+ * @return Command from the action
+ */
+func (m *Model) runAction(action keyAction, count int) tea.Cmd {
+	switch action {
+	case actionQuit:
+		return tea.Quit
+	case actionCommand:
+		return m.openCmdline()
+	case actionSearch:
+		return m.openPrompt(promptSearch)
+	case actionSearchBack:
+		return m.openPrompt(promptSearchBack)
+	case actionSearchAll:
+		return m.openPrompt(promptSearchAll)
+	case actionFind:
+		return m.openFinder("")
+	case actionOutline:
+		m.toggleOutline()
+	case actionNextLink, actionPrevLink:
+		// Tab switches tab as it always did on pages without links
+		switch {
+		case len(m.links) == 0 && action == actionNextLink:
+			m.runMotion(actionNextTab, count)
+		case len(m.links) == 0:
+			m.runMotion(actionPrevTab, count)
+		case action == actionNextLink:
+			m.moveLinkFocus(max(count, 1))
+		default:
+			m.moveLinkFocus(-max(count, 1))
+		}
+	case actionVisual:
+		m.toggleVisual()
+	case actionYank:
+		return m.yank()
+	case actionMouse:
+		return m.toggleMouse()
+	case actionSearchNext:
+		m.nextMatch(max(count, 1))
+	case actionSearchPrev:
+		m.nextMatch(-max(count, 1))
+	case actionHelp:
+		m.toggleKeyHelp()
+	case actionWhatsNew:
+		m.toggleWhatsNew()
+	case actionContact:
+		m.toggleContact()
+	case actionBack:
+		m.back()
+	default:
+		if m.updateVisual(action, count) {
+			return nil
+		}
+		if m.updateOutline(action, count) {
+			return nil
+		}
+		if m.showResults && m.updateResults(action, count) {
+			return nil
+		}
+		if action == actionOpen && m.followFocusedLink() {
+			return nil
+		}
+		if !m.overlayOpen() && m.onBlogTab() && m.updateBlog(action, count) {
+			return nil
+		}
+		if !m.overlayOpen() && m.onProjectsTab() && m.updateProjects(action) {
+			return nil
+		}
+		m.runMotion(action, count)
+	}
+	return nil
+}
+
+/**
+ * Leaves visual mode, the outline or the focused link, or closes the
+ * overlay or the open blog post, or hides search highlighting,
+ * whichever comes first.
+ */
+func (m *Model) back() {
+	switch {
+	case m.visual:
+		m.endVisual()
+		return
+	case m.outlineFocus:
+		m.outlineFocus = false
+		return
+	case m.linkFocus >= 0:
+		m.linkFocus = -1
+		m.redrawMatches()
+		return
+	case m.showLink:
+		m.closeLinkInfo()
+		return
+	case m.overlayOpen():
+		m.closeOverlays()
+	case m.onBlogTab() && m.blogOpen:
+		m.blogOpen = false
+	case m.highlight && len(m.matches) > 0:
+		m.clearHighlight()
+		return
+	default:
+		return
+	}
+	m.updateViewportContent()
+}
+
+/**
+ * Applies a motion.
+ * @param action - Motion to perform
+ * @param count - Count typed before it (0 = none)
+ */
+func (m *Model) runMotion(action keyAction, count int) {
+	times := max(count, 1)
+
+	switch action {
+	case actionDown:
+		m.viewport.ScrollDown(times)
+	case actionUp:
+		m.viewport.ScrollUp(times)
+	case actionHalfDown:
+		for range times {
+			m.viewport.HalfPageDown()
+		}
+	case actionHalfUp:
+		for range times {
+			m.viewport.HalfPageUp()
+		}
+	case actionPageDown:
+		for range times {
+			m.viewport.PageDown()
+		}
+	case actionPageUp:
+		for range times {
+			m.viewport.PageUp()
+		}
+	case actionTop:
+		if count > 0 {
+			m.gotoLine(count - 1)
+			return
+		}
+		m.viewport.GotoTop()
+	case actionBottom:
+		if count > 0 {
+			m.gotoLine(count - 1)
+			return
+		}
+		m.viewport.GotoBottom()
+	case actionNextTab:
+		if count > 0 {
+			m.jumpTab(count - 1)
+			return
+		}
+		m.switchTab(m.activeTab + 1)
+	case actionPrevTab:
+		m.switchTab(m.activeTab - times)
+	case actionJumpTab:
+		m.jumpTab(count - 1)
+	case actionNextHeading:
+		m.jumpHeading(times)
+		return
+	case actionPrevHeading:
+		m.jumpHeading(-times)
+		return
+	case actionCenter:
+		m.viewport.SetYOffset(m.cursorLine - m.viewport.Height/2)
+		return
+	}
+	m.cursorLine = m.viewport.YOffset
+}
+
+/**
+ * Scrolls so a line is at the top and makes it the cursor line.
+ */
+func (m *Model) gotoLine(line int) {
+	line = min(max(line, 0), max(m.viewport.TotalLineCount()-1, 0))
+	m.viewport.SetYOffset(line)
+	m.cursorLine = line
+}
+
+/**
+ * Moves between headings, or between paragraphs when the view has none.
+ * @param n - Headings to move; negative moves up
+ */
+func (m *Model) jumpHeading(n int) {
+	line := m.cursorLine
+	if line < m.viewport.YOffset || line >= m.viewport.YOffset+m.viewport.Height {
+		line = m.viewport.YOffset
+	}
+
+	for ; n > 0; n-- {
+		next := -1
+		for _, l := range m.jumpLines {
+			if l > line {
+				next = l
+				break
+			}
+		}
+		if next < 0 {
+			break
+		}
+		line = next
+	}
+	for ; n < 0; n++ {
+		prev := -1
+		for _, l := range m.jumpLines {
+			if l < line {
+				prev = l
+			}
+		}
+		if prev < 0 {
+			line = 0
+			break
+		}
+		line = prev
+	}
+	m.gotoLine(line)
+}
+
+/**
+ * Switches to a tab by number, ignoring numbers past the last tab.
+ * @param i - 0-based tab index
+ */
+func (m *Model) jumpTab(i int) {
+	if i >= 0 && i < len(m.tabs) {
+		m.switchTab(i)
+	}
+}
+
+/**
+ * Switches tab, wrapping around at either end, and closes any overlay.
+ * @param i - Tab index, possibly out of range
+ */
+func (m *Model) switchTab(i int) {
+	if len(m.tabs) == 0 {
+		return
+	}
+	m.activeTab = ((i % len(m.tabs)) + len(m.tabs)) % len(m.tabs)
+	m.closeOverlays()
+	m.updateViewportContent()
+}
+
+func init() {
+	registerCommand(command{
+		name:  "tab",
+		usage: "<name|number>",
+		help:  "Switch to a tab by name, name prefix or number",
+		complete: func(m Model) []string {
+			var names []string
+			for _, tab := range m.tabs {
+				names = append(names, strings.ToLower(tab.Name))
+			}
+			return names
+		},
+		run: func(m *Model, arg string) (string, tea.Cmd, error) {
+			i, err := m.findTab(arg)
+			if err != nil {
+				return "", nil, err
+			}
+			m.switchTab(i)
+			return "", nil, nil
+		},
+	})
+
+	registerCommand(command{
+		name:  "goto",
+		usage: "<heading>",
+		help:  "Jump to a heading on the current page",
+		complete: func(m Model) []string {
+			var titles []string
+			for _, h := range m.currentHeadings() {
+				titles = append(titles, h.Title)
+			}
+			return titles
+		},
+		run: func(m *Model, arg string) (string, tea.Cmd, error) {
+			line, err := m.findHeading(arg)
+			if err != nil {
+				return "", nil, err
+			}
+			m.gotoLine(line)
+			return "", nil, nil
+		},
+	})
+}
+
+/**
+ * Finds a tab by 1-based number, name, or unambiguous name prefix,
+ * ignoring case.
+ * @return Tab index, and an error if there is no single match
+ */
+func (m Model) findTab(arg string) (int, error) {
+	if arg == "" {
+		return 0, fmt.Errorf("usage: :tab <name|number>")
+	}
+	if n, err := strconv.Atoi(arg); err == nil {
+		if n < 1 || n > len(m.tabs) {
+			return 0, fmt.Errorf("no tab %d", n)
+		}
+		return n - 1, nil
+	}
+
+	match := -1
+	for i, tab := range m.tabs {
+		name := strings.ToLower(tab.Name)
+		if name == strings.ToLower(arg) {
+			return i, nil
+		}
+		if strings.HasPrefix(name, strings.ToLower(arg)) {
+			if match >= 0 {
+				return 0, fmt.Errorf("ambiguous tab: %s", arg)
+			}
+			match = i
+		}
+	}
+	if match < 0 {
+		return 0, fmt.Errorf("no tab named %s", arg)
+	}
+	return match, nil
+}
+
+/**
+ * Finds the line of a heading on the current page by title or title
+ * prefix, ignoring case. An exact title wins over a prefix.
+ * @return Line in the viewport content, and an error if not found
+ */
+func (m Model) findHeading(arg string) (int, error) {
+	if arg == "" {
+		return 0, fmt.Errorf("usage: :goto <heading>")
+	}
+	headings := m.currentHeadings()
+	if len(headings) == 0 {
+		return 0, fmt.Errorf("no headings here")
+	}
+
+	prefix := -1
+	for i, h := range headings {
+		if m.headingRows[i] < 0 {
+			continue
+		}
+		title := strings.ToLower(h.Title)
+		if title == strings.ToLower(arg) {
+			return m.headingRows[i], nil
+		}
+		if prefix < 0 && strings.HasPrefix(title, strings.ToLower(arg)) {
+			prefix = m.headingRows[i]
+		}
+	}
+	if prefix < 0 {
+		return 0, fmt.Errorf("no heading %q", arg)
+	}
+	return prefix, nil
+}
@@ -0,0 +1,222 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+/**
+ * Tests counts, prefixes and fallbacks in the key parser.
+ */
+func TestKeyParser(t *testing.T) {
+	tests := []struct {
+		keys   string
+		action keyAction
+		count  int
+	}{
+		{"j", actionDown, 0},
+		{"5 j", actionDown, 5},
+		{"1 2 k", actionUp, 12},
+		{"g g", actionTop, 0},
+		{"1 0 g g", actionTop, 10},
+		{"G", actionBottom, 0},
+		{"3 g t", actionNextTab, 3},
+		{"g T", actionPrevTab, 0},
+		{"z z", actionCenter, 0},
+		{"2 }", actionNextHeading, 2},
+		{"ctrl+d", actionHalfDown, 0},
+		{"pgup", actionPageUp, 0},
+		{"g j", actionDown, 0}, // Unknown sequence: the key is read afresh
+		{"5 q", actionQuit, 5},
+		{"5 x", actionNone, 0}, // Unbound
+		{"0", actionNone, 0},   // Zero does not start a count
+	}
+
+	for _, tt := range tests {
+		var p keyParser
+		var action keyAction
+		var count int
+		for _, key := range strings.Fields(tt.keys) {
+			action, count = p.feed(key)
+		}
+		if action != tt.action || count != tt.count {
+			t.Errorf("%q: expected action %d count %d, got %d %d", tt.keys, tt.action, tt.count, action, count)
+		}
+		if p.pending() != "" {
+			t.Errorf("%q: expected no pending keys, got %q", tt.keys, p.pending())
+		}
+	}
+}
+
+/**
+ * Tests partial sequences time out, with a lone count jumping to a tab.
+ */
+func TestKeyParser_Timeout(t *testing.T) {
+	var p keyParser
+	p.feed("3")
+	if p.pending() != "3" {
+		t.Errorf("Expected pending 3, got %q", p.pending())
+	}
+	if action, count := p.timeout(p.seq); action != actionJumpTab || count != 3 {
+		t.Errorf("Expected jump to tab 3, got %d %d", action, count)
+	}
+
+	p.feed("2")
+	stale := p.seq
+	p.feed("g")
+	if action, _ := p.timeout(stale); action != actionNone || p.pending() != "2g" {
+		t.Errorf("Expected stale timeout to be ignored, got %d pending %q", action, p.pending())
+	}
+	if action, _ := p.timeout(p.seq); action != actionNone || p.pending() != "" {
+		t.Errorf("Expected g prefix to be dropped, got %d pending %q", action, p.pending())
+	}
+}
+
+/**
+ * Creates a model with three tabs; the first is long and has headings.
+ */
+func motionModel() Model {
+	var lines []string
+	for i := range 100 {
+		if i%20 == 0 {
+			lines = append(lines, fmt.Sprintf("## Section %d", i/20))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	var headings []Heading
+	for i := range 5 {
+		headings = append(headings, Heading{Level: 2, Title: fmt.Sprintf("Section %d", i)})
+	}
+
+	m := NewModel([]Tab{
+		{Name: "Long", Content: strings.Join(lines, "\n"), Headings: headings},
+		{Name: "Two", Content: "two"},
+		{Name: "Three", Content: "three"},
+	}, "test")
+	m.showSplash = false
+	m.SetSize(80, 30)
+	return m
+}
+
+/**
+ * Sends space-separated keys to the model.
+ */
+func typeKeys(m Model, keys string) Model {
+	for _, key := range strings.Fields(keys) {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		switch key {
+		case "pgdown":
+			msg = tea.KeyMsg{Type: tea.KeyPgDown}
+		case "end":
+			msg = tea.KeyMsg{Type: tea.KeyEnd}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		}
+		m = pressKeys(m, msg)
+	}
+	return m
+}
+
+/**
+ * Tests counted scrolling, line jumps and heading jumps.
+ */
+func TestUpdate_Motions(t *testing.T) {
+	m := typeKeys(motionModel(), "5 j")
+	if m.viewport.YOffset != 5 {
+		t.Errorf("Expected 5j to scroll 5 lines, got %d", m.viewport.YOffset)
+	}
+
+	m = typeKeys(m, "g g")
+	if m.viewport.YOffset != 0 {
+		t.Errorf("Expected gg to go to top, got %d", m.viewport.YOffset)
+	}
+
+	m = typeKeys(m, "1 2 G")
+	if m.viewport.YOffset != 11 {
+		t.Errorf("Expected 12G to put line 12 at the top, got %d", m.viewport.YOffset)
+	}
+
+	m = typeKeys(m, "}")
+	if m.viewport.YOffset != 20 {
+		t.Errorf("Expected } to jump to the next heading, got %d", m.viewport.YOffset)
+	}
+	m = typeKeys(m, "2 }")
+	if m.viewport.YOffset != 60 {
+		t.Errorf("Expected 2} to skip a heading, got %d", m.viewport.YOffset)
+	}
+	m = typeKeys(m, "{")
+	if m.viewport.YOffset != 40 {
+		t.Errorf("Expected { to go back a heading, got %d", m.viewport.YOffset)
+	}
+
+	m = typeKeys(m, "z z")
+	if want := 40 - m.viewport.Height/2; m.viewport.YOffset != want {
+		t.Errorf("Expected zz to centre the heading at %d, got %d", want, m.viewport.YOffset)
+	}
+
+	m = typeKeys(m, "end")
+	if !m.viewport.AtBottom() {
+		t.Error("Expected End to go to the bottom")
+	}
+}
+
+/**
+ * Tests gt, gT, counted gt and numeric tab jumps.
+ */
+func TestUpdate_TabMotions(t *testing.T) {
+	m := typeKeys(motionModel(), "g t")
+	if m.activeTab != 1 {
+		t.Errorf("Expected gt to go to tab 2, got %d", m.activeTab)
+	}
+	m = typeKeys(m, "g T g T")
+	if m.activeTab != 2 {
+		t.Errorf("Expected gT to wrap to the last tab, got %d", m.activeTab)
+	}
+	m = typeKeys(m, "1 g t")
+	if m.activeTab != 0 {
+		t.Errorf("Expected 1gt to go to tab 1, got %d", m.activeTab)
+	}
+
+	m = typeKeys(m, "2")
+	if m.activeTab != 0 || !strings.Contains(m.renderHelpBar(), "2") {
+		t.Fatalf("Expected pending count in the help bar, got tab %d", m.activeTab)
+	}
+	updated, _ := m.Update(keyTimeoutMsg{seq: m.keys.seq})
+	m = updated.(Model)
+	if m.activeTab != 1 || m.keys.pending() != "" {
+		t.Errorf("Expected a lone 2 to jump to tab 2, got %d", m.activeTab)
+	}
+
+	m = typeKeys(m, "9")
+	updated, _ = m.Update(keyTimeoutMsg{seq: m.keys.seq})
+	if m = updated.(Model); m.activeTab != 1 {
+		t.Errorf("Expected jump past the last tab to be ignored, got %d", m.activeTab)
+	}
+}
+
+/**
+ * Tests headings are found in rendered content in order, and mapped
+ * through layout steps.
+ */
+func TestHeadingLines(t *testing.T) {
+	content := "\x1b[1m# Intro\x1b[0m\ntext\n\n## Notes\nmore\n## Notes\n"
+	headings := []Heading{{Level: 1, Title: "Intro"}, {Level: 2, Title: "Notes"}, {Level: 2, Title: "Notes"}, {Level: 2, Title: "Missing"}}
+	got := LocateHeadings(content, headings)
+	if fmt.Sprint(got) != "[{1 Intro 1} {2 Notes 4} {2 Notes 6} {2 Missing 0}]" {
+		t.Errorf("Expected lines 1, 4 and 6, got %v", got)
+	}
+
+	// Two lines above the source, then the third line wrapped onto three
+	rows := headingRows(content, headings, 2, nil, lineMap{0, 1, 2, 5, 6, 7, 8, 9})
+	if fmt.Sprint(rows) != "[2 7 9 -1]" {
+		t.Errorf("Expected [2 7 9 -1], got %v", rows)
+	}
+
+	if got := paragraphLines("a\nb\n\n\nc\n \nd"); fmt.Sprint(got) != "[0 4 6]" {
+		t.Errorf("Expected [0 4 6], got %v", got)
+	}
+}
@@ -0,0 +1,465 @@
+package tui
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/adamdeleeuw/ssh-portfolio/internal/qr"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Highlighting of the focused link
+const (
+	linkFocusStart = "\x1b[7m"
+	linkFocusEnd   = "\x1b[27m"
+)
+
+/**
+ * A link in a tab's markdown.
+ */
+type Link struct {
+	Text string // Link text without markdown, as rendered
+	URL  string // External URL, or a content file (relative to the content directory) with an optional #anchor
+}
+
+/**
+ * A link found in the viewport content.
+ */
+type focusLink struct {
+	Link
+	spans []match // Where its text is; two when it wraps onto the next line
+}
+
+/**
+ * Sets whether this session's terminal opens OSC 8 hyperlinks, which
+ * makes URLs the TUI draws clickable.
+ * @param on - Terminal supports OSC 8
+ */
+func (m *Model) SetHyperlinks(on bool) {
+	m.hyperlinks = on
+}
+
+/**
+ * Makes text open a URL when clicked, if the terminal supports it.
+ * @param text - Styled text
+ * @param url - Link target
+ * @return Text wrapped in OSC 8 sequences, or unchanged
+ */
+func (m Model) hyperlink(text, url string) string {
+	if !m.hyperlinks {
+		return text
+	}
+	return ansi.SetHyperlink(url) + text + ansi.ResetHyperlink()
+}
+
+/**
+ * Converts heading text to the anchor links use for it: lower-case,
+ * punctuation dropped, spaces turned into hyphens.
+ */
+func Anchor(text string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(text)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteRune('-')
+		}
+	}
+	return b.String()
+}
+
+/**
+ * Finds the heading a link anchor names. Repeated titles get "-1",
+ * "-2", ... after their anchor, in order.
+ * @return Index into headings, or -1 if none has the anchor
+ */
+func findAnchor(headings []Heading, anchor string) int {
+	counts := make(map[string]int)
+	for i, h := range headings {
+		a := Anchor(h.Title)
+		n := counts[a]
+		counts[a]++
+		if n > 0 {
+			a = fmt.Sprintf("%s-%d", a, n)
+		}
+		if a == anchor {
+			return i
+		}
+	}
+	return -1
+}
+
+/**
+ * Finds the text of each link in rendered content. Links are matched
+ * in order, so repeated texts resolve to successive places; text that
+ * wraps is matched across the end of a line and the start of the next.
+ * @param content - Viewport content
+ * @param links - Links of the page, in document order
+ * @return Links that were found, in order
+ */
+func locateLinks(content string, links []Link) []focusLink {
+	if len(links) == 0 {
+		return nil
+	}
+	lines := strings.Split(ansi.Strip(content), "\n")
+	var out []focusLink
+	line, col := 0, 0 // Where the search for the next link starts
+
+	for _, l := range links {
+		if l.Text == "" {
+			continue
+		}
+		for i := line; i < len(lines); i++ {
+			from := 0
+			if i == line {
+				from = col
+			}
+			spans, end, ok := findLinkText(lines, i, from, l.Text)
+			if ok {
+				out = append(out, focusLink{Link: l, spans: spans})
+				line, col = spans[len(spans)-1].line, end
+				break
+			}
+		}
+	}
+	return out
+}
+
+/**
+ * Looks for link text on a line, whole or wrapped onto the next line.
+ * @param lines - Content without escape sequences
+ * @param i - Line to look on
+ * @param from - First rune of the line to consider
+ * @return Spans of the text, the rune after it on its last line, and
+ *         false if it is not there
+ */
+func findLinkText(lines []string, i, from int, text string) ([]match, int, bool) {
+	runes := []rune(lines[i])
+	if from > len(runes) {
+		return nil, 0, false
+	}
+	rest := string(runes[from:])
+	if at := strings.Index(rest, text); at >= 0 {
+		start := from + utf8.RuneCountInString(rest[:at])
+		end := start + utf8.RuneCountInString(text)
+		return []match{{line: i, start: start, end: end}}, end, true
+	}
+
+	// Wrapped: the line ends with the start of the text, the next line
+	// (after its indent) starts with the rest
+	if i+1 >= len(lines) {
+		return nil, 0, false
+	}
+	trimmed := strings.TrimRight(rest, " ")
+	next := []rune(lines[i+1])
+	indent := len(next) - len([]rune(strings.TrimLeft(lines[i+1], " ")))
+	for cut := len(text) - 1; cut > 0; cut-- {
+		head, tail := strings.TrimRight(text[:cut], " "), strings.TrimLeft(text[cut:], " ")
+		if head == "" || tail == "" || !utf8.ValidString(head) || !strings.HasSuffix(trimmed, head) {
+			continue
+		}
+		if !strings.HasPrefix(string(next[indent:]), tail) {
+			continue
+		}
+		end := from + utf8.RuneCountInString(trimmed)
+		tailEnd := indent + utf8.RuneCountInString(tail)
+		return []match{
+			{line: i, start: end - utf8.RuneCountInString(head), end: end},
+			{line: i + 1, start: indent, end: tailEnd},
+		}, tailEnd, true
+	}
+	return nil, 0, false
+}
+
+/**
+ * Returns the links of the page in the viewport: the tab's, the open
+ * post's, or the repositories of the listed projects.
+ */
+func (m Model) currentLinks() []Link {
+	if m.overlayOpen() || m.activePanel() != nil || m.activeTab < 0 || m.activeTab >= len(m.tabs) {
+		return nil
+	}
+	if m.onBlogTab() {
+		post, _ := m.openPost()
+		return post.Links
+	}
+	if m.onProjectsTab() {
+		var links []Link
+		for _, p := range filterProjects(m.tabs[m.activeTab].Projects, m.currentProjectTag(), m.projectOldestFirst) {
+			if p.Repo != "" {
+				links = append(links, Link{Text: p.Repo, URL: p.Repo})
+			}
+		}
+		return links
+	}
+	return m.tabs[m.activeTab].Links
+}
+
+/**
+ * Returns the focused link.
+ * @return Link, and false if none has focus
+ */
+func (m Model) focusedLink() (focusLink, bool) {
+	if m.linkFocus < 0 || m.linkFocus >= len(m.links) {
+		return focusLink{}, false
+	}
+	return m.links[m.linkFocus], true
+}
+
+/**
+ * Moves the focus to a later (or earlier) link, wrapping at either end
+ * of the page. Without a focused link in view, it starts from the top
+ * (or bottom) of the view.
+ * @param n - Links to move; negative moves up
+ */
+func (m *Model) moveLinkFocus(n int) {
+	if len(m.links) == 0 {
+		return
+	}
+	m.outlineFocus = false
+
+	index := m.linkFocus
+	if l, ok := m.focusedLink(); !ok || !m.lineInView(l.spans[0].line) {
+		top, bottom := m.viewport.YOffset, m.viewport.YOffset+m.viewport.Height
+		index = -1
+		if n > 0 {
+			for i := len(m.links) - 1; i >= 0; i-- {
+				if m.links[i].spans[0].line >= top {
+					index = i
+				}
+			}
+			n--
+		} else {
+			for i, l := range m.links {
+				if l.spans[0].line < bottom {
+					index = i
+				}
+			}
+			n++
+		}
+		if index < 0 {
+			index = 0
+		}
+	}
+	index = ((index+n)%len(m.links) + len(m.links)) % len(m.links)
+	m.focusOn(index)
+}
+
+/**
+ * Focuses a link and scrolls it into view.
+ * @param index - Index in m.links
+ */
+func (m *Model) focusOn(index int) {
+	m.linkFocus = index
+	m.redrawMatches()
+	line := m.links[index].spans[0].line
+	if !m.lineInView(line) || !m.lineInView(m.links[index].spans[len(m.links[index].spans)-1].line) {
+		m.viewport.SetYOffset(line - m.viewport.Height/2)
+	}
+	m.cursorLine = line
+}
+
+/**
+ * Reports whether a line of the viewport content is on screen.
+ */
+func (m Model) lineInView(line int) bool {
+	return line >= m.viewport.YOffset && line < m.viewport.YOffset+m.viewport.Height
+}
+
+/**
+ * Highlights the focused link.
+ * @param content - Viewport content, possibly with search highlighting
+ * @return Content to show
+ */
+func (m Model) highlightLink(content string) string {
+	l, ok := m.focusedLink()
+	if !ok {
+		return content
+	}
+	lines := strings.Split(content, "\n")
+	for _, s := range l.spans {
+		if s.line < len(lines) {
+			lines[s.line] = markSpans(lines[s.line], []match{s}, func(int) (string, string) {
+				return linkFocusStart, linkFocusEnd
+			})
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+/**
+ * Follows the focused link if it is on screen.
+ * @return false if no link is focused in view
+ */
+func (m *Model) followFocusedLink() bool {
+	l, ok := m.focusedLink()
+	if !ok || !m.lineInView(l.spans[0].line) {
+		return false
+	}
+	m.followLink(l.Link)
+	return true
+}
+
+/**
+ * Follows a link: internal links open their tab (or post) at the
+ * anchor, external ones show their URL with copy and open actions.
+ * @effects Shows an error in the status area if the page or anchor
+ *          does not exist
+ */
+func (m *Model) followLink(l Link) {
+	if isExternal(l.URL) {
+		m.openLinkInfo(l)
+		return
+	}
+
+	file, anchor, _ := strings.Cut(l.URL, "#")
+	if file == "" {
+		file = m.tabs[m.activeTab].File
+		if post, ok := m.openPost(); ok {
+			file = post.File
+		}
+	}
+
+	e, ok := m.linkEntry(file)
+	if !ok {
+		m.message, m.messageErr = fmt.Sprintf("%s is not a page here", file), true
+		return
+	}
+	headings := m.tabs[e.tab].Headings
+	if e.post >= 0 {
+		headings = m.tabs[e.tab].Posts[e.post].Headings
+	}
+	found := anchor == ""
+	if j := findAnchor(headings, anchor); j >= 0 && !found {
+		e.kind, e.heading, found = "heading", j, true
+	}
+	for _, p := range m.tabs[e.tab].Projects {
+		if !found && Anchor(p.Name) == anchor {
+			e.kind, e.title, found = "project", p.Name, true
+		}
+	}
+
+	m.jumpToEntry(e)
+	if !found {
+		m.message, m.messageErr = fmt.Sprintf("no heading #%s on %s", anchor, m.tabs[e.tab].Name), true
+	}
+}
+
+/**
+ * Whether a link leads to another site rather than a content file.
+ */
+func isExternal(link string) bool {
+	u, err := url.Parse(link)
+	return err != nil || u.Scheme != "" || strings.HasPrefix(link, "//")
+}
+
+/**
+ * Finds the tab or post rendered from a content file.
+ * @return Finder entry for the page, and false if no tab or post has it
+ */
+func (m Model) linkEntry(file string) (finderEntry, bool) {
+	if file == "" {
+		return finderEntry{}, false
+	}
+	for i, tab := range m.tabs {
+		if tab.File == file {
+			return finderEntry{kind: "page", title: tab.Name, tab: i, heading: -1, post: -1}, true
+		}
+		for j, p := range tab.Posts {
+			if p.File == file {
+				return finderEntry{kind: "post", title: p.Title, tab: i, heading: -1, post: j}, true
+			}
+		}
+	}
+	return finderEntry{}, false
+}
+
+/**
+ * Shows an external link's URL, keeping the page's place to return to.
+ */
+func (m *Model) openLinkInfo(l Link) {
+	offset, focus := m.viewport.YOffset, m.linkFocus
+	m.closeOverlays()
+	m.showLink, m.openLink, m.linkQR = true, l, false
+	m.updateViewportContent()
+	m.linkOffset, m.linkIndex = offset, focus
+}
+
+/**
+ * Closes the link overlay, back where the page was with the link
+ * still focused.
+ */
+func (m *Model) closeLinkInfo() {
+	m.closeOverlays()
+	m.updateViewportContent()
+	m.viewport.SetYOffset(m.linkOffset)
+	m.cursorLine = m.viewport.YOffset
+	if m.linkIndex >= 0 && m.linkIndex < len(m.links) {
+		m.linkFocus = m.linkIndex
+		m.redrawMatches()
+	}
+}
+
+/**
+ * Handles the link overlay's own key: o shows or hides a QR code of
+ * the URL. The yank key copies it.
+ * @param msg - Key press
+ * @return true if the key was consumed
+ */
+func (m *Model) updateLinkInfo(msg tea.KeyMsg) bool {
+	switch msg.String() {
+	case "o":
+		m.linkQR = !m.linkQR
+		m.refreshView()
+	default:
+		return false
+	}
+	return true
+}
+
+/**
+ * Renders the link overlay: the link's text and full URL, its actions
+ * and, when asked for, a QR code to open it on a phone.
+ * @return Styled overlay for the viewport
+ */
+func (m Model) renderLinkInfo() string {
+	l := m.openLink
+	var lines []string
+	lines = append(lines, "  "+m.styles.projectTitle.Render(l.Text), "")
+	for _, part := range strings.Split(ansi.Hardwrap(l.URL, max(m.viewport.Width-2, 10), false), "\n") {
+		lines = append(lines, "  "+m.hyperlink(m.styles.projectLink.Render(part), l.URL))
+	}
+
+	what, qrAction := "URL", "Show a QR code to open it on your phone"
+	if strings.HasPrefix(l.URL, "mailto:") {
+		what = "address"
+	}
+	if m.linkQR {
+		qrAction = "Hide the QR code"
+	}
+	keys := m.styles.pendingKeys
+	lines = append(lines, "",
+		"  "+keys.Render(m.keyChoice(actionYank))+"  Copy the "+what+" to your clipboard",
+		"  "+keys.Render("o")+"  "+qrAction,
+		"  "+keys.Render(m.keyChoice(actionBack))+"  Back to the page")
+
+	if m.linkQR {
+		lines = append(lines, "")
+		code, err := qr.Encode(l.URL, qr.Medium)
+		if err != nil {
+			return strings.Join(append(lines, m.styles.imageCaption.Render("  The URL is too long for a QR code")), "\n")
+		}
+		qrc := QRCode{Label: l.Text, Text: l.URL, Code: code}
+		drawn := drawQRCode(qrc, m.viewport.Width, m.viewport.Height, true)
+		if drawn == nil {
+			drawn = m.qrFallback(qrc)
+		}
+		lines = append(lines, drawn...)
+	}
+	return strings.Join(lines, "\n")
+}
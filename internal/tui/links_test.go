@@ -0,0 +1,105 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+/**
+ * Tests link text is found in order, including text wrapped onto the
+ * next line.
+ */
+func TestLocateLinks(t *testing.T) {
+	content := "Read the \x1b[1mdocs\x1b[0m /docs.md or the docs\n  \x1b[1mGitHub\x1b[0m\n  Repo https://github.com/x"
+	links := []Link{{Text: "docs", URL: "docs.md"}, {Text: "GitHub Repo", URL: "https://github.com/x"}, {Text: "missing", URL: "x.md"}}
+
+	got := locateLinks(content, links)
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 links, got %+v", got)
+	}
+	if s := got[0].spans; len(s) != 1 || s[0] != (match{line: 0, start: 9, end: 13}) {
+		t.Errorf("Expected docs at 0:9-13, got %+v", s)
+	}
+	want := []match{{line: 1, start: 2, end: 8}, {line: 2, start: 2, end: 6}}
+	if s := got[1].spans; len(s) != 2 || s[0] != want[0] || s[1] != want[1] {
+		t.Errorf("Expected GitHub Repo across lines 1 and 2, got %+v", s)
+	}
+}
+
+/**
+ * Tests Tab moves the focus between links, Enter follows internal
+ * links to their anchor and shows external ones.
+ */
+func TestLinks(t *testing.T) {
+	var lines []string
+	for range 40 {
+		lines = append(lines, "filler")
+	}
+	projects := "## Intro\n" + strings.Join(lines, "\n") + "\n## SSH Portfolio\n" + strings.Join(lines, "\n")
+	m := NewModel([]Tab{
+		{Name: "Home", File: "home.md", Content: "See my [project] and the [repo].\nNo links here.",
+			Links: []Link{{Text: "project", URL: "projects.md#ssh-portfolio"}, {Text: "repo", URL: "https://github.com/x"}}},
+		{Name: "Projects", File: "projects.md", Content: projects,
+			Headings: []Heading{{Level: 2, Title: "Intro"}, {Level: 2, Title: "SSH Portfolio"}}},
+	}, "test")
+	m.showSplash = false
+	m.SetSize(80, 30)
+	tab := tea.KeyMsg{Type: tea.KeyTab}
+
+	m = pressKeys(m, tab)
+	if m.linkFocus != 0 || !strings.Contains(m.viewport.View(), "["+linkFocusStart+"project"+linkFocusEnd+"]") {
+		t.Fatalf("Expected the first link focused, got %d", m.linkFocus)
+	}
+	m = pressKeys(m, tab, tab)
+	if m.linkFocus != 0 || m.activeTab != 0 {
+		t.Errorf("Expected Tab to wrap to the first link, got %d", m.linkFocus)
+	}
+	m = typeKeys(m, "[ l")
+	if m.linkFocus != 1 {
+		t.Errorf("Expected [l to wrap to the last link, got %d", m.linkFocus)
+	}
+
+	m = typeKeys(m, "y")
+	if m.toast != "Copied https://github.com/x" {
+		t.Errorf("Expected y to copy the focused link, got %q", m.toast)
+	}
+
+	// External links show their URL with copy and QR code actions
+	m = pressKeys(m, keyEnter)
+	if !m.showLink || !strings.Contains(ansi.Strip(m.content), "https://github.com/x") {
+		t.Fatalf("Expected the URL shown, got %q", ansi.Strip(m.content))
+	}
+	m = typeKeys(m, "y")
+	if !strings.Contains(m.clipboard, "\x1b]52;") || m.toast != "Copied https://github.com/x" {
+		t.Errorf("Expected the URL copied, got %q", m.toast)
+	}
+	m = typeKeys(m, "o")
+	if !m.linkQR || !strings.Contains(m.content, "Hide the QR code") {
+		t.Error("Expected o to show a QR code")
+	}
+	m = pressKeys(m, keyEsc)
+	if m.showLink || m.linkFocus != 1 {
+		t.Errorf("Expected Esc to return to the focused link, got %d", m.linkFocus)
+	}
+
+	// Internal links open the tab at the anchor
+	m = pressKeys(m, tea.KeyMsg{Type: tea.KeyShiftTab}, keyEnter)
+	if m.activeTab != 1 || !strings.HasPrefix(ansi.Strip(m.viewport.View()), "## SSH Portfolio") {
+		t.Errorf("Expected Projects at SSH Portfolio, got tab %d", m.activeTab)
+	}
+
+	// Pages without links switch tab
+	m = pressKeys(m, tab)
+	if m.activeTab != 0 || m.linkFocus != -1 {
+		t.Errorf("Expected Tab to switch tab on a page without links, got tab %d", m.activeTab)
+	}
+	m.tabs[0].Links[0].URL = "missing.md"
+	m.updateViewportContent()
+	m = pressKeys(m, tab, keyEnter)
+	if !m.messageErr || m.activeTab != 0 {
+		t.Errorf("Expected an error for a missing page, got %q", m.message)
+	}
+}
@@ -3,6 +3,8 @@ package tui
 import (
 	"time"
 
+	"github.com/adamdeleeuw/ssh-portfolio/internal/termimg"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -11,8 +13,18 @@ import (
  * Represents a single tab in the portfolio.
  */
 type Tab struct {
-	Name    string
-	Content string
+	Name     string
+	Content  string
+	Projects []Project         // Structured catalogue; rendered as cards instead of Content when set
+	Posts    []Post            // Blog posts; rendered as a paginated list instead of Content when set
+	Source   string            // Markdown the tab was built from, before templating; used to detect changes
+	File     string            // Content file the tab was built from, for internal links ("" = none)
+	Headings []Heading         // Headings in Content, for { and } (nil = jump between paragraphs)
+	Links    []Link            // Links in Content, in order, for keyboard focus
+	Images   map[string]Image  // Images in Content, keyed by placeholder line
+	QRCodes  map[string]QRCode // QR codes in Content, keyed by placeholder line
+	Contact  *QRCode           // Shown full size with the c key (nil = none)
+	Panel    Panel             // Produces the content at runtime instead of Content when set
 }
 
 /**
@@ -29,6 +41,92 @@ type Model struct {
 	showSplash bool           // Show splash screen animation
 	startTime  time.Time      // Server start time for uptime
 	sessionID  string         // Unique session identifier
+
+	contentVersion string // Commit the content was loaded from ("" if not from git)
+	notice         string // Warning shown in the stats bar, e.g. degraded content
+
+	changes      []TabChange     // Tabs changed since the visitor's previous session
+	changesSince time.Time       // Start of the previous session
+	seenTabs     map[string]bool // Changed tabs opened this session (badge cleared)
+	whatsNew     bool            // Showing the "What's new" view instead of the tab
+	showContact  bool            // Showing the tab's contact QR code instead of the tab
+	showKeys     bool            // Showing the key help instead of the tab
+
+	theme       Theme       // Colour scheme of this session
+	styles      *styles     // Styles of the theme
+	themeLoader ThemeLoader // Re-renders the tabs for a theme (nil = chrome only)
+	wrap        bool        // Wrap long lines to the viewport (":set wrap")
+
+	imageProtocol  termimg.Protocol // How this client draws images
+	hyperlinks     bool             // Client opens OSC 8 hyperlinks
+	splashImage    *Image           // Replaces the ASCII logo when set
+	placed         []placedImage    // Graphics images in the current viewport content
+	viewGeneration int              // Bumped whenever the viewport content is replaced
+	content        string           // Current viewport content, for commands that read it
+
+	projectTag         int  // Tech filter position on the projects tab (0 = all)
+	projectOldestFirst bool // Sort projects oldest first instead of newest first
+
+	blogCursor int  // Selected post in the filtered blog list
+	blogTag    int  // Tag filter position on the blog tab (0 = all)
+	blogOpen   bool // Showing the selected post instead of the list
+
+	keys       keyParser // Partial Vim key sequence (count, g or z prefix)
+	cursorLine int       // Line the last motion landed on; zz centres it
+	jumpLines  []int     // Heading (or paragraph) lines in the viewport content, for { and }
+
+	headingRows   []int // Viewport line of each of currentHeadings (-1 = not found)
+	outline       bool  // Show the outline sidebar on pages with headings
+	outlineSet    bool  // The visitor chose whether to show the outline
+	outlineFocus  bool  // Keys move the outline's selection
+	outlineCursor int   // Selected heading while the outline has focus
+
+	cmdline       textinput.Model // Command and search line
+	cmdlineOpen   bool            // Typing a command or search
+	cmdPrompt     prompt          // What the open line is for
+	cmdHistory    []string        // Commands run this session, oldest first
+	searchHistory []string        // Searches made this session, oldest first
+	cmdHistoryPos int             // Position while walking the history (len = new line)
+	message       string          // Output of the last command, shown in the stats bar
+	messageErr    bool            // Whether message is an error
+	clipboard     string          // OSC 52 sequence sent with frames until the toast times out
+	toast         string          // Confirmation shown above the stats bar until it times out
+	toastSeq      int             // Bumped on every toast so stale timeouts are ignored
+
+	search      string      // Pattern of the last search ("" = none)
+	searchBack  bool        // Whether the last search went up the page
+	highlight   bool        // Whether matches of search are highlighted
+	matches     []match     // Matches of search in the viewport content
+	matchIndex  int         // Current match (-1 = none yet)
+	results     []searchHit // Hits of a search across all tabs
+	resultIndex int         // Selected hit
+	showResults bool        // Showing the hits instead of the tab
+
+	finder        textinput.Model // Fuzzy finder input
+	showFinder    bool            // Showing the fuzzy finder instead of the tab
+	finderEntries []finderEntry   // Pages, headings, projects and posts to choose from
+	finderHits    []finderHit     // Entries matching the input, best first
+	finderCursor  int             // Selected hit
+
+	links      []focusLink // Links in the viewport content, for Tab and Enter
+	linkFocus  int         // Focused link (-1 = none)
+	showLink   bool        // Showing an external link's URL instead of the tab
+	openLink   Link        // Link shown by showLink
+	linkQR     bool        // Show a QR code of openLink
+	linkOffset int         // Scroll position to return to when the link overlay closes
+	linkIndex  int         // Link to focus again when the link overlay closes
+
+	visual       bool // Selecting lines to copy
+	visualAnchor int  // Line the selection started on
+	visualCursor int  // Line the selection extends to
+
+	mouse    bool // Terminal reports clicks and the wheel (cell motion mode); off so text selection works
+	dragging bool // Dragging the scrollbar thumb
+
+	started map[Panel]bool // Panels whose tab has been opened
+
+	reload     Reloader  // Rebuilds tabs when scheduled content changes
+	nextReload time.Time // When reload should next run (zero = never)
 }
 
 /**
@@ -39,7 +137,7 @@ type Model struct {
  */
 func NewModel(tabs []Tab, sessionID string) Model {
 	vp := viewport.New(80, 20)
-	vp.MouseWheelEnabled = false // SSH doesn't support mouse
+	vp.MouseWheelEnabled = false // The wheel is handled with the rest of the mouse in updateMouse
 
 	return Model{
 		activeTab:  0,
@@ -48,17 +146,23 @@ func NewModel(tabs []Tab, sessionID string) Model {
 		ready:      false,
 		showHelp:   true,
 		showSplash: true, // Start with splash screen
+		theme:      themes[0],
+		styles:     themeStyles[themes[0].Name],
 		startTime:  time.Now(),
 		sessionID:  sessionID,
+		keys:       keyParser{keymap: activeKeymaps.Load().initial},
+		cmdline:    newCmdline(),
+		started:    make(map[Panel]bool),
 	}
 }
 
 /**
  * Initializes the Bubble Tea program.
- * @return Initial command to start splash timer
+ * @return Initial commands to start the splash and reload timers and
+ *         the first tab's panel, and to set mouse reporting
  */
 func (m Model) Init() tea.Cmd {
-	return splashTimer()
+	return tea.Batch(splashTimer(), m.scheduleReload(), m.startPanel(), m.mouseCmd())
 }
 
 /**
@@ -84,10 +188,31 @@ func (m *Model) SetSize(width, height int) {
 	m.viewport.Width = width - 4
 	m.viewport.Height = height - headerHeight - tabHeight - statsHeight - helpHeight - 2
 
+	// Wide terminals show the outline unless the visitor turned it off
+	if !m.outlineSet {
+		m.outline = width >= outlineAutoWidth
+	}
+
 	// Set initial content
 	m.updateViewportContent()
 }
 
+/**
+ * Sets the content version shown in the stats bar.
+ * @param version - Short commit description, or "" to hide it
+ */
+func (m *Model) SetContentVersion(version string) {
+	m.contentVersion = version
+}
+
+/**
+ * Sets a warning shown in the stats bar for the whole session.
+ * @param notice - Short message, or "" to hide it
+ */
+func (m *Model) SetNotice(notice string) {
+	m.notice = notice
+}
+
 /**
  * Message sent when splash screen timer completes.
  */
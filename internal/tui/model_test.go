@@ -2,6 +2,7 @@ package tui
 
 import (
 	"testing"
+	"time"
 )
 
 /**
@@ -31,3 +32,29 @@ func TestNewModel(t *testing.T) {
 		t.Error("Expected showHelp to be true by default")
 	}
 }
+
+/**
+ * Tests a scheduled reload swaps tabs but stays on the current tab.
+ */
+func TestModel_Reload(t *testing.T) {
+	m := NewModel([]Tab{{Name: "Welcome"}, {Name: "About"}}, "test")
+	m.activeTab = 1
+
+	m.SetReloader(func() ([]Tab, time.Time, error) {
+		return []Tab{{Name: "Welcome"}, {Name: "Banner"}, {Name: "About"}}, time.Time{}, nil
+	}, time.Now())
+
+	if m.scheduleReload() == nil {
+		t.Fatal("Expected a reload to be scheduled")
+	}
+
+	updatedModel, cmd := m.Update(reloadMsg{})
+	m = updatedModel.(Model)
+
+	if len(m.tabs) != 3 || m.tabs[m.activeTab].Name != "About" {
+		t.Errorf("Expected to stay on About after reload, got tab %d of %d", m.activeTab, len(m.tabs))
+	}
+	if cmd != nil {
+		t.Error("Expected no further reload when nothing else is scheduled")
+	}
+}
@@ -0,0 +1,227 @@
+package tui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+/**
+ * Turns mouse reporting on or off. While it is on, the terminal sends
+ * clicks and the wheel to the portfolio instead of selecting text.
+ * @return Command telling the terminal, and hiding the toast
+ */
+func (m *Model) toggleMouse() tea.Cmd {
+	m.mouse, m.dragging = !m.mouse, false
+	text := "Mouse on: click tabs and links, drag the scrollbar"
+	if !m.mouse {
+		text = "Mouse off: your terminal selects text again"
+	}
+	return tea.Batch(m.mouseCmd(), m.showToast(text))
+}
+
+/**
+ * Sets whether the session starts with the mouse on. It is off by
+ * default so the terminal keeps selecting text.
+ * @param on - Report clicks and the wheel
+ */
+func (m *Model) SetMouse(on bool) {
+	m.mouse = on
+}
+
+/**
+ * Command that puts the terminal's mouse reporting in the session's mode.
+ */
+func (m Model) mouseCmd() tea.Cmd {
+	if m.mouse {
+		return tea.EnableMouseCellMotion
+	}
+	return tea.DisableMouse
+}
+
+/**
+ * Handles a mouse event: clicks on tabs, links and the scrollbar,
+ * dragging the scrollbar and the wheel.
+ * @param msg - Mouse event
+ * @effects Switches tab, scrolls or follows a link
+ */
+func (m *Model) updateMouse(msg tea.MouseMsg) {
+	if !m.mouse {
+		return
+	}
+	if m.showSplash {
+		// Clicking skips the splash screen like any key
+		if msg.Action == tea.MouseActionPress {
+			m.showSplash = false
+		}
+		return
+	}
+
+	row := msg.Y - m.viewportRow()
+	switch {
+	case msg.Action == tea.MouseActionRelease:
+		m.dragging = false
+	case msg.Action == tea.MouseActionMotion:
+		if m.dragging {
+			m.dragScrollbar(row)
+		}
+	case msg.Button == tea.MouseButtonWheelUp:
+		m.viewport.ScrollUp(m.viewport.MouseWheelDelta)
+		m.cursorLine = m.viewport.YOffset
+	case msg.Button == tea.MouseButtonWheelDown:
+		m.viewport.ScrollDown(m.viewport.MouseWheelDelta)
+		m.cursorLine = m.viewport.YOffset
+	case msg.Button != tea.MouseButtonLeft:
+	case msg.Y == m.tabBarRow():
+		if i := m.tabAt(msg.X); i >= 0 && i != m.activeTab {
+			m.switchTab(i)
+		}
+	case row < 0 || row >= m.viewport.Height:
+	case m.scrollbarVisible() && msg.X == m.scrollbarCol():
+		m.dragging = true
+		m.dragScrollbar(row)
+	default:
+		if i := m.linkAt(msg.X, m.viewport.YOffset+row); i >= 0 {
+			m.linkFocus = i
+			m.redrawMatches()
+			m.followFocusedLink()
+		}
+	}
+}
+
+/**
+ * Screen row of the tabs, below the header and a blank line.
+ */
+func (m Model) tabBarRow() int {
+	return lipgloss.Height(m.renderHeader()) + 1
+}
+
+/**
+ * Screen row of the first viewport line, below the tab bar and a
+ * blank line.
+ */
+func (m Model) viewportRow() int {
+	return m.tabBarRow() + lipgloss.Height(m.renderTabBar()) + 1
+}
+
+/**
+ * Finds the tab drawn at a column of the tab bar.
+ * @return Tab index, or -1 if the column is between or past the tabs
+ */
+func (m Model) tabAt(x int) int {
+	col := m.styles.tabBar.GetPaddingLeft()
+	for i, label := range m.tabLabels() {
+		w := lipgloss.Width(label)
+		if x >= col && x < col+w {
+			return i
+		}
+		col += w
+	}
+	return -1
+}
+
+/**
+ * Finds the link whose text is at a cell of the viewport content.
+ * @param x - Screen column
+ * @param line - Content line
+ * @return Index into links, or -1 if there is none
+ */
+func (m Model) linkAt(x, line int) int {
+	lines := strings.Split(ansi.Strip(m.content), "\n")
+	if line < 0 || line >= len(lines) {
+		return -1
+	}
+
+	// Columns count cells; link spans count runes
+	col, pos := 0, -1
+	for i, r := range []rune(lines[line]) {
+		col += ansi.StringWidth(string(r))
+		if x < col {
+			pos = i
+			break
+		}
+	}
+	for i, l := range m.links {
+		for _, s := range l.spans {
+			if s.line == line && pos >= s.start && pos < s.end {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+/**
+ * Whether the scrollbar is drawn: the mouse is on and the content is
+ * longer than the view.
+ */
+func (m Model) scrollbarVisible() bool {
+	return m.mouse && m.viewport.TotalLineCount() > m.viewport.Height
+}
+
+/**
+ * Screen column of the scrollbar, one space right of the viewport.
+ */
+func (m Model) scrollbarCol() int {
+	return m.viewport.Width + 1
+}
+
+/**
+ * Places the scrollbar's thumb: its size shows how much of the content
+ * is in view, its position how far down the view is.
+ * @return First row and height of the thumb
+ */
+func (m Model) scrollThumb() (int, int) {
+	height, total := m.viewport.Height, m.viewport.TotalLineCount()
+	if total <= height {
+		return 0, height
+	}
+	size := max(height*height/total, 1)
+	return m.viewport.YOffset * (height - size) / (total - height), size
+}
+
+/**
+ * Scrolls so the thumb follows the mouse: the top row shows the top
+ * of the content and the bottom row its end.
+ * @param row - Viewport row the mouse is on, possibly outside it
+ */
+func (m *Model) dragScrollbar(row int) {
+	height, total := m.viewport.Height, m.viewport.TotalLineCount()
+	if height < 2 || total <= height {
+		return
+	}
+	row = min(max(row, 0), height-1)
+	m.viewport.SetYOffset((row*(total-height) + (height-1)/2) / (height - 1))
+	m.cursorLine = m.viewport.YOffset
+}
+
+/**
+ * Renders the scrollbar beside the viewport.
+ * @return Column as tall as the viewport
+ */
+func (m Model) renderScrollbar() string {
+	start, size := m.scrollThumb()
+	lines := make([]string, m.viewport.Height)
+	for i := range lines {
+		if i >= start && i < start+size {
+			lines[i] = m.styles.scrollThumb.Render("┃")
+		} else {
+			lines[i] = m.styles.scrollTrack.Render("│")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func init() {
+	registerOption(option{
+		name: "mouse",
+		help: "Use the mouse for tabs, links and scrolling; off lets the terminal select text",
+		get:  func(m Model) bool { return m.mouse },
+		set: func(m *Model, on bool) {
+			m.mouse, m.dragging = on, false
+		},
+		cmd: Model.mouseCmd,
+	})
+}
@@ -0,0 +1,96 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+/**
+ * Sends a mouse event to the model.
+ */
+func mouse(m Model, x, y int, button tea.MouseButton, action tea.MouseAction) Model {
+	updated, _ := m.Update(tea.MouseMsg{X: x, Y: y, Button: button, Action: action})
+	return updated.(Model)
+}
+
+/**
+ * Tests the wheel scrolls, the scrollbar drags and tabs switch on
+ * click, and that the mouse can be turned off.
+ */
+func TestMouse(t *testing.T) {
+	m := motionModel()
+	top := m.viewportRow()
+
+	// Off until asked for, so the terminal selects text
+	m = mouse(m, 10, top+2, tea.MouseButtonWheelDown, tea.MouseActionPress)
+	if m.mouse || m.viewport.YOffset != 0 {
+		t.Fatalf("Expected the mouse off by default, got offset %d", m.viewport.YOffset)
+	}
+	m.SetMouse(true)
+
+	m = mouse(m, 10, top+2, tea.MouseButtonWheelDown, tea.MouseActionPress)
+	if m.viewport.YOffset != 3 {
+		t.Errorf("Expected the wheel to scroll 3 lines, got %d", m.viewport.YOffset)
+	}
+
+	// Drag the thumb to the bottom and back to the top
+	if !strings.Contains(m.View(), "┃") {
+		t.Fatal("Expected a scrollbar beside long content")
+	}
+	x, bottom := m.scrollbarCol(), top+m.viewport.Height-1
+	m = mouse(m, x, bottom, tea.MouseButtonLeft, tea.MouseActionPress)
+	if !m.dragging || !m.viewport.AtBottom() {
+		t.Errorf("Expected a click on the scrollbar's last row to scroll to the end, got %d", m.viewport.YOffset)
+	}
+	m = mouse(m, x, top-5, tea.MouseButtonLeft, tea.MouseActionMotion)
+	m = mouse(m, x, top-5, tea.MouseButtonLeft, tea.MouseActionRelease)
+	if m.dragging || m.viewport.YOffset != 0 {
+		t.Errorf("Expected dragging above the scrollbar to reach the top, got %d", m.viewport.YOffset)
+	}
+
+	// The second tab starts after the first tab's label
+	labels := m.tabLabels()
+	m = mouse(m, m.styles.tabBar.GetPaddingLeft()+lipgloss.Width(labels[0])+1, m.tabBarRow(), tea.MouseButtonLeft, tea.MouseActionPress)
+	if m.activeTab != 1 {
+		t.Errorf("Expected a click on the second tab to open it, got %d", m.activeTab)
+	}
+
+	m = typeKeys(m, "1 m")
+	if m.mouse || m.toast == "" || strings.Contains(m.View(), "┃") {
+		t.Error("Expected m to turn the mouse and scrollbar off")
+	}
+	m = mouse(m, 10, top+2, tea.MouseButtonWheelDown, tea.MouseActionPress)
+	if m.viewport.YOffset != 0 {
+		t.Error("Expected the wheel to be ignored with the mouse off")
+	}
+	if m = runCommand(m, "set mouse"); !m.mouse {
+		t.Error("Expected :set mouse to turn it back on")
+	}
+}
+
+/**
+ * Tests clicking a link follows it.
+ */
+func TestMouse_Links(t *testing.T) {
+	m := NewModel([]Tab{{Name: "Home", Content: "See the [repo] now.",
+		Links: []Link{{Text: "repo", URL: "https://github.com/x"}}}}, "test")
+	m.showSplash = false
+	m.SetMouse(true)
+	m.SetSize(80, 30)
+	if len(m.links) != 1 {
+		t.Fatalf("Expected the link to be found, got %+v", m.links)
+	}
+
+	s := m.links[0].spans[0]
+	m = mouse(m, 0, m.viewportRow()+s.line, tea.MouseButtonLeft, tea.MouseActionPress)
+	if m.showLink {
+		t.Error("Expected a click beside the link to do nothing")
+	}
+	m = mouse(m, s.start+1, m.viewportRow()+s.line, tea.MouseButtonLeft, tea.MouseActionPress)
+	if !m.showLink || m.openLink.URL != "https://github.com/x" {
+		t.Error("Expected a click on the link to show its URL")
+	}
+}
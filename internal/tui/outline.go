@@ -0,0 +1,201 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Terminal width from which the outline is shown without being asked for
+const outlineAutoWidth = 120
+
+// Columns taken by the outline, including its border
+const outlineCols = 30
+
+// Narrowest content column the outline leaves beside it
+const minOutlineContent = 40
+
+/**
+ * Whether the outline sidebar is shown: turned on, the page has
+ * headings and the terminal is wide enough for both.
+ */
+func (m Model) outlineVisible() bool {
+	return m.outline && len(m.currentHeadings()) > 0 && m.width-4-outlineCols >= minOutlineContent
+}
+
+/**
+ * Columns the outline takes from the viewport (0 when hidden).
+ */
+func (m Model) outlineWidth() int {
+	if m.outlineVisible() {
+		return outlineCols
+	}
+	return 0
+}
+
+/**
+ * Lists the headings the outline shows: those found in the viewport.
+ * @return Indexes into currentHeadings
+ */
+func (m Model) outlineItems() []int {
+	var items []int
+	for i, row := range m.headingRows {
+		if row >= 0 {
+			items = append(items, i)
+		}
+	}
+	return items
+}
+
+/**
+ * Finds the section being read: the last heading at or above the top
+ * of the view, or the first one in view. At the bottom of the page,
+ * where later headings can no longer reach the top, the last heading
+ * in view wins.
+ * @return Index into currentHeadings, or -1 if none is in or above view
+ */
+func (m Model) activeSection() int {
+	top, bottom := m.viewport.YOffset, m.viewport.YOffset+m.viewport.Height
+	atBottom := top > 0 && m.viewport.AtBottom()
+	active := -1
+	for i, row := range m.headingRows {
+		if row >= 0 && row <= top {
+			active = i
+		}
+	}
+	for i, row := range m.headingRows {
+		if row > top && row < bottom && (active < 0 || atBottom) {
+			active = i
+		}
+	}
+	return active
+}
+
+/**
+ * Shows the outline and moves into it; if it already has focus, hides
+ * it.
+ * @effects Shows an error in the status area if the page has no
+ *          headings or the terminal is too narrow
+ */
+func (m *Model) toggleOutline() {
+	if m.outlineVisible() && m.outlineFocus {
+		m.outline, m.outlineSet, m.outlineFocus = false, true, false
+		m.refreshView()
+		return
+	}
+	if len(m.currentHeadings()) == 0 {
+		m.message, m.messageErr = "no headings here", true
+		return
+	}
+
+	if !m.outlineVisible() {
+		m.outline, m.outlineSet = true, true
+		if !m.outlineVisible() {
+			m.message, m.messageErr = "window too narrow for the outline", true
+			return
+		}
+		m.refreshView()
+	}
+	m.outlineFocus = true
+	m.outlineCursor = 0
+	active := m.activeSection()
+	for k, i := range m.outlineItems() {
+		if i == active {
+			m.outlineCursor = k
+		}
+	}
+}
+
+/**
+ * Handles actions while the outline has focus: moving the selection
+ * and going to the selected heading.
+ * @param action - Action from the keymap
+ * @param count - Count typed before it (0 = none)
+ * @return true if the action was consumed
+ */
+func (m *Model) updateOutline(action keyAction, count int) bool {
+	items := m.outlineItems()
+	if !m.outlineFocus || !m.outlineVisible() || len(items) == 0 {
+		return false
+	}
+
+	times := max(count, 1)
+	switch action {
+	case actionDown:
+		m.outlineCursor = min(m.outlineCursor+times, len(items)-1)
+	case actionUp:
+		m.outlineCursor = max(m.outlineCursor-times, 0)
+	case actionTop:
+		m.outlineCursor = 0
+	case actionBottom:
+		m.outlineCursor = len(items) - 1
+	case actionOpen:
+		m.gotoLine(m.headingRows[items[min(m.outlineCursor, len(items)-1)]])
+		m.outlineFocus = false
+	default:
+		return false
+	}
+	return true
+}
+
+/**
+ * Renders the outline sidebar: the page's headings, indented by level,
+ * with the section being read (and the selection) highlighted.
+ * @return Column as tall as the viewport
+ */
+func (m Model) renderOutline() string {
+	headings := m.currentHeadings()
+	items := m.outlineItems()
+	active := m.activeSection()
+	inner := outlineCols - 3 // Border, padding and a spare column
+
+	minLevel := 6
+	for _, i := range items {
+		minLevel = min(minLevel, headings[i].Level)
+	}
+
+	// Scroll the list so the selection (or the section being read) shows
+	focus := 0
+	for k, i := range items {
+		if m.outlineFocus && k == m.outlineCursor || !m.outlineFocus && i == active {
+			focus = k
+		}
+	}
+	room := max(m.viewport.Height-2, 1)
+	first := max(focus-room+1, 0)
+
+	lines := []string{m.styles.outlineTitle.Render("Contents"), ""}
+	if len(items) == 0 {
+		lines = append(lines, m.styles.outlineItem.Render("No headings found"))
+	}
+	for k := first; k < len(items) && k < first+room; k++ {
+		h := headings[items[k]]
+		text := ansi.Truncate(strings.Repeat("  ", h.Level-minLevel)+h.Title, inner-2, "…")
+
+		marker, style := "  ", m.styles.outlineItem
+		if items[k] == active {
+			style = m.styles.outlineActive
+		}
+		if m.outlineFocus && k == m.outlineCursor {
+			marker, style = "▸ ", m.styles.postSelected
+		}
+		lines = append(lines, style.Render(marker+text))
+	}
+
+	return m.styles.outline.
+		Width(outlineCols - 1).
+		Height(m.viewport.Height).
+		Render(strings.Join(lines, "\n"))
+}
+
+func init() {
+	registerOption(option{
+		name: "outline",
+		help: "Show the outline of pages with headings beside the content",
+		get:  func(m Model) bool { return m.outline },
+		set: func(m *Model, on bool) {
+			m.outline, m.outlineSet = on, true
+			m.refreshView()
+		},
+	})
+}
@@ -0,0 +1,70 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+/**
+ * Tests the outline shows on wide terminals, follows scrolling and
+ * jumps to the selected heading.
+ */
+func TestOutline(t *testing.T) {
+	m := motionModel()
+	m.SetSize(130, 30)
+	if !m.outlineVisible() || m.viewport.Width != 130-4-outlineCols {
+		t.Fatalf("Expected the outline beside a narrower viewport, got width %d", m.viewport.Width)
+	}
+	view := ansi.Strip(m.View())
+	if !strings.Contains(view, "Contents") || !strings.Contains(view, "Section 4") {
+		t.Errorf("Expected the headings beside the content, got %q", view)
+	}
+
+	m = typeKeys(m, "2 }")
+	if m.viewport.YOffset != 40 || m.activeSection() != 2 {
+		t.Errorf("Expected Section 2 to be the section read, got %d", m.activeSection())
+	}
+	m = typeKeys(m, "j j j")
+	if m.activeSection() != 2 {
+		t.Errorf("Expected Section 2 to stay active while reading it, got %d", m.activeSection())
+	}
+
+	m = typeKeys(m, "o j")
+	if !m.outlineFocus || m.outlineCursor != 3 {
+		t.Fatalf("Expected the outline focused on Section 3, got %d", m.outlineCursor)
+	}
+	if !strings.Contains(ansi.Strip(m.renderOutline()), "▸ Section 3") {
+		t.Error("Expected the selection to be marked")
+	}
+	m = pressKeys(m, keyEnter)
+	if m.outlineFocus || m.viewport.YOffset != 60 {
+		t.Errorf("Expected Enter to scroll to Section 3, got offset %d", m.viewport.YOffset)
+	}
+
+	m = typeKeys(m, "o o")
+	if m.outlineVisible() || m.viewport.Width != 126 {
+		t.Errorf("Expected a second o to hide the outline, got width %d", m.viewport.Width)
+	}
+
+	// Narrow terminals start without it, and the choice sticks
+	m.SetSize(80, 30)
+	m.SetSize(130, 30)
+	if m.outlineVisible() {
+		t.Error("Expected the outline to stay hidden once turned off")
+	}
+	m = motionModel()
+	if m.outlineVisible() {
+		t.Error("Expected no outline at 80 columns")
+	}
+	m.SetSize(60, 30)
+	m = typeKeys(m, "o")
+	if m.outlineVisible() || !m.messageErr {
+		t.Error("Expected an error when the window is too narrow")
+	}
+	m = typeKeys(m, "2 g t o")
+	if m.outlineVisible() || m.message != "no headings here" {
+		t.Errorf("Expected no outline on a page without headings, got %q", m.message)
+	}
+}
@@ -0,0 +1,156 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+/**
+ * A tab whose content is produced while the session runs, e.g. by an
+ * external program. The model forwards messages to every panel and
+ * shows the active one's View; panels never see the model itself.
+ * Methods are called from the Bubble Tea goroutine only.
+ */
+type Panel interface {
+	// Init starts the panel with the viewport size. It is called when
+	// the panel's tab is first opened, so panels of tabs a visitor never
+	// looks at do no work.
+	Init(width, height int) tea.Cmd
+
+	// Update handles a message. Panels receive every message that is
+	// not the model's own and must ignore ones they did not create.
+	// Interactive panels also receive keys while their tab is active.
+	Update(msg tea.Msg) tea.Cmd
+
+	// View returns the content to show in the viewport.
+	View() string
+
+	// Interactive reports whether the panel wants keys. Tab switching
+	// and ctrl+c are always handled by the model.
+	Interactive() bool
+
+	// Close stops the panel and releases what it holds (e.g. processes).
+	Close()
+}
+
+/**
+ * A panel that can carry on across a reload or theme switch. When the
+ * new tabs have a panel with the same key as a running one, the running
+ * panel takes its place instead of being restarted.
+ */
+type KeyedPanel interface {
+	Panel
+
+	// Key identifies what the panel runs, e.g. a program and its limits.
+	Key() string
+}
+
+/**
+ * Sent to panels when the viewport is resized.
+ */
+type PanelSizeMsg struct {
+	Width  int
+	Height int
+}
+
+/**
+ * Returns the active tab's panel, if it has one.
+ */
+func (m Model) activePanel() Panel {
+	if m.activeTab < 0 || m.activeTab >= len(m.tabs) {
+		return nil
+	}
+	return m.tabs[m.activeTab].Panel
+}
+
+/**
+ * Starts the active tab's panel the first time its tab is opened.
+ * @return Command returned by the panel
+ */
+func (m *Model) startPanel() tea.Cmd {
+	p := m.activePanel()
+	if p == nil || m.started[p] {
+		return nil
+	}
+	m.started[p] = true
+	cmd := p.Init(m.viewport.Width, m.viewport.Height)
+	m.refreshPanel()
+	return cmd
+}
+
+/**
+ * Moves running keyed panels over to the tabs replacing them and
+ * stops the rest.
+ * @param tabs - New tabs; panels matching a running one are swapped for it
+ */
+func (m *Model) carryPanels(tabs []Tab) {
+	running := make(map[string]Panel)
+	for _, tab := range m.tabs {
+		if p, ok := tab.Panel.(KeyedPanel); ok && m.started[p] {
+			if _, dup := running[p.Key()]; !dup {
+				running[p.Key()] = p
+			}
+		}
+	}
+
+	kept := make(map[Panel]bool)
+	for i, tab := range tabs {
+		if p, ok := tab.Panel.(KeyedPanel); ok {
+			if old, ok := running[p.Key()]; ok && !kept[old] {
+				tabs[i].Panel = old
+				kept[old] = true
+			}
+		}
+	}
+	for _, tab := range m.tabs {
+		if tab.Panel != nil && !kept[tab.Panel] {
+			tab.Panel.Close()
+			delete(m.started, tab.Panel)
+		}
+	}
+}
+
+/**
+ * Forwards a message to every panel and redraws the active one.
+ * @param msg - Message the model does not handle itself
+ * @return Commands returned by the panels
+ */
+func (m *Model) updatePanels(msg tea.Msg) tea.Cmd {
+	var cmds []tea.Cmd
+	for _, tab := range m.tabs {
+		if tab.Panel != nil {
+			cmds = append(cmds, tab.Panel.Update(msg))
+		}
+	}
+	m.refreshPanel()
+	return tea.Batch(cmds...)
+}
+
+/**
+ * Shows the active panel's latest view, keeping the scroll position.
+ */
+func (m *Model) refreshPanel() {
+	if p := m.activePanel(); p != nil && !m.overlayOpen() {
+		content := m.wrapContent(p.View())
+		m.setContent(content)
+		m.jumpLines = paragraphLines(content)
+	}
+}
+
+/**
+ * Stops every panel. Called when the session ends; the model must not
+ * be used afterwards.
+ */
+func (m Model) Close() {
+	closePanels(m.tabs)
+}
+
+/**
+ * Stops the panels of a tab list.
+ */
+func closePanels(tabs []Tab) {
+	for _, tab := range tabs {
+		if tab.Panel != nil {
+			tab.Panel.Close()
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+/**
+ * Panel that records what it receives.
+ */
+type fakePanel struct {
+	interactive bool
+	inits       int
+	width       int
+	keys        []string
+	view        string
+	closed      bool
+}
+
+type fakePanelMsg struct{ view string }
+
+func (p *fakePanel) Init(width, height int) tea.Cmd {
+	p.inits++
+	p.width = width
+	return nil
+}
+
+func (p *fakePanel) Update(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case fakePanelMsg:
+		p.view = msg.view
+	case PanelSizeMsg:
+		p.width = msg.Width
+	case tea.KeyMsg:
+		p.keys = append(p.keys, msg.String())
+	}
+	return nil
+}
+
+func (p *fakePanel) View() string      { return p.view }
+func (p *fakePanel) Interactive() bool { return p.interactive }
+func (p *fakePanel) Close()            { p.closed = true }
+
+/**
+ * Panel that may carry on across reloads.
+ */
+type fakeKeyedPanel struct {
+	fakePanel
+	key string
+}
+
+func (p *fakeKeyedPanel) Key() string { return p.key }
+
+/**
+ * Tests panel messages, sizes and keys are routed and the view refreshed.
+ */
+func TestUpdate_Panels(t *testing.T) {
+	status := &fakePanel{view: "loading"}
+	game := &fakePanel{interactive: true}
+	m := NewModel([]Tab{{Name: "Status", Panel: status}, {Name: "Game", Panel: game}}, "test")
+	m.showSplash = false
+	m.SetSize(80, 30)
+	m.Init()
+
+	if status.width != m.viewport.Width {
+		t.Errorf("Expected panels started with the viewport width, got %d", status.width)
+	}
+	if game.inits != 0 {
+		t.Error("Expected the game to wait until its tab is opened")
+	}
+
+	next, _ := m.Update(fakePanelMsg{view: "all green"})
+	m = next.(Model)
+	if !strings.Contains(m.viewport.View(), "all green") {
+		t.Errorf("Expected refreshed panel view, got %q", m.viewport.View())
+	}
+
+	next, _ = m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	m = next.(Model)
+	if game.width != 96 {
+		t.Errorf("Expected resize forwarded to panels, got width %d", game.width)
+	}
+
+	// Keys reach interactive panels only, except tab switching
+	keyQ := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}}
+	m = pressKeys(m, keyQ, tea.KeyMsg{Type: tea.KeyTab}, keyQ, tea.KeyMsg{Type: tea.KeyTab})
+	if game.inits != 1 || status.inits != 1 {
+		t.Errorf("Expected each panel started once, got game=%d status=%d", game.inits, status.inits)
+	}
+	if strings.Join(game.keys, ",") != "q" || len(status.keys) != 0 {
+		t.Errorf("Unexpected keys: game=%v status=%v", game.keys, status.keys)
+	}
+	if m.activeTab != 0 {
+		t.Errorf("Expected tab to leave the interactive panel, got tab %d", m.activeTab)
+	}
+
+	m.Close()
+	if !status.closed || !game.closed {
+		t.Error("Expected Close to stop every panel")
+	}
+}
+
+/**
+ * Tests a reload keeps running keyed panels whose key is unchanged and
+ * stops the others.
+ */
+func TestReplaceTabs_Panels(t *testing.T) {
+	game := &fakeKeyedPanel{fakePanel: fakePanel{interactive: true}, key: "snake"}
+	status := &fakePanel{}
+	m := NewModel([]Tab{{Name: "Game", Panel: game}, {Name: "Status", Panel: status}}, "test")
+	m.showSplash = false
+	m.SetSize(80, 30)
+	m.Init()
+
+	newGame := &fakeKeyedPanel{key: "snake"}
+	newStatus := &fakePanel{}
+	m.replaceTabs([]Tab{{Name: "Game", Panel: newGame}, {Name: "Status", Panel: newStatus}})
+	if m.tabs[0].Panel != game || game.closed || game.inits != 1 || newGame.inits != 0 {
+		t.Error("Expected the running game to carry on")
+	}
+	if !status.closed || m.tabs[1].Panel != newStatus {
+		t.Error("Expected other panels to be replaced")
+	}
+
+	other := &fakeKeyedPanel{key: "tetris"}
+	m.replaceTabs([]Tab{{Name: "Game", Panel: other}})
+	if !game.closed || other.inits != 1 {
+		t.Error("Expected a changed game to be restarted")
+	}
+}
@@ -0,0 +1,227 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+/**
+ * A single entry in the structured projects catalogue.
+ */
+type Project struct {
+	Name    string
+	Summary string
+	Tech    []string
+	Repo    string
+	Status  string
+	Date    time.Time // Zero if the project has no date
+}
+
+/**
+ * Collects the distinct tech tags used across all projects.
+ * @param projects - Projects to scan
+ * @return Sorted, de-duplicated tag list
+ */
+func projectTags(projects []Project) []string {
+	lists := make([][]string, len(projects))
+	for i, p := range projects {
+		lists[i] = p.Tech
+	}
+	return uniqueTags(lists)
+}
+
+/**
+ * Merges tag lists case-insensitively, keeping the first spelling seen.
+ * @param lists - Tag lists to merge
+ * @return Sorted, de-duplicated tag list
+ */
+func uniqueTags(lists [][]string) []string {
+	seen := make(map[string]bool)
+	var tags []string
+
+	for _, list := range lists {
+		for _, tag := range list {
+			key := strings.ToLower(tag)
+			if !seen[key] {
+				seen[key] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+
+	sort.Slice(tags, func(i, j int) bool {
+		return strings.ToLower(tags[i]) < strings.ToLower(tags[j])
+	})
+	return tags
+}
+
+/**
+ * Filters projects by tech tag and sorts them by date.
+ * Undated projects always sort last.
+ * @param projects - Projects to filter
+ * @param tag - Tech tag to keep (empty keeps all)
+ * @param oldestFirst - Sort ascending by date instead of newest first
+ * @return New slice with the matching projects in display order
+ */
+func filterProjects(projects []Project, tag string, oldestFirst bool) []Project {
+	var out []Project
+	for _, p := range projects {
+		if tag == "" || containsTag(p.Tech, tag) {
+			out = append(out, p)
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := out[i].Date, out[j].Date
+		if a.IsZero() || b.IsZero() {
+			return !a.IsZero() && b.IsZero()
+		}
+		if oldestFirst {
+			return a.Before(b)
+		}
+		return a.After(b)
+	})
+	return out
+}
+
+/**
+ * Reports whether a tag list contains the given tag (case-insensitive).
+ */
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+/**
+ * Returns the tech tag currently used as filter, or "" for all.
+ */
+func (m Model) currentProjectTag() string {
+	tags := projectTags(m.tabs[m.activeTab].Projects)
+	if m.projectTag <= 0 || m.projectTag > len(tags) {
+		return ""
+	}
+	return tags[m.projectTag-1]
+}
+
+/**
+ * Handles actions on the projects tab: filtering by tech and sorting.
+ * @param action - Action from the keymap
+ * @return true if the action was consumed
+ */
+func (m *Model) updateProjects(action keyAction) bool {
+	switch action {
+	case actionFilterNext:
+		m.cycleProjectTag(1)
+	case actionFilterPrev:
+		m.cycleProjectTag(-1)
+	case actionSort:
+		m.projectOldestFirst = !m.projectOldestFirst
+		m.updateViewportContent()
+	default:
+		return false
+	}
+	return true
+}
+
+/**
+ * Cycles the tech tag filter forwards or backwards.
+ * Position 0 means "all", positions 1..n map to the sorted tag list.
+ * @param delta - +1 for next tag, -1 for previous
+ * @effects Updates the filter and re-renders the projects tab
+ */
+func (m *Model) cycleProjectTag(delta int) {
+	count := len(projectTags(m.tabs[m.activeTab].Projects)) + 1
+	m.projectTag = ((m.projectTag+delta)%count + count) % count
+	m.updateViewportContent()
+}
+
+/**
+ * Reports whether the active tab shows the projects catalogue.
+ */
+func (m Model) onProjectsTab() bool {
+	return m.activeTab >= 0 && m.activeTab < len(m.tabs) && len(m.tabs[m.activeTab].Projects) > 0
+}
+
+/**
+ * Renders the filtered projects catalogue as a stack of cards.
+ * @param width - Available width in cells
+ * @return Styled catalogue string
+ */
+func (m Model) renderProjects(width int) string {
+	tag := m.currentProjectTag()
+	projects := filterProjects(m.tabs[m.activeTab].Projects, tag, m.projectOldestFirst)
+
+	cardWidth := width - 2
+	if cardWidth > 100 {
+		cardWidth = 100
+	}
+
+	filter := "all"
+	if tag != "" {
+		filter = tag
+	}
+	order := "newest first"
+	if m.projectOldestFirst {
+		order = "oldest first"
+	}
+
+	var b strings.Builder
+	b.WriteString(m.styles.projectHeader.Render(fmt.Sprintf("Projects (%d)  •  tech: %s  •  %s", len(projects), filter, order)))
+	b.WriteString("\n\n")
+
+	for _, p := range projects {
+		b.WriteString(m.renderProjectCard(p, cardWidth))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+/**
+ * Renders a single project as a bordered card.
+ * @param p - Project to render
+ * @param width - Outer card width in cells
+ * @return Styled card string
+ */
+func (m Model) renderProjectCard(p Project, width int) string {
+	inner := width - m.styles.projectCard.GetHorizontalFrameSize()
+	if inner < 10 {
+		inner = 10
+	}
+
+	var meta []string
+	if p.Status != "" {
+		meta = append(meta, p.Status)
+	}
+	if !p.Date.IsZero() {
+		meta = append(meta, p.Date.Format("Jan 2006"))
+	}
+
+	lines := []string{m.styles.projectTitle.Render(p.Name)}
+	if len(meta) > 0 {
+		lines = append(lines, m.styles.projectMeta.Render(strings.Join(meta, " • ")))
+	}
+	if p.Summary != "" {
+		lines = append(lines, "", lipgloss.NewStyle().Width(inner).Render(p.Summary))
+	}
+	if len(p.Tech) > 0 {
+		var chips []string
+		for _, t := range p.Tech {
+			chips = append(chips, m.styles.projectTag.Render(t))
+		}
+		lines = append(lines, "", lipgloss.NewStyle().Width(inner).Render(strings.Join(chips, " ")))
+	}
+	if p.Repo != "" {
+		lines = append(lines, "", m.hyperlink(m.styles.projectLink.Render(p.Repo), p.Repo))
+	}
+
+	return m.styles.projectCard.Width(width - m.styles.projectCard.GetHorizontalBorderSize()).Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,82 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+/**
+ * Builds a small catalogue used by the project tests.
+ */
+func testProjects() []Project {
+	return []Project{
+		{Name: "Old", Tech: []string{"C"}, Date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{Name: "Undated", Tech: []string{"Go"}},
+		{Name: "New", Tech: []string{"Go", "C"}, Date: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+	}
+}
+
+/**
+ * Tests tag collection is sorted and de-duplicated.
+ */
+func TestProjectTags(t *testing.T) {
+	tags := projectTags(testProjects())
+	if strings.Join(tags, ",") != "C,Go" {
+		t.Errorf("Expected tags C,Go, got %v", tags)
+	}
+}
+
+/**
+ * Tests filtering by tag and sorting by date.
+ */
+func TestFilterProjects(t *testing.T) {
+	names := func(ps []Project) string {
+		var out []string
+		for _, p := range ps {
+			out = append(out, p.Name)
+		}
+		return strings.Join(out, ",")
+	}
+
+	if got := names(filterProjects(testProjects(), "", false)); got != "New,Old,Undated" {
+		t.Errorf("Newest first: got %s", got)
+	}
+
+	if got := names(filterProjects(testProjects(), "", true)); got != "Old,New,Undated" {
+		t.Errorf("Oldest first: got %s", got)
+	}
+
+	if got := names(filterProjects(testProjects(), "go", false)); got != "New,Undated" {
+		t.Errorf("Filter go: got %s", got)
+	}
+}
+
+/**
+ * Tests the filter and sort keys on the projects tab.
+ */
+func TestUpdate_ProjectFilterKeys(t *testing.T) {
+	m := NewModel([]Tab{{Name: "Projects", Projects: testProjects()}}, "test")
+	m.showSplash = false
+
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+	m = updatedModel.(Model)
+	if m.currentProjectTag() != "C" {
+		t.Errorf("Expected filter C, got %q", m.currentProjectTag())
+	}
+
+	// Cycling backwards from the first tag returns to "all"
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'F'}})
+	m = updatedModel.(Model)
+	if m.currentProjectTag() != "" {
+		t.Errorf("Expected no filter, got %q", m.currentProjectTag())
+	}
+
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	m = updatedModel.(Model)
+	if !m.projectOldestFirst {
+		t.Error("Expected sort order to toggle")
+	}
+}
@@ -0,0 +1,148 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/adamdeleeuw/ssh-portfolio/internal/qr"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Light border around codes, in modules
+const qrQuietZone = 2
+
+/**
+ * A QR code in content, drawn when the page is shown so it can fall
+ * back to text when the viewport is too small to scan it.
+ */
+type QRCode struct {
+	Label string   // Caption, e.g. the URL without its scheme
+	Text  string   // Shown instead of the code when it does not fit
+	Code  *qr.Code // Encoded code
+}
+
+/**
+ * Draws a QR code if it fits in the given area.
+ * @param code - Code to draw
+ * @param cols - Available width in cells
+ * @param rows - Available height in lines
+ * @param large - Prefer double size when there is room
+ * @return Styled lines, or nil if the code does not fit
+ */
+func drawQRCode(code QRCode, cols, rows int, large bool) []string {
+	if code.Code == nil {
+		return nil
+	}
+	fits := func(lines []string) bool {
+		return len(lines) <= rows && ansi.StringWidth(lines[0]) <= cols
+	}
+
+	lines := code.Code.Lines(qrQuietZone)
+	if large && fits(code.Code.LargeLines(qrQuietZone)) {
+		lines = code.Code.LargeLines(qrQuietZone)
+	}
+	if !fits(lines) {
+		return nil
+	}
+
+	for i, l := range lines {
+		lines[i] = qrStyle.Render(l)
+	}
+	return lines
+}
+
+/**
+ * Text shown in place of a code that does not fit.
+ */
+func (m Model) qrFallback(code QRCode) []string {
+	lines := []string{m.styles.imageCaption.Render("▣ " + code.Label + " (enlarge the window for a QR code)")}
+	if code.Text != "" && code.Text != code.Label {
+		for _, l := range strings.Split(code.Text, "\n") {
+			lines = append(lines, "  "+l)
+		}
+	}
+	return lines
+}
+
+/**
+ * Replaces QR code placeholder lines in rendered content with codes,
+ * or with their text when the viewport is too small to scan them.
+ * @param content - Rendered page
+ * @param codes - Codes keyed by placeholder
+ * @return Content with codes laid out, and where each line moved
+ */
+func (m Model) layoutQRCodes(content string, codes map[string]QRCode) (string, lineMap) {
+	if len(codes) == 0 {
+		return content, nil
+	}
+
+	var out []string
+	lines := strings.Split(content, "\n")
+	rows := make(lineMap, len(lines))
+	for i, line := range lines {
+		rows[i] = len(out)
+		code, ok := codes[strings.TrimSpace(ansi.Strip(line))]
+		if !ok {
+			out = append(out, line)
+			continue
+		}
+
+		art := drawQRCode(code, m.viewport.Width-4, m.viewport.Height-1, false)
+		if art == nil {
+			for _, l := range m.qrFallback(code) {
+				out = append(out, "  "+l)
+			}
+			continue
+		}
+		for _, l := range art {
+			out = append(out, "  "+l)
+		}
+		out = append(out, "  "+m.styles.imageCaption.Render(code.Label))
+	}
+
+	return strings.Join(out, "\n"), rows
+}
+
+/**
+ * Returns the active tab's contact code, if it has one.
+ */
+func (m Model) contactCode() (QRCode, bool) {
+	if m.activeTab < 0 || m.activeTab >= len(m.tabs) || m.tabs[m.activeTab].Contact == nil {
+		return QRCode{}, false
+	}
+	return *m.tabs[m.activeTab].Contact, true
+}
+
+/**
+ * Opens or closes the active tab's contact QR code.
+ * @effects Closes any other overlay; does nothing without a contact code
+ */
+func (m *Model) toggleContact() {
+	if _, ok := m.contactCode(); !ok {
+		return
+	}
+	open := !m.showContact
+	m.closeOverlays()
+	m.showContact = open
+	m.updateViewportContent()
+}
+
+/**
+ * Renders the contact QR code centered in the viewport.
+ * @return Code with caption, or its text if the viewport is too small
+ */
+func (m Model) renderContact() string {
+	code, _ := m.contactCode()
+
+	lines := drawQRCode(code, m.viewport.Width, m.viewport.Height-2, true)
+	if lines == nil {
+		return strings.Join(m.qrFallback(code), "\n")
+	}
+
+	pad := strings.Repeat(" ", max((m.viewport.Width-ansi.StringWidth(lines[0]))/2, 0))
+	for i := range lines {
+		lines[i] = pad + lines[i]
+	}
+	caption := m.styles.imageCaption.Render(code.Label + " • " + m.keyChoice(actionContact, actionBack) + " to close")
+	lines = append(lines, "", strings.Repeat(" ", max((m.viewport.Width-ansi.StringWidth(caption))/2, 0))+caption)
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,79 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/adamdeleeuw/ssh-portfolio/internal/qr"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+/**
+ * Builds a code for tests.
+ */
+func testQRCode(t *testing.T) QRCode {
+	t.Helper()
+	code, err := qr.Encode("https://example.com", qr.Medium)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return QRCode{Label: "example.com", Text: "https://example.com", Code: code}
+}
+
+/**
+ * Tests inline codes are drawn when they fit and fall back to text otherwise.
+ */
+func TestLayoutQRCodes(t *testing.T) {
+	code := testQRCode(t)
+	tab := Tab{
+		Name:    "About",
+		Content: "  Scan me\n  sshportfolioqr0\n  Done",
+		QRCodes: map[string]QRCode{"sshportfolioqr0": code},
+	}
+
+	m := NewModel([]Tab{tab}, "test")
+	m.showSplash = false
+	m.SetSize(80, 50)
+	view := ansi.Strip(m.viewport.View())
+	if !strings.Contains(view, "█") || strings.Contains(view, "enlarge") {
+		t.Errorf("Expected the code drawn at 80x50, got %q", view)
+	}
+
+	m.SetSize(30, 50)
+	view = ansi.Strip(m.viewport.View())
+	if strings.Contains(view, "█") || !strings.Contains(view, "https://example.com") {
+		t.Errorf("Expected text fallback at 30 columns, got %q", view)
+	}
+}
+
+/**
+ * Tests the c key toggles the contact code on tabs that have one.
+ */
+func TestUpdate_ContactCode(t *testing.T) {
+	code := testQRCode(t)
+	m := NewModel([]Tab{{Name: "About", Contact: &code}, {Name: "Future"}}, "test")
+	m.showSplash = false
+	m.SetSize(120, 60)
+
+	keyC := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}}
+	m = pressKeys(m, keyC)
+	if !m.showContact {
+		t.Fatal("Expected c to open the contact code")
+	}
+	view := ansi.Strip(m.viewport.View())
+	if !strings.Contains(view, "████") || !strings.Contains(view, "example.com") {
+		t.Errorf("Expected a large code with caption, got %q", view)
+	}
+
+	m = pressKeys(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.showContact {
+		t.Error("Expected esc to close the contact code")
+	}
+
+	// Tabs without a contact ignore the key
+	m = pressKeys(m, tea.KeyMsg{Type: tea.KeyTab}, keyC)
+	if m.showContact {
+		t.Error("Expected c to do nothing without a contact code")
+	}
+}
@@ -0,0 +1,96 @@
+package tui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Delay before retrying a reload that failed
+const reloadRetryDelay = time.Minute
+
+/**
+ * Rebuilds the tab list while a session is running.
+ * @return New tabs
+ * @return When content is next due to change (zero if never)
+ * @return error if the content could not be loaded
+ */
+type Reloader func() ([]Tab, time.Time, error)
+
+/**
+ * Message sent when scheduled content is due to change.
+ */
+type reloadMsg struct{}
+
+/**
+ * Registers a reloader to run when content is next due to change,
+ * e.g. when a page's publish_at or expire_at passes.
+ * Must be called before the program starts.
+ * @param reload - Function that rebuilds the tabs
+ * @param next - First time to reload (zero disables reloading until set)
+ */
+func (m *Model) SetReloader(reload Reloader, next time.Time) {
+	m.reload = reload
+	m.nextReload = next
+}
+
+/**
+ * Creates a command that fires at the next scheduled reload.
+ * @return Timer command, or nil if nothing is scheduled
+ */
+func (m Model) scheduleReload() tea.Cmd {
+	if m.reload == nil || m.nextReload.IsZero() {
+		return nil
+	}
+	return tea.Tick(time.Until(m.nextReload), func(time.Time) tea.Msg {
+		return reloadMsg{}
+	})
+}
+
+/**
+ * Runs the reloader and swaps in the new tabs.
+ * @return Command for the following reload
+ * @effects Replaces tabs; on failure keeps the old ones and retries later
+ */
+func (m *Model) runReload() tea.Cmd {
+	tabs, next, err := m.reload()
+	if err != nil {
+		m.nextReload = time.Now().Add(reloadRetryDelay)
+		return m.scheduleReload()
+	}
+
+	cmd := m.replaceTabs(tabs)
+	m.nextReload = next
+	return tea.Batch(cmd, m.scheduleReload())
+}
+
+/**
+ * Replaces the tab list, staying on the same tab (by name) and
+ * scroll position where possible. Panels of the old tabs are closed,
+ * unless an identical one in the new tabs can take them over.
+ * @param tabs - New tabs
+ * @return Command from starting the active tab's panel
+ */
+func (m *Model) replaceTabs(tabs []Tab) tea.Cmd {
+	var current string
+	if m.activeTab >= 0 && m.activeTab < len(m.tabs) {
+		current = m.tabs[m.activeTab].Name
+	}
+	offset := m.viewport.YOffset
+
+	m.carryPanels(tabs)
+	m.tabs = tabs
+	m.activeTab = 0
+	for i, tab := range tabs {
+		if tab.Name == current {
+			m.activeTab = i
+			break
+		}
+	}
+
+	m.updateViewportContent()
+	if current != "" && m.activeTab < len(tabs) && tabs[m.activeTab].Name == current {
+		m.viewport.SetYOffset(offset)
+	}
+	return m.startPanel()
+}
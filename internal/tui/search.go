@@ -0,0 +1,516 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Most hits listed by a search across all tabs
+const maxSearchHits = 200
+
+// Highlighting of matches and of the current match, and what ends it
+const (
+	matchStart   = "\x1b[7m"
+	matchEnd     = "\x1b[27m"
+	currentStart = "\x1b[7;4m"
+	currentEnd   = "\x1b[24;27m"
+)
+
+/**
+ * A search match in the viewport content. Columns count runes of the
+ * line's text, escape sequences left out.
+ */
+type match struct {
+	line  int
+	start int // First rune of the match
+	end   int // Rune after the match
+}
+
+/**
+ * A match found by a search across all tabs.
+ */
+type searchHit struct {
+	tab  int    // Tab index
+	post int    // Post index on a blog tab (-1 = the tab itself)
+	line int    // Line in the tab's (or post's) content
+	text string // Text of the line, trimmed
+}
+
+/**
+ * Finds a pattern in a line of text. Matching ignores case unless the
+ * pattern has an upper-case letter ("smartcase").
+ * @param text - Line without escape sequences
+ * @param pattern - Text to find
+ * @return Start and end rune of each match, in order, not overlapping
+ */
+func findInLine(text, pattern string) [][2]int {
+	if pattern == "" {
+		return nil
+	}
+	fold := !strings.ContainsFunc(pattern, unicode.IsUpper)
+	runes, pat := []rune(text), []rune(pattern)
+	if fold {
+		for i, r := range runes {
+			runes[i] = unicode.ToLower(r)
+		}
+		for i, r := range pat {
+			pat[i] = unicode.ToLower(r)
+		}
+	}
+
+	var out [][2]int
+	for i := 0; i+len(pat) <= len(runes); i++ {
+		if string(runes[i:i+len(pat)]) == string(pat) {
+			out = append(out, [2]int{i, i + len(pat)})
+			i += len(pat) - 1
+		}
+	}
+	return out
+}
+
+/**
+ * Finds the search pattern in the viewport content, keeping the
+ * current match if it is still there.
+ */
+func (m *Model) findMatches() {
+	var current *match
+	if m.matchIndex >= 0 && m.matchIndex < len(m.matches) {
+		current = &m.matches[m.matchIndex]
+	}
+	var matches []match
+	index := -1
+	if m.search != "" {
+		for i, line := range strings.Split(m.content, "\n") {
+			for _, r := range findInLine(ansi.Strip(line), m.search) {
+				found := match{line: i, start: r[0], end: r[1]}
+				if current != nil && found == *current {
+					index = len(matches)
+				}
+				matches = append(matches, found)
+			}
+		}
+	}
+	m.matches, m.matchIndex = matches, index
+}
+
+/**
+ * Highlights the matches in the viewport content.
+ * @return Content to show
+ */
+func (m Model) highlightMatches() string {
+	if !m.highlight || len(m.matches) == 0 {
+		return m.content
+	}
+
+	lines := strings.Split(m.content, "\n")
+	for i := 0; i < len(m.matches); {
+		line := m.matches[i].line
+		j := i
+		for j < len(m.matches) && m.matches[j].line == line {
+			j++
+		}
+		current := -1
+		if m.matchIndex >= i && m.matchIndex < j {
+			current = m.matchIndex - i
+		}
+		lines[line] = highlightLine(lines[line], m.matches[i:j], current)
+		i = j
+	}
+	return strings.Join(lines, "\n")
+}
+
+/**
+ * Wraps matches in a rendered line with reverse video, keeping the
+ * line's own escape sequences.
+ * @param line - Rendered line
+ * @param matches - Matches on the line, in order
+ * @param current - Index of the current match in matches (-1 = none)
+ * @return Highlighted line
+ */
+func highlightLine(line string, matches []match, current int) string {
+	return markSpans(line, matches, func(i int) (string, string) {
+		if i == current {
+			return currentStart, currentEnd
+		}
+		return matchStart, matchEnd
+	})
+}
+
+/**
+ * Wraps spans of a rendered line in escape sequences, keeping the
+ * line's own. Styles set inside a span (which may reset it) are
+ * followed by its opening sequence again.
+ * @param line - Rendered line
+ * @param spans - Spans on the line, in order, not overlapping
+ * @param style - Opening and closing sequences of the i-th span
+ * @return Marked line
+ */
+func markSpans(line string, spans []match, style func(i int) (string, string)) string {
+	var b strings.Builder
+	col, next := 0, 0
+	open, end := "", ""
+
+	for i := 0; i < len(line); {
+		if line[i] == '\x1b' {
+			n := escapeLen(line[i:])
+			seq := line[i : i+n]
+			b.WriteString(seq)
+			if open != "" && strings.HasPrefix(seq, "\x1b[") && strings.HasSuffix(seq, "m") {
+				b.WriteString(open)
+			}
+			i += n
+			continue
+		}
+
+		if open == "" && next < len(spans) && col == spans[next].start {
+			open, end = style(next)
+			b.WriteString(open)
+		}
+		_, n := utf8.DecodeRuneInString(line[i:])
+		b.WriteString(line[i : i+n])
+		i += n
+		col++
+		if open != "" && col == spans[next].end {
+			b.WriteString(end)
+			open = ""
+			next++
+		}
+	}
+	return b.String()
+}
+
+/**
+ * Measures the escape sequence at the start of s: CSI, OSC and other
+ * string sequences, or a two-byte escape.
+ * @return Length in bytes (at least 1)
+ */
+func escapeLen(s string) int {
+	if len(s) < 2 {
+		return len(s)
+	}
+	switch s[1] {
+	case '[':
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1
+			}
+		}
+		return len(s)
+	case ']', 'P', '_', '^', 'X':
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			}
+			if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return len(s)
+	}
+	return 2
+}
+
+/**
+ * Re-highlights the viewport content after the matches, the current
+ * match or the focused link changed, keeping the scroll position.
+ */
+func (m *Model) redrawMatches() {
+	offset := m.viewport.YOffset
+	m.viewport.SetContent(m.highlightSelection(m.highlightLink(m.highlightMatches())))
+	m.viewport.SetYOffset(offset)
+}
+
+/**
+ * Searches the viewport content and moves to the first match after
+ * (or before) the top of the view.
+ * @param pattern - Text to find
+ * @param back - Search up the page instead of down
+ * @effects Shows an error in the status area if nothing matches
+ */
+func (m *Model) startSearch(pattern string, back bool) {
+	m.search, m.searchBack, m.highlight = pattern, back, true
+	m.matchIndex = -1
+	m.findMatches()
+	m.nextMatch(1)
+}
+
+/**
+ * Moves to a later (or earlier) match of the last search, wrapping at
+ * either end of the page.
+ * @param n - Matches to move in the search's direction; negative
+ *            moves the other way
+ * @effects Shows an error in the status area if nothing matches
+ */
+func (m *Model) nextMatch(n int) {
+	if m.search == "" {
+		m.message, m.messageErr = "no previous search", true
+		return
+	}
+	m.highlight = true
+	if len(m.matches) == 0 {
+		m.redrawMatches()
+		m.message, m.messageErr = "pattern not found: "+m.search, true
+		return
+	}
+	if m.searchBack {
+		n = -n
+	}
+
+	index := m.matchIndex
+	if index < 0 {
+		// Start from the top of the view (or the cursor line on it)
+		line := m.cursorLine
+		if line < m.viewport.YOffset || line >= m.viewport.YOffset+m.viewport.Height {
+			line = m.viewport.YOffset
+		}
+		index = len(m.matches)
+		for i, mt := range m.matches {
+			if mt.line >= line {
+				index = i
+				break
+			}
+		}
+		if n > 0 {
+			index-- // The first step lands on the match at or after the line
+		}
+	}
+
+	for ; n > 0; n-- {
+		if index++; index >= len(m.matches) {
+			index = 0
+			m.message = "search hit BOTTOM, continuing at TOP"
+		}
+	}
+	for ; n < 0; n++ {
+		if index--; index < 0 {
+			index = len(m.matches) - 1
+			m.message = "search hit TOP, continuing at BOTTOM"
+		}
+	}
+	m.showMatch(index)
+}
+
+/**
+ * Makes a match the current one, scrolling it to the middle of the
+ * view if it is out of sight.
+ * @param index - Index in matches
+ */
+func (m *Model) showMatch(index int) {
+	m.matchIndex = index
+	m.redrawMatches()
+	line := m.matches[index].line
+	if line < m.viewport.YOffset || line >= m.viewport.YOffset+m.viewport.Height {
+		m.viewport.SetYOffset(line - m.viewport.Height/2)
+	}
+	m.cursorLine = line
+}
+
+/**
+ * Hides the highlighting until the next search or n/N.
+ */
+func (m *Model) clearHighlight() {
+	m.highlight = false
+	m.redrawMatches()
+}
+
+/**
+ * Describes the search for the status bar, e.g. "/go [3/12]".
+ * @return Status text, or "" when no search is highlighted
+ */
+func (m Model) searchStatus() string {
+	if !m.highlight || m.search == "" {
+		return ""
+	}
+	dir := "/"
+	if m.searchBack {
+		dir = "?"
+	}
+	if m.matchIndex < 0 {
+		return fmt.Sprintf("%s%s [%d]", dir, m.search, len(m.matches))
+	}
+	return fmt.Sprintf("%s%s [%d/%d]", dir, m.search, m.matchIndex+1, len(m.matches))
+}
+
+/**
+ * Searches the content of every tab and every blog post, and lists
+ * the hits.
+ * @param pattern - Text to find
+ * @return error if nothing matches
+ * @effects Opens the results
+ */
+func (m *Model) searchAll(pattern string) error {
+	m.search, m.searchBack, m.highlight = pattern, false, true
+	m.results = m.findAll(pattern)
+	m.resultIndex = 0
+	if len(m.results) == 0 {
+		m.matchIndex = -1
+		m.findMatches()
+		m.redrawMatches()
+		return fmt.Errorf("pattern not found in any tab: %s", pattern)
+	}
+	m.closeOverlays()
+	m.showResults = true
+	m.updateViewportContent()
+	return nil
+}
+
+/**
+ * Finds a pattern in every tab, rendering each tab (and post) the
+ * way the viewport would.
+ * @return Hits in tab order, at most maxSearchHits
+ */
+func (m Model) findAll(pattern string) []searchHit {
+	var hits []searchHit
+	add := func(tab, post int, content string) {
+		for i, line := range strings.Split(content, "\n") {
+			text := ansi.Strip(line)
+			if len(hits) < maxSearchHits && len(findInLine(text, pattern)) > 0 {
+				hits = append(hits, searchHit{tab: tab, post: post, line: i, text: strings.TrimSpace(text)})
+			}
+		}
+	}
+
+	for i, tab := range m.tabs {
+		c := m
+		c.activeTab = i
+		c.closeOverlays()
+		c.blogOpen, c.blogTag, c.blogCursor = false, 0, 0
+		if len(tab.Posts) == 0 {
+			content, _, _ := c.renderContent()
+			add(i, -1, content)
+			continue
+		}
+		c.blogOpen = true
+		for j := range tab.Posts {
+			c.blogCursor = j
+			content, _, _ := c.renderContent()
+			add(i, j, content)
+		}
+	}
+	return hits
+}
+
+/**
+ * Renders the hits of a search across all tabs.
+ * @return Styled list for the viewport
+ */
+func (m Model) renderResults() string {
+	var b strings.Builder
+	summary := fmt.Sprintf("%d matches for %q", len(m.results), m.search)
+	if len(m.results) == maxSearchHits {
+		summary = fmt.Sprintf("First %d matches for %q", maxSearchHits, m.search)
+	}
+	b.WriteString(m.styles.projectHeader.Render(summary) + "\n\n")
+
+	for i, hit := range m.results {
+		marker, style := "  ", m.styles.postTitle
+		if i == m.resultIndex {
+			marker, style = "▸ ", m.styles.postSelected
+		}
+		b.WriteString(style.Render(marker+m.hitLocation(hit)) + "\n")
+		b.WriteString(m.styles.projectMeta.Render("    "+hit.text) + "\n")
+	}
+
+	b.WriteString("\n" + m.styles.projectMeta.Render(fmt.Sprintf("%s to go there • %s to close",
+		m.keyChoice(actionOpen), m.keyChoice(actionBack))))
+	return b.String()
+}
+
+/**
+ * Names where a hit is, e.g. "Blog › Hello world, line 12".
+ */
+func (m Model) hitLocation(hit searchHit) string {
+	tab := m.tabs[hit.tab]
+	where := tab.Name
+	if hit.post >= 0 {
+		where += " › " + tab.Posts[hit.post].Title
+	}
+	return fmt.Sprintf("%s, line %d", where, hit.line+1)
+}
+
+/**
+ * Handles actions in the search results: moving the selection and
+ * going to the selected hit.
+ * @param action - Action from the keymap
+ * @param count - Count typed before it (0 = none)
+ * @return true if the action was consumed
+ */
+func (m *Model) updateResults(action keyAction, count int) bool {
+	times := max(count, 1)
+	switch action {
+	case actionDown:
+		m.resultIndex = min(m.resultIndex+times, len(m.results)-1)
+	case actionUp:
+		m.resultIndex = max(m.resultIndex-times, 0)
+	case actionTop:
+		m.resultIndex = 0
+	case actionBottom:
+		m.resultIndex = len(m.results) - 1
+	case actionOpen:
+		m.jumpTo(m.results[m.resultIndex])
+		return true
+	default:
+		return false
+	}
+
+	m.updateViewportContent()
+	// Keep the selection in view: two lines per hit below the summary
+	line := 2 + 2*m.resultIndex
+	m.viewport.SetYOffset(max(line+2-m.viewport.Height, 0))
+	m.cursorLine = line
+	return true
+}
+
+/**
+ * Opens the tab (and post) of a hit and moves to its match.
+ * @param hit - Hit from the search results
+ */
+func (m *Model) jumpTo(hit searchHit) {
+	m.closeOverlays()
+	m.activeTab = hit.tab
+	if hit.post >= 0 {
+		m.blogOpen, m.blogTag, m.blogCursor = true, 0, hit.post
+	} else {
+		m.blogOpen = false
+	}
+	m.matchIndex = -1
+	m.updateViewportContent()
+
+	m.searchBack = false
+	for i, mt := range m.matches {
+		if mt.line == hit.line {
+			m.showMatch(i)
+			return
+		}
+	}
+	m.gotoLine(hit.line)
+}
+
+func init() {
+	registerCommand(command{
+		name:  "search",
+		usage: "<text>",
+		help:  "Search every tab and list the matches",
+		run: func(m *Model, arg string) (string, tea.Cmd, error) {
+			if arg == "" {
+				return "", nil, fmt.Errorf("usage: :search <text>")
+			}
+			return "", nil, m.searchAll(arg)
+		},
+	})
+
+	registerCommand(command{
+		name:    "nohlsearch",
+		aliases: []string{"noh"},
+		help:    "Hide search highlighting until the next search",
+		run: func(m *Model, _ string) (string, tea.Cmd, error) {
+			m.clearHighlight()
+			return "", nil, nil
+		},
+	})
+}
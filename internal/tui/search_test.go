@@ -0,0 +1,138 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+/**
+ * Types a search prompt key, the pattern and enter.
+ */
+func runSearch(m Model, prompt string, pattern string) Model {
+	m = typeKeys(m, prompt)
+	return pressKeys(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(pattern)}, keyEnter)
+}
+
+/**
+ * Tests smartcase matching.
+ */
+func TestFindInLine(t *testing.T) {
+	tests := []struct {
+		text, pattern string
+		want          int
+	}{
+		{"Go, go, GO", "go", 3},
+		{"Go, go, GO", "Go", 1},
+		{"aaaa", "aa", 2},
+		{"Ünïcode ünïcode", "ünï", 2},
+		{"nothing", "", 0},
+	}
+	for _, tt := range tests {
+		if got := findInLine(tt.text, tt.pattern); len(got) != tt.want {
+			t.Errorf("findInLine(%q, %q) = %v, want %d matches", tt.text, tt.pattern, got, tt.want)
+		}
+	}
+}
+
+/**
+ * Tests highlighting keeps the line's own styles and text.
+ */
+func TestHighlightLine(t *testing.T) {
+	line := "\x1b[1mHello\x1b[0m \x1b]8;;https://example.com\x07world\x1b]8;;\x07!"
+	out := highlightLine(line, []match{{start: 3, end: 8}}, -1)
+
+	if ansi.Strip(out) != ansi.Strip(line) {
+		t.Errorf("Expected the same text, got %q", ansi.Strip(out))
+	}
+	if !strings.Contains(out, "Hel"+matchStart+"lo\x1b[0m"+matchStart) {
+		t.Errorf("Expected the highlight to start at l and survive the reset, got %q", out)
+	}
+	if !strings.Contains(out, "wo"+matchEnd+"rld") || !strings.Contains(out, "https://example.com\x07") {
+		t.Errorf("Expected the highlight to end after wo and keep the link, got %q", out)
+	}
+
+	out = highlightLine("ab ab", []match{{start: 0, end: 2}, {start: 3, end: 5}}, 1)
+	if out != matchStart+"ab"+matchEnd+" "+currentStart+"ab"+currentEnd {
+		t.Errorf("Expected the second match marked current, got %q", out)
+	}
+}
+
+/**
+ * Tests /, n and N move between matches, wrap and show a counter.
+ */
+func TestSearch_NextPrev(t *testing.T) {
+	m := runSearch(motionModel(), "/", "section")
+	if len(m.matches) != 5 || m.matchIndex != 0 || m.cursorLine != 0 {
+		t.Fatalf("Expected the first of 5 matches, got %d of %d", m.matchIndex, len(m.matches))
+	}
+	if !strings.Contains(m.viewport.View(), currentStart+"Section"+currentEnd) {
+		t.Error("Expected the current match highlighted")
+	}
+
+	m = typeKeys(m, "2 n")
+	if m.matchIndex != 2 || m.cursorLine != 40 || !strings.Contains(ansi.Strip(m.viewport.View()), "Section 2") {
+		t.Errorf("Expected 2n on Section 2, got match %d on line %d", m.matchIndex, m.cursorLine)
+	}
+	if status := m.renderStatsBar(); !strings.Contains(status, "/section [3/5]") {
+		t.Errorf("Expected a match counter, got %q", status)
+	}
+
+	m = typeKeys(m, "N N N")
+	if m.matchIndex != 4 || m.message != "search hit TOP, continuing at BOTTOM" {
+		t.Errorf("Expected N to wrap to the last match, got %d (%q)", m.matchIndex, m.message)
+	}
+
+	// ? searches upwards, so n goes up
+	m = runSearch(m, "?", "line 1")
+	if m.matchIndex < 0 || m.matches[m.matchIndex].line >= 80 {
+		t.Errorf("Expected ? to find a match above, got line %d", m.cursorLine)
+	}
+
+	m = typeKeys(m, "esc")
+	if m.highlight || strings.Contains(m.viewport.View(), matchStart) {
+		t.Error("Expected Esc to hide the highlighting")
+	}
+
+	m = runSearch(m, "/", "missing")
+	if !m.messageErr || !strings.Contains(m.message, "pattern not found") {
+		t.Errorf("Expected pattern not found, got %q", m.message)
+	}
+}
+
+/**
+ * Tests searching every tab lists hits in posts and other tabs, and
+ * opens the chosen one.
+ */
+func TestSearch_AllTabs(t *testing.T) {
+	m := testBlogModel(3)
+	m.tabs[0].Posts[2].Content = "intro\n\nthe needle is here"
+	m.tabs = append(m.tabs, Tab{Name: "About", Content: "no\nneedle"})
+	m.SetSize(80, 30)
+
+	m = runSearch(m, "g /", "needle")
+	if !m.showResults || len(m.results) != 2 {
+		t.Fatalf("Expected 2 hits, got %+v", m.results)
+	}
+	if !strings.Contains(m.content, "Blog › Post 2, line") || !strings.Contains(m.content, "About, line 2") {
+		t.Errorf("Expected the hits' locations, got %q", ansi.Strip(m.content))
+	}
+
+	m = pressKeys(m, keyEnter)
+	post, ok := m.openPost()
+	if m.showResults || !ok || post.Title != "Post 2" || m.matchIndex < 0 {
+		t.Fatalf("Expected Post 2 open on the match, got %q", post.Title)
+	}
+
+	m = typeKeys(m, "n")
+	if m.activeTab != 0 || m.matchIndex != 0 {
+		t.Errorf("Expected n to stay in the post, got tab %d match %d", m.activeTab, m.matchIndex)
+	}
+
+	m = runCommand(m, "search j-q-z")
+	if !m.messageErr || m.showResults {
+		t.Errorf("Expected an error when no tab matches, got %q", m.message)
+	}
+}
@@ -1,8 +1,10 @@
 package tui
 
 import (
+	"fmt"
 	"os"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/muesli/termenv"
 )
@@ -14,7 +16,7 @@ func init() {
 	os.Setenv("COLORTERM", "truecolor")
 }
 
-// Tokyo Night color palette
+// Tokyo Night color palette (the dark theme)
 const (
 	colorBackground = "#1a1b26"
 
@@ -23,48 +25,329 @@ const (
 
 	colorBorder = "#414868" // Borders, dividers
 	colorMuted  = "#565f89" // Dim text
+
+	colorGreen = "#9ece6a" // Additions
+	colorRed   = "#f7768e" // Removals
 )
 
-var (
-	// Header style (ASCII art container)
-	headerStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color(colorAccent)).
+/**
+ * A colour scheme for the interface. Markdown pages are rendered with
+ * the glamour style of the same name.
+ */
+type Theme struct {
+	Name    string
+	Glamour string // Glamour standard style for markdown pages ("dark" or "light")
+
+	Background string
+	Accent     string // Headings, active tab
+	Highlight  string // Links, emphasis
+	Border     string // Borders, dividers
+	Muted      string // Dim text
+	Green      string // Additions
+	Red        string // Removals, errors
+}
+
+// Selectable themes; the first is the default
+var themes = []Theme{
+	{
+		Name: "dark", Glamour: "dark",
+		Background: colorBackground, Accent: colorAccent, Highlight: colorHighlight,
+		Border: colorBorder, Muted: colorMuted, Green: colorGreen, Red: colorRed,
+	},
+	{
+		// Tokyo Night Day
+		Name: "light", Glamour: "light",
+		Background: "#e1e2e7", Accent: "#2e7de9", Highlight: "#9854f1",
+		Border: "#a8aecb", Muted: "#6172b0", Green: "#587539", Red: "#f52a65",
+	},
+}
+
+/**
+ * Finds a theme by name.
+ * @return Theme, and false if there is none by that name
+ */
+func themeByName(name string) (Theme, bool) {
+	for _, t := range themes {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Theme{}, false
+}
+
+/**
+ * Styles of the interface for one theme.
+ */
+type styles struct {
+	header      lipgloss.Style // ASCII art container
+	tabBar      lipgloss.Style // Tab bar container
+	activeTab   lipgloss.Style
+	inactiveTab lipgloss.Style
+	helpBar     lipgloss.Style // Help bar (bottom)
+	statsBar    lipgloss.Style
+	notice      lipgloss.Style // Degraded-mode warning inside the stats bar
+	toast       lipgloss.Style // Confirmation above the stats bar, e.g. after a copy
+	pendingKeys lipgloss.Style // Partial key sequence (count or g/z prefix) at the start of the help bar
+
+	commandPrompt lipgloss.Style // ":" command line
+	commandError  lipgloss.Style // Error from a ":" command
+
+	splash         lipgloss.Style // Splash logo
+	splashSubtitle lipgloss.Style
+	splashSkip     lipgloss.Style
+
+	changeBadge   lipgloss.Style // Marker on tabs changed since the visitor's last session
+	changeAdded   lipgloss.Style // "What's new" lines for added, updated and removed sections
+	changeUpdated lipgloss.Style
+	changeRemoved lipgloss.Style
+
+	imageCaption lipgloss.Style // Caption under (or in place of) an image
+
+	projectHeader lipgloss.Style // Projects catalogue summary line
+	projectCard   lipgloss.Style // Project card container
+	projectTitle  lipgloss.Style // Project name inside a card
+	projectMeta   lipgloss.Style // Project status and date line
+	projectTag    lipgloss.Style // Tech tag chip
+	projectLink   lipgloss.Style // Repository link
+
+	postTitle    lipgloss.Style // Blog post title in the list
+	postSelected lipgloss.Style // Selected post title, and the title of an open post
+	postSummary  lipgloss.Style // Post summary under the title
+
+	outline       lipgloss.Style // Outline sidebar container
+	outlineTitle  lipgloss.Style // "Contents" above the outline
+	outlineItem   lipgloss.Style // Heading in the outline
+	outlineActive lipgloss.Style // Heading of the section being read
+
+	scrollTrack lipgloss.Style // Scrollbar beside the viewport
+	scrollThumb lipgloss.Style // Part of the scrollbar showing what is in view
+}
+
+// Styles of each theme, built once
+var themeStyles = make(map[string]*styles)
+
+func init() {
+	for _, t := range themes {
+		themeStyles[t.Name] = newStyles(t)
+	}
+}
+
+/**
+ * Builds the styles for a theme.
+ */
+func newStyles(t Theme) *styles {
+	return &styles{
+		header: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(t.Accent)).
 			Bold(true).
-			Align(lipgloss.Center)
+			Align(lipgloss.Center),
 
-	// Tab bar container
-	tabBarStyle = lipgloss.NewStyle().
+		tabBar: lipgloss.NewStyle().
 			BorderStyle(lipgloss.NormalBorder()).
-			BorderForeground(lipgloss.Color(colorBorder)).
+			BorderForeground(lipgloss.Color(t.Border)).
 			BorderBottom(true).
-			Padding(0, 1)
+			Padding(0, 1),
 
-	// Active tab style
-	activeTabStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color(colorAccent)).
-			Background(lipgloss.Color(colorBorder)).
+		activeTab: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(t.Accent)).
+			Background(lipgloss.Color(t.Border)).
 			Bold(true).
-			Padding(0, 2)
+			Padding(0, 2),
 
-	// Inactive tab style
-	inactiveTabStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color(colorMuted)).
-				Padding(0, 2)
+		inactiveTab: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(t.Muted)).
+			Padding(0, 2),
 
-	// Help bar style (bottom)
-	helpBarStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color(colorMuted)).
-			Background(lipgloss.Color(colorBackground)).
+		helpBar: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(t.Muted)).
+			Background(lipgloss.Color(t.Background)).
 			BorderStyle(lipgloss.NormalBorder()).
-			BorderForeground(lipgloss.Color(colorBorder)).
+			BorderForeground(lipgloss.Color(t.Border)).
 			BorderTop(true).
-			Padding(0, 1)
+			Padding(0, 1),
 
-	// Stats bar style
-	statsBarStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color(colorHighlight)).
+		statsBar: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(t.Highlight)).
 			BorderStyle(lipgloss.NormalBorder()).
-			BorderForeground(lipgloss.Color(colorBorder)).
+			BorderForeground(lipgloss.Color(t.Border)).
 			BorderTop(true).
-			Padding(0, 1)
-)
+			Padding(0, 1),
+
+		notice: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(t.Accent)).
+			Bold(true),
+
+		toast: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(t.Highlight)).
+			Background(lipgloss.Color(t.Border)).
+			Padding(0, 1),
+
+		pendingKeys: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(t.Accent)).
+			Bold(true),
+
+		commandPrompt: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(t.Accent)).
+			Bold(true),
+
+		commandError: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(t.Red)),
+
+		splash: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(t.Accent)).
+			Bold(true).
+			Align(lipgloss.Center),
+
+		splashSubtitle: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(t.Highlight)).
+			Italic(true).
+			Align(lipgloss.Center),
+
+		splashSkip: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(t.Muted)).
+			Align(lipgloss.Center),
+
+		changeBadge:   lipgloss.NewStyle().Foreground(lipgloss.Color(t.Highlight)),
+		changeAdded:   lipgloss.NewStyle().Foreground(lipgloss.Color(t.Green)),
+		changeUpdated: lipgloss.NewStyle().Foreground(lipgloss.Color(t.Highlight)),
+		changeRemoved: lipgloss.NewStyle().Foreground(lipgloss.Color(t.Red)),
+
+		imageCaption: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(t.Muted)).
+			Italic(true),
+
+		projectHeader: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(t.Muted)).
+			Italic(true),
+
+		projectCard: lipgloss.NewStyle().
+			BorderStyle(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color(t.Border)).
+			Padding(0, 1),
+
+		projectTitle: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(t.Accent)).
+			Bold(true),
+
+		projectMeta: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(t.Muted)),
+
+		projectTag: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(t.Background)).
+			Background(lipgloss.Color(t.Highlight)).
+			Padding(0, 1),
+
+		projectLink: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(t.Highlight)).
+			Underline(true),
+
+		postTitle: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(t.Accent)),
+
+		postSelected: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(t.Highlight)).
+			Bold(true),
+
+		postSummary: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(t.Muted)).
+			PaddingLeft(2),
+
+		outline: lipgloss.NewStyle().
+			BorderStyle(lipgloss.NormalBorder()).
+			BorderForeground(lipgloss.Color(t.Border)).
+			BorderLeft(true).
+			PaddingLeft(1),
+
+		outlineTitle: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(t.Accent)).
+			Bold(true),
+
+		outlineItem: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(t.Muted)),
+
+		outlineActive: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(t.Highlight)).
+			Bold(true),
+
+		scrollTrack: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(t.Border)),
+
+		scrollThumb: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(t.Accent)),
+	}
+}
+
+// QR codes are drawn light-on-dark with explicit colours so they
+// scan the same whatever the terminal's own palette or theme
+var qrStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("#ffffff")).
+	Background(lipgloss.Color("#000000"))
+
+/**
+ * Re-renders the tabs for a theme's glamour style.
+ * @param style - Glamour standard style ("dark" or "light")
+ * @return Tabs rendered in that style
+ * @return error if the content could not be loaded
+ */
+type ThemeLoader func(style string) ([]Tab, error)
+
+/**
+ * Registers the function that re-renders markdown pages when the
+ * visitor changes theme. Without one, ":theme" only recolours the
+ * interface around the pages.
+ * Must be called before the program starts.
+ */
+func (m *Model) SetThemeLoader(load ThemeLoader) {
+	m.themeLoader = load
+}
+
+func init() {
+	registerCommand(command{
+		name:  "theme",
+		usage: "[name]",
+		help:  "Switch colour scheme, or show the current one",
+		complete: func(Model) []string {
+			var names []string
+			for _, t := range themes {
+				names = append(names, t.Name)
+			}
+			return names
+		},
+		run: func(m *Model, arg string) (string, tea.Cmd, error) {
+			if arg == "" {
+				return "theme " + m.theme.Name, nil, nil
+			}
+			t, ok := themeByName(arg)
+			if !ok {
+				return "", nil, fmt.Errorf("unknown theme: %s", arg)
+			}
+			cmd, err := m.setTheme(t)
+			if err != nil {
+				return "", nil, fmt.Errorf("theme %s, but pages could not be re-rendered: %w", t.Name, err)
+			}
+			return "theme " + t.Name, cmd, nil
+		},
+	})
+}
+
+/**
+ * Switches the session's theme, re-rendering pages if the glamour
+ * style changes and a theme loader is set.
+ * @return Commands from starting the re-rendered tabs' panels
+ * @return error if the pages could not be re-rendered; the interface
+ *         still switches theme
+ */
+func (m *Model) setTheme(t Theme) (tea.Cmd, error) {
+	old := m.theme
+	m.theme, m.styles = t, themeStyles[t.Name]
+
+	var err error
+	if m.themeLoader != nil && old.Glamour != t.Glamour {
+		var tabs []Tab
+		if tabs, err = m.themeLoader(t.Glamour); err == nil {
+			return m.replaceTabs(tabs), nil
+		}
+	}
+	m.refreshView()
+	return nil, err
+}
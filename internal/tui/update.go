@@ -1,16 +1,28 @@
 package tui
 
 import (
+	"strings"
+
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
 )
 
 /**
  * Handles all user input and system events.
  * @param msg - Message from Bubble Tea (keypress, window resize, etc.)
  * @return Updated model and optional command
- * @effects Updates model state based on message type
+ * @effects Updates model state based on message type, and starts the
+ *          panel of a tab opened for the first time
  */
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	m, cmd := m.update(msg)
+	return m, tea.Batch(cmd, m.startPanel())
+}
+
+/**
+ * Handles a message for Update.
+ */
+func (m Model) update(msg tea.Msg) (Model, tea.Cmd) {
 	var cmd tea.Cmd
 
 	switch msg := msg.(type) {
@@ -19,93 +31,258 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.showSplash = false
 		return m, nil
 
+	case reloadMsg:
+		// Scheduled content is due to change
+		return m, m.runReload()
+
+	case toastTimeoutMsg:
+		// A toast has been up long enough, unless a newer one replaced it.
+		// The copy it confirms has been sent by now; sending it again
+		// with later frames would overwrite what the visitor copied since
+		if msg.seq == m.toastSeq {
+			m.toast, m.clipboard = "", ""
+		}
+		return m, nil
+
+	case keyTimeoutMsg:
+		// A partial key sequence was left waiting
+		if action, count := m.keys.timeout(msg.seq); action != actionNone {
+			m.runMotion(action, count)
+		}
+		return m, nil
+
 	case tea.KeyMsg:
 		// Allow any key to skip splash screen
 		if m.showSplash {
 			m.showSplash = false
 			return m, nil
 		}
-		switch msg.String() {
-		// Quit
-		case "q", "ctrl+c":
-			return m, tea.Quit
 
-		// Tab navigation (using Tab key for now, gt/gT will be added later for true Vim motions)
-		case "tab", "l", "right":
-			m.activeTab++
-			if m.activeTab >= len(m.tabs) {
-				m.activeTab = 0
-			}
-			m.updateViewportContent()
+		// The last command's output lasts until the next key
+		m.message, m.messageErr, m.clipboard = "", false, ""
+		if m.cmdlineOpen {
+			return m, m.updateCmdline(msg)
+		}
+		if m.showFinder {
+			return m, m.updateFinder(msg)
+		}
+		if m.showLink && m.updateLinkInfo(msg) {
+			return m, nil
+		}
 
-		case "shift+tab", "h", "left":
-			m.activeTab--
-			if m.activeTab < 0 {
-				m.activeTab = len(m.tabs) - 1
+		// Interactive panels get every key but tab switching and ctrl+c,
+		// which work the same in every keymap so a visitor cannot get stuck
+		if p := m.activePanel(); p != nil && p.Interactive() && !m.overlayOpen() {
+			switch msg.String() {
+			case "tab":
+				m.switchTab(m.activeTab + 1)
+				return m, nil
+			case "shift+tab":
+				m.switchTab(m.activeTab - 1)
+				return m, nil
+			case "ctrl+c":
+			default:
+				cmd = p.Update(msg)
+				m.refreshPanel()
+				return m, cmd
 			}
-			m.updateViewportContent()
-
-		// Scrolling
-		case "j", "down":
-			m.viewport, cmd = m.viewport.Update(msg)
-			return m, cmd
+		}
 
-		case "k", "up":
-			m.viewport, cmd = m.viewport.Update(msg)
-			return m, cmd
+		// Ctrl+C quits whatever the keymap says
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		return m, m.updateKeys(msg)
 
-		case "d", "ctrl+d":
-			m.viewport.PageDown()
+	case tea.MouseMsg:
+		m.updateMouse(msg)
+		return m, nil
 
-		case "u", "ctrl+u":
-			m.viewport.PageUp()
+	case tea.WindowSizeMsg:
+		// Terminal was resized
+		m.SetSize(msg.Width, msg.Height)
+		// Panels never share the row with the outline
+		cmd = m.updatePanels(PanelSizeMsg{Width: m.width - 4, Height: m.viewport.Height})
 
-		case "g":
-			m.viewport.GotoTop()
+	default:
+		// Messages from panels (program output, timers)
+		cmd = m.updatePanels(msg)
+	}
 
-		case "G":
-			m.viewport.GotoBottom()
+	return m, cmd
+}
 
-		// Toggle help
-		case "?":
-			m.showHelp = !m.showHelp
+/**
+ * Updates viewport content when tab changes.
+ * @effects Sets viewport content to current tab's content
+ */
+func (m *Model) updateViewportContent() {
+	if m.activeTab >= 0 && m.activeTab < len(m.tabs) {
+		if m.seenTabs != nil {
+			m.seenTabs[m.tabs[m.activeTab].Name] = true
+		}
+		if m.width > 0 {
+			m.viewport.Width = m.width - 4 - m.outlineWidth()
 		}
 
-	case tea.WindowSizeMsg:
-		// Terminal was resized
-		m.width = msg.Width
-		m.height = msg.Height
+		var content string
+		content, m.placed, m.headingRows = m.renderContent()
+		m.links, m.linkFocus = locateLinks(content, m.currentLinks()), -1
+		m.visual = false
+		m.viewGeneration++
+		m.setContent(content)
+		m.viewport.GotoTop()
+		m.cursorLine = 0
+		m.jumpLines = m.contentJumpLines(content)
+		m.outlineFocus = false
+	}
+}
 
-		if !m.ready {
-			m.ready = true
+/**
+ * Renders what the viewport shows for the current tab and overlay.
+ * @return Content, the graphics images placed in it, and the line of
+ *         each of currentHeadings (-1 where not found)
+ */
+func (m Model) renderContent() (string, []placedImage, []int) {
+	switch {
+	case m.showKeys:
+		return m.wrapContent(m.renderKeyHelp()), nil, nil
+	case m.whatsNew:
+		return m.wrapContent(m.renderWhatsNew()), nil, nil
+	case m.showContact:
+		return m.wrapContent(m.renderContact()), nil, nil
+	case m.showResults:
+		return m.wrapContent(m.renderResults()), nil, nil
+	case m.showFinder:
+		return m.renderFinder(), nil, nil
+	case m.showLink:
+		return m.wrapContent(m.renderLinkInfo()), nil, nil
+	}
+	if p := m.activePanel(); p != nil {
+		return m.wrapContent(p.View()), nil, nil
+	}
+	if m.onProjectsTab() {
+		return m.wrapContent(m.renderProjects(m.viewport.Width)), nil, nil
+	}
+	if m.onBlogTab() {
+		content, wrapped := m.wrapLines(m.renderBlog())
+		post, ok := m.openPost()
+		if !ok {
+			return content, nil, nil
 		}
+		content, codes := m.layoutQRCodes(content, post.QRCodes)
+		content, placed, images := m.layoutImages(content, post.Images)
+		return content, placed, headingRows(post.Content, post.Headings, postHeaderLines, wrapped, codes, images)
+	}
 
-		// Recalculate viewport dimensions
-		// Reserve space for: header (3), tabs (3), stats (2), help (2), padding (2)
-		headerHeight := 3
-		tabHeight := 3
-		statsHeight := 2
-		helpHeight := 2
-		if !m.showHelp {
-			helpHeight = 0
-		}
+	tab := m.tabs[m.activeTab]
+	content, wrapped := m.wrapLines(tab.Content)
+	content, codes := m.layoutQRCodes(content, tab.QRCodes)
+	content, placed, images := m.layoutImages(content, tab.Images)
+	return content, placed, headingRows(tab.Content, tab.Headings, 0, wrapped, codes, images)
+}
 
-		m.viewport.Width = msg.Width - 4
-		m.viewport.Height = msg.Height - headerHeight - tabHeight - statsHeight - helpHeight - 2
+/**
+ * Returns the headings of the page in the viewport: the tab's, or the
+ * open post's. Overlays, panels and lists have none.
+ */
+func (m Model) currentHeadings() []Heading {
+	if m.overlayOpen() || m.activePanel() != nil || m.activeTab < 0 || m.activeTab >= len(m.tabs) {
+		return nil
+	}
+	if m.onBlogTab() {
+		post, _ := m.openPost()
+		return post.Headings
+	}
+	if m.onProjectsTab() {
+		return nil
+	}
+	return m.tabs[m.activeTab].Headings
+}
+
+/**
+ * Replaces the viewport content, highlighting search matches and the
+ * focused link in it.
+ * @param content - Rendered content, without highlighting
+ */
+func (m *Model) setContent(content string) {
+	m.content = content
+	m.findMatches()
+	m.viewport.SetContent(m.highlightSelection(m.highlightLink(m.highlightMatches())))
+}
+
+/**
+ * Whether an overlay (key help, what's new, contact code, search
+ * results, finder, link) replaces the tab's content.
+ */
+func (m Model) overlayOpen() bool {
+	return m.showKeys || m.whatsNew || m.showContact || m.showResults || m.showFinder || m.showLink
+}
+
+/**
+ * Closes any overlay. The caller re-renders the viewport.
+ */
+func (m *Model) closeOverlays() {
+	m.showKeys, m.whatsNew, m.showContact, m.showResults, m.showFinder = false, false, false, false, false
+	m.showLink = false
+}
 
-		m.updateViewportContent()
+/**
+ * Re-renders the viewport content, keeping the scroll position, after
+ * a change to how it is drawn (theme, wrapping).
+ */
+func (m *Model) refreshView() {
+	offset, cursor, focus := m.viewport.YOffset, m.cursorLine, m.linkFocus
+	m.updateViewportContent()
+	m.viewport.SetYOffset(offset)
+	m.cursorLine = cursor
+	if focus >= 0 && focus < len(m.links) {
+		m.linkFocus = focus
+		m.redrawMatches()
 	}
+}
 
-	return m, cmd
+/**
+ * Wraps long lines to the viewport width when ":set wrap" is on.
+ * Placeholder lines for images and QR codes are short, so tab content
+ * is wrapped before they are laid out.
+ */
+func (m Model) wrapContent(content string) string {
+	content, _ = m.wrapLines(content)
+	return content
 }
 
 /**
- * Updates viewport content when tab changes.
- * @effects Sets viewport content to current tab's content
+ * Wraps content as wrapContent does.
+ * @return Content, and where each line starts after wrapping
  */
-func (m *Model) updateViewportContent() {
-	if m.activeTab >= 0 && m.activeTab < len(m.tabs) {
-		m.viewport.SetContent(m.tabs[m.activeTab].Content)
-		m.viewport.GotoTop()
+func (m Model) wrapLines(content string) (string, lineMap) {
+	if !m.wrap || m.viewport.Width <= 0 {
+		return content, nil
+	}
+	lines := strings.Split(content, "\n")
+	rows := make(lineMap, len(lines))
+	var out []string
+	for i, line := range lines {
+		rows[i] = len(out)
+		out = append(out, strings.Split(ansi.Wrap(line, m.viewport.Width, ""), "\n")...)
+	}
+	return strings.Join(out, "\n"), rows
+}
+
+/**
+ * Finds the lines { and } move between in new viewport content: the
+ * tab's headings when it has them, otherwise paragraph starts.
+ */
+func (m Model) contentJumpLines(content string) []int {
+	var lines []int
+	for _, row := range m.headingRows {
+		if row >= 0 {
+			lines = append(lines, row)
+		}
+	}
+	if len(lines) > 0 {
+		return lines
 	}
+	return paragraphLines(content)
 }
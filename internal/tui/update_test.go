@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"strings"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -85,24 +86,24 @@ func TestUpdate_Quit(t *testing.T) {
 }
 
 /**
- * Tests help toggle.
+ * Tests F1 opens and closes the key help.
  */
 func TestUpdate_HelpToggle(t *testing.T) {
-	m := NewModel([]Tab{}, "test")
+	m := NewModel([]Tab{{Name: "About", Content: "about"}}, "test")
 	m.showSplash = false // Disable splash for testing
-	initialHelp := m.showHelp
+	m.SetSize(80, 40)
 
 	// Toggle help
-	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyF1})
 	m = updatedModel.(Model)
-	if m.showHelp == initialHelp {
-		t.Error("Help should have toggled")
+	if !m.showKeys || !strings.Contains(m.viewport.View(), "Scroll down a line") {
+		t.Error("Help should have opened")
 	}
 
 	// Toggle again
-	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyF1})
 	m = updatedModel.(Model)
-	if m.showHelp != initialHelp {
-		t.Error("Help should have toggled back")
+	if m.showKeys || !strings.Contains(m.viewport.View(), "about") {
+		t.Error("Help should have closed")
 	}
 }
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 /**
@@ -32,9 +33,16 @@ func (m Model) View() string {
 	b.WriteString(m.renderTabBar())
 	b.WriteString("\n\n")
 
-	// Viewport content
-	b.WriteString(m.viewport.View())
-	b.WriteString("\n\n")
+	// Viewport content, with the scrollbar and outline beside it
+	body := m.overlayImages(m.viewport.View())
+	if m.scrollbarVisible() {
+		body = lipgloss.JoinHorizontal(lipgloss.Top, body, " ", m.renderScrollbar())
+	}
+	if m.outlineVisible() {
+		body = lipgloss.JoinHorizontal(lipgloss.Top, body, m.renderOutline())
+	}
+	b.WriteString(body)
+	b.WriteString("\n" + m.renderToast() + "\n")
 
 	// Stats bar
 	b.WriteString(m.renderStatsBar())
@@ -57,7 +65,7 @@ func (m Model) renderHeader() string {
 		"║        ADAM'S  SSH  PORTFOLIO        ║\n" +
 		"╚══════════════════════════════════════╝"
 
-	return headerStyle.Width(m.width).Render(title)
+	return m.styles.header.Width(m.width).Render(title)
 }
 
 /**
@@ -65,20 +73,31 @@ func (m Model) renderHeader() string {
  * @return Styled tab bar string
  */
 func (m Model) renderTabBar() string {
+	tabBar := lipgloss.JoinHorizontal(lipgloss.Top, m.tabLabels()...)
+	return m.styles.tabBar.Width(m.width).Render(tabBar)
+}
+
+/**
+ * Renders each tab's label, as the tab bar lines them up.
+ * @return Styled labels in tab order
+ */
+func (m Model) tabLabels() []string {
 	var tabs []string
 
 	for i, tab := range m.tabs {
 		var style lipgloss.Style
 		if i == m.activeTab {
-			style = activeTabStyle
+			style = m.styles.activeTab
 		} else {
-			style = inactiveTabStyle
+			style = m.styles.inactiveTab
 		}
-		tabs = append(tabs, style.Render(tab.Name))
+		name := tab.Name
+		if m.tabChanged(tab.Name) {
+			name += m.styles.changeBadge.Render(" ●")
+		}
+		tabs = append(tabs, style.Render(name))
 	}
-
-	tabBar := lipgloss.JoinHorizontal(lipgloss.Top, tabs...)
-	return tabBarStyle.Width(m.width).Render(tabBar)
+	return tabs
 }
 
 /**
@@ -86,19 +105,47 @@ func (m Model) renderTabBar() string {
  * @return Styled stats bar string
  */
 func (m Model) renderStatsBar() string {
+	// Sent with the bar so the terminal sets its clipboard on this frame
+	clipboard := m.clipboard
+
+	if line := m.renderCmdline(); line != "" {
+		line = ansi.Truncate(line, m.width-2, "…")
+		return clipboard + m.styles.statsBar.Width(m.width).Render(line)
+	}
+
 	uptime := time.Since(m.startTime)
 	hours := int(uptime.Hours())
 	minutes := int(uptime.Minutes()) % 60
 
 	stats := fmt.Sprintf("⏱ %dh %dm • Session: %s", hours, minutes, m.sessionID)
-	return statsBarStyle.Width(m.width).Render(stats)
+	if m.contentVersion != "" {
+		stats += " • Content: " + m.contentVersion
+	}
+	if search := m.searchStatus(); search != "" {
+		stats += " • " + search
+	}
+	if m.notice != "" {
+		stats += " • " + m.styles.notice.Render("⚠ "+m.notice)
+	}
+	return clipboard + m.styles.statsBar.Width(m.width).Render(stats)
 }
 
 /**
- * Renders the help bar with keybindings.
+ * Renders the help bar from the session's keymap.
  * @return Styled help bar string
  */
 func (m Model) renderHelpBar() string {
-	help := "Tab/h/l: navigate  •  j/k: scroll  •  g/G: top/bottom  •  ?: help  •  q: quit"
-	return helpBarStyle.Width(m.width).Render(help)
+	var help string
+	if p := m.activePanel(); p != nil && p.Interactive() && !m.overlayOpen() {
+		help = "Keys go to the program  •  Tab/Shift+Tab: switch tab  •  Ctrl+C: quit"
+	} else if m.showFinder {
+		help = "Type to filter  •  ↑/↓: select  •  Enter: go  •  Esc: close"
+	} else {
+		items := m.helpItems()
+		if keys := m.keys.pending(); keys != "" {
+			items = append([]string{m.styles.pendingKeys.Render(keys)}, items...)
+		}
+		help = joinHelp(items, m.width-2)
+	}
+	return m.styles.helpBar.Width(m.width).Render(help)
 }
@@ -0,0 +1,161 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Highlighting of the lines selected in visual mode
+const (
+	visualStart = "\x1b[7m"
+	visualEnd   = "\x1b[27m"
+)
+
+/**
+ * Starts selecting lines from the cursor line, or leaves visual mode
+ * if it is on.
+ * @effects Shows an error in the status area over overlays
+ */
+func (m *Model) toggleVisual() {
+	if m.visual {
+		m.endVisual()
+		return
+	}
+	if m.overlayOpen() {
+		m.message, m.messageErr = "nothing to select here", true
+		return
+	}
+
+	line := m.cursorLine
+	if !m.lineInView(line) {
+		line = m.viewport.YOffset
+	}
+	m.visual, m.visualAnchor, m.visualCursor = true, line, line
+	m.redrawMatches()
+}
+
+/**
+ * Leaves visual mode and clears the selection.
+ */
+func (m *Model) endVisual() {
+	if m.visual {
+		m.visual = false
+		m.redrawMatches()
+	}
+}
+
+/**
+ * The selected lines, first to last.
+ */
+func (m Model) visualRange() (int, int) {
+	return min(m.visualAnchor, m.visualCursor), max(m.visualAnchor, m.visualCursor)
+}
+
+/**
+ * Handles motions in visual mode, which move the end of the selection
+ * and scroll just enough to keep it in view.
+ * @param action - Action from the keymap
+ * @param count - Count typed before it (0 = none)
+ * @return true if the action was consumed
+ */
+func (m *Model) updateVisual(action keyAction, count int) bool {
+	if !m.visual {
+		return false
+	}
+
+	times := max(count, 1)
+	last := max(m.viewport.TotalLineCount()-1, 0)
+	line := m.visualCursor
+	switch action {
+	case actionDown:
+		line += times
+	case actionUp:
+		line -= times
+	case actionHalfDown:
+		line += times * max(m.viewport.Height/2, 1)
+	case actionHalfUp:
+		line -= times * max(m.viewport.Height/2, 1)
+	case actionPageDown:
+		line += times * m.viewport.Height
+	case actionPageUp:
+		line -= times * m.viewport.Height
+	case actionTop:
+		line = max(count-1, 0)
+	case actionBottom:
+		line = last
+		if count > 0 {
+			line = count - 1
+		}
+	default:
+		return false
+	}
+
+	line = min(max(line, 0), last)
+	switch {
+	case line < m.viewport.YOffset:
+		m.viewport.SetYOffset(line)
+	case line >= m.viewport.YOffset+m.viewport.Height:
+		m.viewport.SetYOffset(line - m.viewport.Height + 1)
+	}
+	m.visualCursor, m.cursorLine = line, line
+	m.redrawMatches()
+	return true
+}
+
+/**
+ * Highlights the lines selected in visual mode, up to the end of
+ * their text.
+ * @param content - Viewport content, possibly with other highlighting
+ * @return Content with the selection marked
+ */
+func (m Model) highlightSelection(content string) string {
+	if !m.visual {
+		return content
+	}
+	first, last := m.visualRange()
+	lines := strings.Split(content, "\n")
+	for i := first; i <= last && i < len(lines); i++ {
+		width := len([]rune(strings.TrimRight(ansi.Strip(lines[i]), " ")))
+		lines[i] = markSpans(lines[i], []match{{line: i, start: 0, end: width}}, func(int) (string, string) {
+			return visualStart, visualEnd
+		})
+	}
+	return strings.Join(lines, "\n")
+}
+
+/**
+ * Copies what the yank key points at: the lines selected in visual
+ * mode, the URL shown in the link overlay, or the focused link's URL.
+ * @return Command that hides the confirmation
+ * @effects Shows an error in the status area if nothing is selected
+ */
+func (m *Model) yank() tea.Cmd {
+	switch {
+	case m.visual:
+		first, last := m.visualRange()
+		lines := strings.Split(m.content, "\n")
+		text := plainText(strings.Join(lines[first:min(last+1, len(lines))], "\n"))
+		m.endVisual()
+		what := "1 line"
+		if n := last - first + 1; n > 1 {
+			what = fmt.Sprintf("%d lines", n)
+		}
+		return m.copyToClipboard(text, what)
+	case m.showLink:
+		text := strings.TrimPrefix(m.openLink.URL, "mailto:")
+		return m.copyToClipboard(text, text)
+	}
+	if l, ok := m.focusedLink(); ok {
+		if !isExternal(l.URL) {
+			m.message, m.messageErr = "links to pages here have nothing to copy", true
+			return nil
+		}
+		text := strings.TrimPrefix(l.URL, "mailto:")
+		return m.copyToClipboard(text, text)
+	}
+	m.message, m.messageErr = fmt.Sprintf("nothing to copy: %s selects lines", m.keyChoice(actionVisual)), true
+	return nil
+}
@@ -0,0 +1,52 @@
+package tui
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+/**
+ * Tests visual mode selects lines from the cursor, scrolls to follow
+ * the selection and copies it without styling.
+ */
+func TestVisual(t *testing.T) {
+	m := typeKeys(motionModel(), "y")
+	if !m.messageErr || m.clipboard != "" {
+		t.Errorf("Expected an error with nothing selected, got %q", m.message)
+	}
+
+	m = typeKeys(m, "5 j v j j")
+	if !m.visual || m.visualAnchor != 5 || m.visualCursor != 7 {
+		t.Fatalf("Expected lines 5-7 selected, got %d-%d", m.visualAnchor, m.visualCursor)
+	}
+	if !strings.Contains(m.viewport.View(), visualStart+"line 6") {
+		t.Error("Expected the selection to be highlighted")
+	}
+
+	m = typeKeys(m, "y")
+	want := base64.StdEncoding.EncodeToString([]byte("line 5\nline 6\nline 7\n"))
+	if m.visual || !strings.Contains(m.clipboard, want) || m.toast != "Copied 3 lines" {
+		t.Errorf("Expected the lines copied with a toast, got %q", m.toast)
+	}
+	if !strings.Contains(m.View(), "✓ Copied 3 lines") {
+		t.Error("Expected the toast in the view")
+	}
+	updated, _ := m.Update(toastTimeoutMsg{seq: m.toastSeq})
+	if m = updated.(Model); m.toast != "" {
+		t.Error("Expected the toast to time out")
+	}
+	if m.clipboard != "" || strings.Contains(m.View(), want) {
+		t.Error("Expected the clipboard sequence sent only until the toast times out")
+	}
+
+	// The selection keeps its end in view
+	m = typeKeys(m, "v G")
+	if m.visualCursor != 99 || m.viewport.YOffset != 100-m.viewport.Height {
+		t.Errorf("Expected G to select to the last line, got %d at offset %d", m.visualCursor, m.viewport.YOffset)
+	}
+	m = typeKeys(m, "esc")
+	if m.visual || strings.Contains(m.viewport.View(), visualStart) {
+		t.Error("Expected Esc to clear the selection")
+	}
+}